@@ -9,7 +9,11 @@ import { LevelUpModal } from "@/components/ui/quiz/levelUpModal";
 import { QuizFinishedScreen } from "@/components/ui/quiz/quizFinishedScreen";
 import { QuizLoadingSkeleton } from "@/components/ui/quiz/quizLoadingSkeleton";
 import { QuizView } from "@/components/ui/quiz/quizView";
-import { GetTaskByIdForUser, SubmitAnswerForTask } from "@/lib/api/task";
+import {
+  GetQuestionHint,
+  GetTaskByIdForUser,
+  SubmitAnswerForTask,
+} from "@/lib/api/task";
 import { Task, UserAnswer } from "@/lib/types/task";
 import { useParams, useSearchParams } from "next/navigation";
 
@@ -106,28 +110,22 @@ export default function CoursePage() {
     })();
   }, [user, id, isPracticeMode, setUser]);
 
-  const handleUseHint = () => {
+  const handleUseHint = async () => {
     if (isHintUsed || !task) return;
 
     setIsHintUsed(true);
     const questions = task.task_questions || [];
     const currentQuestion = questions[currentQuestionIndex];
-    const correctAnswer = currentQuestion.correct_answer;
+    const hint = await GetQuestionHint(currentQuestion.ID);
+    if (!hint) return;
 
-    if (currentQuestion.type === "QUIZ") {
+    if (currentQuestion.type === "QUIZ" && hint.eliminated_option) {
       const originalOptions: string[] = currentQuestion.options;
-      const incorrectOptionToRemove = originalOptions.find(
-        (opt) => opt !== correctAnswer
+      setHintedOptions(
+        originalOptions.filter((opt) => opt !== hint.eliminated_option)
       );
-      if (incorrectOptionToRemove) {
-        setHintedOptions(
-          originalOptions.filter((opt) => opt !== incorrectOptionToRemove)
-        );
-      }
-    } else if (currentQuestion.type === "FILL_BLANK") {
-      if (correctAnswer && correctAnswer.length > 0) {
-        setCurrentAnswer(correctAnswer.charAt(0));
-      }
+    } else if (currentQuestion.type === "FILL_BLANK" && hint.first_character) {
+      setCurrentAnswer(hint.first_character);
     }
   };
 
@@ -146,7 +144,7 @@ export default function CoursePage() {
     let isTaskCompleted = false;
 
     if (isPracticeMode) {
-      const correctAnswer = currentQuestion.correct_answer;
+      const correctAnswer = currentQuestion.correct_answer ?? "";
       isCorrect = stringsEqualFold(currentAnswer, correctAnswer);
 
       if (isCorrect) {
@@ -1,4 +1,4 @@
-import { Task } from "@/lib/types/task";
+import { QuestionHint, Task } from "@/lib/types/task";
 import { User } from "@/lib/types/user";
 
 export const GetAllTasksForUser = async (
@@ -101,3 +101,30 @@ export async function SubmitAnswerForTask(
     return null;
   }
 }
+
+export async function GetQuestionHint(
+  questionId: number
+): Promise<QuestionHint | null> {
+  try {
+    const response = await fetch(
+      `${process.env.NEXT_PUBLIC_API_URL}/inventory/hints`,
+      {
+        method: "POST",
+        headers: {
+          "Content-Type": "application/json",
+        },
+        credentials: "include",
+        body: JSON.stringify({ questionId }),
+      }
+    );
+
+    if (response.ok) {
+      return (await response.json()) as QuestionHint;
+    }
+
+    return null;
+  } catch (error) {
+    console.error("Error fetching question hint:", error);
+    return null;
+  }
+}
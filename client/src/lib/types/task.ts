@@ -27,9 +27,17 @@ export interface TaskQuestion {
   question_text: string;
   type: "QUIZ" | "CODE" | "FILL_BLANK";
   options: string[];
-  correct_answer: string;
-  test_input: string;
-  expected_output: string;
+  is_revealed: boolean;
+  correct_answer?: string;
+  test_input?: string;
+  expected_output?: string;
+  explanation?: string;
+}
+
+export interface QuestionHint {
+  question_id: number;
+  eliminated_option?: string;
+  first_character?: string;
 }
 
 export interface UserTaskProgress {
@@ -1,36 +1,289 @@
+// Command app runs the CodeQuest API.
+//
+//	app serve [-addr :5000]        run the HTTP server and background jobs (default command)
+//	app migrate [up|down|status]   apply, roll back (-steps N) or list SQL migrations
+//	app seed -profile demo [-force] reset the database and load a seed profile
+//	app reset [-force]             truncate all data tables
+//
+// seed and reset refuse to run when the database holds users that are not demo accounts,
+// unless -force is passed.
 package main
 
 import (
+	"context"
+	"flag"
+	"fmt"
 	"log/slog"
 	"os"
+	"strings"
+	"time"
+	// Embed the timezone database so per-user timezones work in minimal containers.
+	_ "time/tzdata"
 
 	"github.com/Suplice/CodeQuest/config"
 	"github.com/Suplice/CodeQuest/internal/database"
+	"github.com/Suplice/CodeQuest/internal/events"
+	"github.com/Suplice/CodeQuest/internal/grading"
+	"github.com/Suplice/CodeQuest/internal/jobs"
+	"github.com/Suplice/CodeQuest/internal/ranking"
+	"github.com/Suplice/CodeQuest/internal/repositories"
+	"github.com/Suplice/CodeQuest/internal/sandbox"
 	"github.com/Suplice/CodeQuest/internal/seed"
 	"github.com/Suplice/CodeQuest/internal/server"
+	"github.com/Suplice/CodeQuest/internal/services"
+	"gorm.io/gorm"
 )
 
 func main() {
-	cfg := config.LoadConfig()	
+	command := "serve"
+	args := os.Args[1:]
+	if len(args) > 0 {
+		command, args = args[0], args[1:]
+	}
+
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+
+	var err error
+	switch command {
+	case "serve":
+		err = runServe(args, logger)
+	case "migrate":
+		err = runMigrate(args)
+	case "seed":
+		err = runSeed(args, logger)
+	case "reset":
+		err = runReset(args)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q, expected serve, migrate, seed or reset\n", command)
+		os.Exit(2)
+	}
+
+	if err != nil {
+		logger.Error("Command failed", "command", command, "err", err)
+		os.Exit(1)
+	}
+}
+
+func connect() (*gorm.DB, error) {
+	cfg := config.LoadConfig()
+	return database.Connect(cfg.DatabaseURL)
+}
+
+func runServe(args []string, logger *slog.Logger) error {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	addr := fs.String("addr", ":5000", "address to listen on")
+	migrate := fs.Bool("migrate", true, "apply database migrations before starting")
+	runJobs := fs.Bool("jobs", true, "run background jobs such as the streak reset")
+	fs.Parse(args)
+
+	db, err := connect()
+	if err != nil {
+		return err
+	}
+	defer database.Close(db)
+
+	if *migrate {
+		if err := database.Migrate(db); err != nil {
+			return err
+		}
+	}
+
+	// Never serve code tasks from a host that cannot isolate submissions.
+	if err := sandbox.NewRunner(sandbox.DefaultLimits(), logger).Check(); err != nil && os.Getenv("SANDBOX_OPTIONAL") != "true" {
+		return fmt.Errorf("code sandbox unavailable, set SANDBOX_OPTIONAL=true to start without code execution: %w", err)
+	}
+
+	rankingStore, err := newRankingStore(*runJobs, logger)
+	if err != nil {
+		return err
+	}
+
+	if *runJobs {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		newScheduler(db, rankingStore, logger).Start(ctx)
+	}
+
+	server := server.NewServer(db, logger, rankingStore)
+	return server.Run(*addr)
+}
+
+// newRankingStore keeps leaderboards in Redis when REDIS_ADDR is set. Without Redis there is
+// no cache and every board is ranked in SQL, since replicas caching in their own memory would
+// serve different rankings. RANKING_CACHE=memory caches in process memory anyway, which is only
+// correct for a single process that also runs the jobs.
+func newRankingStore(runJobs bool, logger *slog.Logger) (ranking.Store, error) {
+	if addr := os.Getenv("REDIS_ADDR"); addr != "" {
+		return ranking.NewRedisStore(addr, os.Getenv("REDIS_PASSWORD")), nil
+	}
+	if os.Getenv("RANKING_CACHE") != "memory" {
+		logger.Info("REDIS_ADDR not set, ranking leaderboards in SQL")
+		return nil, nil
+	}
+	if !runJobs {
+		return nil, fmt.Errorf("RANKING_CACHE=memory needs the background jobs in the same process")
+	}
+	logger.Warn("Caching leaderboards in process memory; run a single API process or set REDIS_ADDR")
+	return ranking.NewMemoryStore(), nil
+}
+
+func newScheduler(db *gorm.DB, rankingStore ranking.Store, logger *slog.Logger) *jobs.Scheduler {
+	// Duel payouts and refunds made by the jobs still move the cached boards.
+	dispatcher := events.NewDispatcher(logger)
+	var rankingService *services.RankingService
+	if rankingStore != nil {
+		rankingService = services.NewRankingService(rankingStore, repositories.NewLeaderboardRepository(db, logger), logger)
+		for _, t := range services.RankingEvents {
+			dispatcher.Subscribe(t, rankingService.HandleEvent)
+		}
+	}
+	streakService := services.NewStreakService(repositories.NewStreakRepository(db, logger), repositories.NewShopRepository(db, logger), dispatcher, logger)
+	seasonService := services.NewSeasonService(repositories.NewSeasonRepository(db, logger), repositories.NewLeaderboardRepository(db, logger), logger)
+	duelService := services.NewDuelService(repositories.NewDuelRepository(db, logger), grading.NewDefaultRegistry(), dispatcher, logger)
+	leagueService := services.NewLeagueService(repositories.NewLeagueRepository(db, logger), repositories.NewLeaderboardRepository(db, logger), logger)
+
+	scheduler := jobs.NewScheduler(logger)
+	scheduler.Add(jobs.Job{
+		Name:     "streak-reset",
+		Interval: time.Hour,
+		Run: func(ctx context.Context) error {
+			_, err := streakService.ResetLapsedStreaks(ctx)
+			return err
+		},
+	})
+	scheduler.Add(jobs.Job{
+		Name:     "season-archive",
+		Interval: time.Hour,
+		Run: func(ctx context.Context) error {
+			_, err := seasonService.ArchiveEndedSeasons(ctx)
+			return err
+		},
+	})
+	scheduler.Add(jobs.Job{
+		Name:     "league-finalize",
+		Interval: time.Hour,
+		Run: func(ctx context.Context) error {
+			_, err := leagueService.FinalizeEndedWeeks(ctx)
+			return err
+		},
+	})
+	scheduler.Add(jobs.Job{
+		Name:     "duel-expiry",
+		Interval: 5 * time.Minute,
+		Run: func(ctx context.Context) error {
+			_, err := duelService.ExpireDuels(ctx)
+			return err
+		},
+	})
+	if rankingService != nil {
+		scheduler.Add(jobs.Job{
+			Name:     "ranking-rebuild",
+			Interval: services.RankingRebuildInterval,
+			Run: func(ctx context.Context) error {
+				_, err := rankingService.Rebuild(ctx)
+				return err
+			},
+		})
+	}
+	return scheduler
+}
 
-	db, err := database.Connect(cfg.DatabaseURL)
+func runMigrate(args []string) error {
+	direction := "up"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		direction, args = args[0], args[1:]
+	}
+
+	fs := flag.NewFlagSet("migrate", flag.ExitOnError)
+	steps := fs.Int("steps", 1, "number of migrations to roll back with down")
+	fs.Parse(args)
+
+	db, err := connect()
+	if err != nil {
+		return err
+	}
 	defer database.Close(db)
 
+	migrator, err := database.NewMigrator(db)
 	if err != nil {
-		panic(err)
-	} 
+		return err
+	}
 
-	err = database.Migrate(db)
+	switch direction {
+	case "up":
+		applied, err := migrator.Up()
+		for _, m := range applied {
+			fmt.Printf("applied %04d_%s\n", m.Version, m.Name)
+		}
+		if err == nil && len(applied) == 0 {
+			fmt.Println("database is up to date")
+		}
+		return err
+	case "down":
+		if *steps < 1 {
+			return fmt.Errorf("-steps must be at least 1")
+		}
+		rolledBack, err := migrator.Down(*steps)
+		for _, m := range rolledBack {
+			fmt.Printf("rolled back %04d_%s\n", m.Version, m.Name)
+		}
+		return err
+	case "status":
+		statuses, err := migrator.Status()
+		if err != nil {
+			return err
+		}
+		for _, s := range statuses {
+			state := "pending"
+			if s.AppliedAt != nil {
+				state = "applied " + s.AppliedAt.Format(time.RFC3339)
+			}
+			if s.Missing {
+				state += " (file missing)"
+			}
+			fmt.Printf("%04d_%-40s %s\n", s.Version, s.Name, state)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown migrate direction %q, expected up, down or status", direction)
+	}
+}
+
+func runSeed(args []string, logger *slog.Logger) error {
+	fs := flag.NewFlagSet("seed", flag.ExitOnError)
+	profile := fs.String("profile", "", "seed profile to load (demo)")
+	force := fs.Bool("force", false, "truncate even if the database contains non-demo users")
+	fs.Parse(args)
+
+	if *profile == "" {
+		return fmt.Errorf("-profile is required")
+	}
+
+	db, err := connect()
 	if err != nil {
-		panic(err)
+		return err
+	}
+	defer database.Close(db)
+
+	if err := database.Migrate(db); err != nil {
+		return err
 	}
+	return seed.Seed(db, logger, *profile, *force)
+}
+
+func runReset(args []string) error {
+	fs := flag.NewFlagSet("reset", flag.ExitOnError)
+	force := fs.Bool("force", false, "truncate even if the database contains non-demo users")
+	fs.Parse(args)
 
-	err = seed.SeedTestData(db)
+	db, err := connect()
 	if err != nil {
-		panic(err)
+		return err
 	}
+	defer database.Close(db)
 
-	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
-	server := server.NewServer(db, logger)
-	server.Run(":5000")
-}
\ No newline at end of file
+	if err := database.Migrate(db); err != nil {
+		return err
+	}
+	return seed.Reset(db, *force)
+}
@@ -0,0 +1,136 @@
+// Command content imports and exports content bundles.
+//
+//	content import -file bundle.yaml [-dry-run]
+//	content export [-file bundle.yaml] [-format yaml|json] [-name name]
+//
+// Importing is idempotent: tasks, questions and badges are matched by slug, and -dry-run
+// prints the diff without writing anything.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+
+	"github.com/Suplice/CodeQuest/config"
+	"github.com/Suplice/CodeQuest/internal/content"
+	"github.com/Suplice/CodeQuest/internal/database"
+	"github.com/Suplice/CodeQuest/internal/grading"
+	"github.com/Suplice/CodeQuest/internal/repositories"
+	"github.com/Suplice/CodeQuest/internal/services"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "import":
+		err = runImport(os.Args[2:])
+	case "export":
+		err = runExport(os.Args[2:])
+	default:
+		usage()
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: content import -file <bundle> [-dry-run]")
+	fmt.Fprintln(os.Stderr, "       content export [-file <bundle>] [-format yaml|json] [-name <name>]")
+}
+
+func newContentService() (*services.ContentService, func(), error) {
+	cfg := config.LoadConfig()
+
+	db, err := database.Connect(cfg.DatabaseURL)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := database.Migrate(db); err != nil {
+		database.Close(db)
+		return nil, nil, err
+	}
+
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	contentRepo := repositories.NewContentRepository(db, logger)
+	service := services.NewContentService(contentRepo, grading.NewDefaultRegistry(), logger)
+	return service, func() { database.Close(db) }, nil
+}
+
+func runImport(args []string) error {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	file := fs.String("file", "", "bundle to import (.yaml, .yml or .json)")
+	dryRun := fs.Bool("dry-run", false, "print the changes without applying them")
+	fs.Parse(args)
+
+	if *file == "" {
+		return fmt.Errorf("-file is required")
+	}
+
+	bundle, err := content.Load(*file)
+	if err != nil {
+		return err
+	}
+
+	service, closeDB, err := newContentService()
+	if err != nil {
+		return err
+	}
+	defer closeDB()
+
+	diff, err := service.Import(bundle, *dryRun)
+	if err != nil {
+		return err
+	}
+
+	diff.Print(os.Stdout)
+	if *dryRun {
+		fmt.Println("dry run, nothing was written")
+	}
+	return nil
+}
+
+func runExport(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	file := fs.String("file", "", "output file, stdout when empty")
+	format := fs.String("format", "", "yaml or json, defaults to the file extension")
+	name := fs.String("name", "codequest", "bundle name written to the metadata")
+	fs.Parse(args)
+
+	if *format == "" {
+		*format = content.FormatFromPath(*file)
+	}
+
+	service, closeDB, err := newContentService()
+	if err != nil {
+		return err
+	}
+	defer closeDB()
+
+	bundle, err := service.Export(content.Metadata{Name: *name})
+	if err != nil {
+		return err
+	}
+
+	var out io.Writer = os.Stdout
+	if *file != "" {
+		f, err := os.Create(*file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+	return content.Write(out, bundle, *format)
+}
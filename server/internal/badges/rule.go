@@ -0,0 +1,166 @@
+package badges
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	MetricCompletedTasks    = "completed_tasks"
+	MetricPerfectTasks      = "perfect_tasks"
+	MetricLanguageCompleted = "language_completed"
+	MetricStreak            = "streak"
+	MetricLevel             = "level"
+	MetricXP                = "xp"
+)
+
+var ErrInvalidRule = errors.New("invalid badge rule")
+
+// metrics maps every supported metric to whether it takes an argument in brackets.
+var metrics = map[string]bool{
+	MetricCompletedTasks:    false,
+	MetricPerfectTasks:      false,
+	MetricLanguageCompleted: true,
+	MetricStreak:            false,
+	MetricLevel:             false,
+	MetricXP:                false,
+}
+
+var conditionPattern = regexp.MustCompile(`^([a-z_]+)(?:\[([^\]]+)\])?\s*(>=|<=|==|>|<)\s*(\d+)$`)
+
+// Stats is the snapshot of a user's achievements that rules are evaluated against.
+type Stats struct {
+	CompletedTasks    int
+	PerfectTasks      int
+	Streak            int
+	Level             int
+	XP                int
+	LanguageCompleted map[string]int
+}
+
+type Condition struct {
+	Metric string
+	Arg    string
+	Op     string
+	Value  int
+}
+
+// Rule is a badge requirement such as "completed_tasks >= 10" or
+// "language_completed[Go] >= 5 && perfect_tasks >= 3". All conditions must hold.
+type Rule struct {
+	Conditions []Condition
+}
+
+func ParseRule(s string) (*Rule, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, fmt.Errorf("%w: rule is empty", ErrInvalidRule)
+	}
+
+	rule := &Rule{}
+	for _, part := range strings.Split(s, "&&") {
+		part = strings.TrimSpace(part)
+		match := conditionPattern.FindStringSubmatch(part)
+		if match == nil {
+			return nil, fmt.Errorf("%w: cannot parse %q", ErrInvalidRule, part)
+		}
+
+		takesArg, ok := metrics[match[1]]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown metric %q", ErrInvalidRule, match[1])
+		}
+		arg := strings.TrimSpace(match[2])
+		if takesArg && arg == "" {
+			return nil, fmt.Errorf("%w: %s needs an argument, e.g. %s[Go]", ErrInvalidRule, match[1], match[1])
+		}
+		if !takesArg && arg != "" {
+			return nil, fmt.Errorf("%w: %s does not take an argument", ErrInvalidRule, match[1])
+		}
+
+		value, err := strconv.Atoi(match[4])
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidRule, err)
+		}
+		rule.Conditions = append(rule.Conditions, Condition{Metric: match[1], Arg: arg, Op: match[3], Value: value})
+	}
+	return rule, nil
+}
+
+func (r *Rule) Evaluate(stats Stats) bool {
+	for _, c := range r.Conditions {
+		if !c.Holds(stats) {
+			return false
+		}
+	}
+	return true
+}
+
+// Current returns the user's value for the condition's metric.
+func (c Condition) Current(stats Stats) int {
+	switch c.Metric {
+	case MetricCompletedTasks:
+		return stats.CompletedTasks
+	case MetricPerfectTasks:
+		return stats.PerfectTasks
+	case MetricLanguageCompleted:
+		return stats.LanguageCompleted[strings.ToLower(c.Arg)]
+	case MetricStreak:
+		return stats.Streak
+	case MetricLevel:
+		return stats.Level
+	case MetricXP:
+		return stats.XP
+	}
+	return 0
+}
+
+func (c Condition) Holds(stats Stats) bool {
+	current := c.Current(stats)
+	switch c.Op {
+	case ">=":
+		return current >= c.Value
+	case ">":
+		return current > c.Value
+	case "==":
+		return current == c.Value
+	case "<=":
+		return current <= c.Value
+	case "<":
+		return current < c.Value
+	}
+	return false
+}
+
+// Target is the smallest value of the metric that satisfies the condition. ok is false for
+// conditions that do not count upwards (==, <= and <), which have no meaningful progress.
+func (c Condition) Target() (target int, ok bool) {
+	switch c.Op {
+	case ">=":
+		return c.Value, true
+	case ">":
+		return c.Value + 1, true
+	}
+	return 0, false
+}
+
+// Bottleneck returns the unmet condition the user is furthest from, which is what a progress
+// indicator should show. ok is false when every condition holds or none can be measured.
+func (r *Rule) Bottleneck(stats Stats) (condition Condition, ok bool) {
+	best := 2.0
+	for _, c := range r.Conditions {
+		if c.Holds(stats) {
+			continue
+		}
+		target, measurable := c.Target()
+		if !measurable || target <= 0 {
+			continue
+		}
+		ratio := float64(c.Current(stats)) / float64(target)
+		if ratio < best {
+			best, condition, ok = ratio, c, true
+		}
+	}
+	return condition, ok
+}
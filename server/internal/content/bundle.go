@@ -0,0 +1,138 @@
+package content
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Suplice/CodeQuest/internal/grading"
+	"gopkg.in/yaml.v3"
+)
+
+// CurrentVersion is the bundle format version written by the exporter. Bundles with a
+// higher version are rejected so an old binary never half-imports content it does not understand.
+const CurrentVersion = 1
+
+const (
+	FormatYAML = "yaml"
+	FormatJSON = "json"
+)
+
+var (
+	ErrUnsupportedVersion = errors.New("unsupported bundle version")
+	ErrUnknownFormat      = errors.New("unknown bundle format")
+)
+
+type Bundle struct {
+	Version  int         `json:"version" yaml:"version"`
+	Metadata Metadata    `json:"metadata" yaml:"metadata"`
+	Tasks    []TaskSpec  `json:"tasks" yaml:"tasks"`
+	Badges   []BadgeSpec `json:"badges,omitempty" yaml:"badges,omitempty"`
+}
+
+type Metadata struct {
+	Name        string     `json:"name" yaml:"name"`
+	Description string     `json:"description,omitempty" yaml:"description,omitempty"`
+	Author      string     `json:"author,omitempty" yaml:"author,omitempty"`
+	ExportedAt  *time.Time `json:"exported_at,omitempty" yaml:"exported_at,omitempty"`
+}
+
+type TaskSpec struct {
+	Slug        string         `json:"slug" yaml:"slug"`
+	Title       string         `json:"title" yaml:"title"`
+	Description string         `json:"description,omitempty" yaml:"description,omitempty"`
+	Type        string         `json:"type" yaml:"type"`
+	Language    string         `json:"language" yaml:"language"`
+	Difficulty  string         `json:"difficulty" yaml:"difficulty"`
+	Points      int            `json:"points" yaml:"points"`
+	XP          int            `json:"xp" yaml:"xp"`
+	IsActive    *bool          `json:"is_active,omitempty" yaml:"is_active,omitempty"`
+	Questions   []QuestionSpec `json:"questions" yaml:"questions"`
+}
+
+// QuestionSpec describes a single question. Its slug only has to be unique within the task,
+// and the order of questions in the bundle becomes their position.
+type QuestionSpec struct {
+	Slug           string          `json:"slug" yaml:"slug"`
+	Text           string          `json:"text" yaml:"text"`
+	Options        []string        `json:"options,omitempty" yaml:"options,omitempty"`
+	CorrectAnswer  string          `json:"correct_answer,omitempty" yaml:"correct_answer,omitempty"`
+	TestInput      string          `json:"test_input,omitempty" yaml:"test_input,omitempty"`
+	ExpectedOutput string          `json:"expected_output,omitempty" yaml:"expected_output,omitempty"`
+	Explanation    string          `json:"explanation,omitempty" yaml:"explanation,omitempty"`
+	Grading        *grading.Config `json:"grading,omitempty" yaml:"grading,omitempty"`
+}
+
+type BadgeSpec struct {
+	Slug        string `json:"slug" yaml:"slug"`
+	Name        string `json:"name" yaml:"name"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	IconURL     string `json:"icon_url,omitempty" yaml:"icon_url,omitempty"`
+	Requirement string `json:"requirement,omitempty" yaml:"requirement,omitempty"`
+}
+
+// FormatFromPath picks the bundle format from a file extension, defaulting to YAML.
+func FormatFromPath(path string) string {
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		return FormatJSON
+	}
+	return FormatYAML
+}
+
+func Load(path string) (*Bundle, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data, FormatFromPath(path))
+}
+
+func Parse(data []byte, format string) (*Bundle, error) {
+	var bundle Bundle
+	switch format {
+	case FormatYAML:
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(&bundle); err != nil {
+			return nil, fmt.Errorf("could not parse bundle: %w", err)
+		}
+	case FormatJSON:
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&bundle); err != nil {
+			return nil, fmt.Errorf("could not parse bundle: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownFormat, format)
+	}
+
+	if bundle.Version < 1 || bundle.Version > CurrentVersion {
+		return nil, fmt.Errorf("%w: %d (this build supports up to %d)", ErrUnsupportedVersion, bundle.Version, CurrentVersion)
+	}
+	return &bundle, nil
+}
+
+func Write(w io.Writer, bundle *Bundle, format string) error {
+	switch format {
+	case FormatYAML:
+		encoder := yaml.NewEncoder(w)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(bundle); err != nil {
+			return err
+		}
+		return encoder.Close()
+	case FormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		encoder.SetEscapeHTML(false)
+		return encoder.Encode(bundle)
+	default:
+		return fmt.Errorf("%w: %q", ErrUnknownFormat, format)
+	}
+}
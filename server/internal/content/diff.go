@@ -0,0 +1,69 @@
+package content
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	KindTask     = "task"
+	KindQuestion = "question"
+	KindBadge    = "badge"
+)
+
+const (
+	ActionCreate    = "create"
+	ActionUpdate    = "update"
+	ActionDelete    = "delete"
+	ActionUnchanged = "unchanged"
+)
+
+// Change is one line of an import diff. Slug is "<task>/<question>" for questions.
+type Change struct {
+	Kind   string   `json:"kind"`
+	Slug   string   `json:"slug"`
+	Action string   `json:"action"`
+	Fields []string `json:"fields,omitempty"`
+}
+
+type Diff struct {
+	DryRun  bool     `json:"dry_run"`
+	Changes []Change `json:"changes"`
+}
+
+func (d *Diff) Add(kind, slug, action string, fields ...string) {
+	d.Changes = append(d.Changes, Change{Kind: kind, Slug: slug, Action: action, Fields: fields})
+}
+
+// Count returns how many changes of the given action the diff contains.
+func (d *Diff) Count(action string) int {
+	count := 0
+	for _, c := range d.Changes {
+		if c.Action == action {
+			count++
+		}
+	}
+	return count
+}
+
+func (d *Diff) HasChanges() bool {
+	return d.Count(ActionUnchanged) != len(d.Changes)
+}
+
+// Print writes a human readable diff, leaving out unchanged entries.
+func (d *Diff) Print(w io.Writer) {
+	symbols := map[string]string{ActionCreate: "+", ActionUpdate: "~", ActionDelete: "-"}
+	for _, c := range d.Changes {
+		if c.Action == ActionUnchanged {
+			continue
+		}
+		line := fmt.Sprintf("%s %-8s %s", symbols[c.Action], c.Kind, c.Slug)
+		if len(c.Fields) > 0 {
+			line += " (" + strings.Join(c.Fields, ", ") + ")"
+		}
+		fmt.Fprintln(w, line)
+	}
+	fmt.Fprintf(w, "%d to create, %d to update, %d to delete, %d unchanged\n",
+		d.Count(ActionCreate), d.Count(ActionUpdate), d.Count(ActionDelete), d.Count(ActionUnchanged))
+}
@@ -0,0 +1,23 @@
+package content
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	slugReplacer = strings.NewReplacer("#", "sharp", "+", "plus")
+	slugInvalid  = regexp.MustCompile(`[^a-z0-9]+`)
+	slugPattern  = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+)
+
+// Slugify turns a title such as "C# - Syntax Basics" into "csharp-syntax-basics".
+func Slugify(title string) string {
+	slug := slugReplacer.Replace(strings.ToLower(strings.TrimSpace(title)))
+	slug = slugInvalid.ReplaceAllString(slug, "-")
+	return strings.Trim(slug, "-")
+}
+
+func IsValidSlug(slug string) bool {
+	return len(slug) <= 255 && slugPattern.MatchString(slug)
+}
@@ -0,0 +1,54 @@
+package controllers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Suplice/CodeQuest/internal/services"
+	"github.com/Suplice/CodeQuest/internal/utils/constants"
+	"github.com/gin-gonic/gin"
+)
+
+type ActivityController struct {
+	service *services.ActivityService
+	logger  *slog.Logger
+}
+
+func NewActivityController(service *services.ActivityService, logger *slog.Logger) *ActivityController {
+	return &ActivityController{service: service, logger: logger}
+}
+
+// GetMyActivity serves GET /me/activity?cursor=&limit=&type=task_completed,level_up
+func (ac *ActivityController) GetMyActivity(ctx *gin.Context) {
+	userID := ctx.GetUint64("userID")
+	if userID == 0 {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": constants.ErrUnauthorized})
+		return
+	}
+
+	limit, _ := strconv.Atoi(ctx.Query("limit"))
+	page, err := ac.service.GetTimeline(uint(userID), ctx.Query("cursor"), limit, queryTypes(ctx))
+	if err != nil {
+		if errors.Is(err, services.ErrValidation) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch activity"})
+		return
+	}
+	ctx.JSON(http.StatusOK, page)
+}
+
+// queryTypes reads the comma-separated type filter of activity listings.
+func queryTypes(ctx *gin.Context) []string {
+	var types []string
+	for _, t := range strings.Split(ctx.Query("type"), ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			types = append(types, t)
+		}
+	}
+	return types
+}
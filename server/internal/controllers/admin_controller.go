@@ -1,21 +1,29 @@
 package controllers
 
 import (
+	"errors"
 	"log/slog"
 	"net/http"
 	"strconv"
 
+	"github.com/Suplice/CodeQuest/internal/dto"
+	"github.com/Suplice/CodeQuest/internal/leveling"
+	"github.com/Suplice/CodeQuest/internal/repositories"
 	"github.com/Suplice/CodeQuest/internal/services"
 	"github.com/gin-gonic/gin"
 )
 
 type AdminController struct {
-	adminService *services.AdminService
-	logger       *slog.Logger
+	adminService      *services.AdminService
+	levelingService   *services.LevelingService
+	seasonService     *services.SeasonService
+	shopService       *services.ShopService
+	multiplierService *services.MultiplierService
+	logger            *slog.Logger
 }
 
-func NewAdminController(logger *slog.Logger, adminService *services.AdminService) *AdminController {
-	return &AdminController{adminService: adminService, logger: logger}
+func NewAdminController(logger *slog.Logger, adminService *services.AdminService, levelingService *services.LevelingService, seasonService *services.SeasonService, shopService *services.ShopService, multiplierService *services.MultiplierService) *AdminController {
+	return &AdminController{adminService: adminService, levelingService: levelingService, seasonService: seasonService, shopService: shopService, multiplierService: multiplierService, logger: logger}
 }
 
 func (ac *AdminController) DeleteUser(c *gin.Context) {
@@ -71,4 +79,439 @@ func (ac *AdminController) GetAllUsers(c *gin.Context) {
 		return
 	}
 	c.JSON(http.StatusOK, users)
-}
\ No newline at end of file
+}
+func (ac *AdminController) GetAllTasks(c *gin.Context) {
+	tasks, err := ac.adminService.GetAllTasks()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch tasks"})
+		return
+	}
+	c.JSON(http.StatusOK, tasks)
+}
+
+func (ac *AdminController) GetTask(c *gin.Context) {
+	taskID, ok := parseIDParam(c, "id", "Invalid task ID")
+	if !ok {
+		return
+	}
+
+	task, err := ac.adminService.GetTask(taskID)
+	if err != nil {
+		ac.respondContentError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, task)
+}
+
+func (ac *AdminController) CreateTask(c *gin.Context) {
+	var payload dto.TaskUpsertDTO
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		return
+	}
+
+	task, err := ac.adminService.CreateTask(payload)
+	if err != nil {
+		ac.respondContentError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, task)
+}
+
+func (ac *AdminController) UpdateTask(c *gin.Context) {
+	taskID, ok := parseIDParam(c, "id", "Invalid task ID")
+	if !ok {
+		return
+	}
+
+	var payload dto.TaskUpsertDTO
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		return
+	}
+
+	task, err := ac.adminService.UpdateTask(taskID, payload)
+	if err != nil {
+		ac.respondContentError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, task)
+}
+
+func (ac *AdminController) SetTaskActive(c *gin.Context) {
+	taskID, ok := parseIDParam(c, "id", "Invalid task ID")
+	if !ok {
+		return
+	}
+
+	var payload dto.TaskActiveDTO
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		return
+	}
+
+	if err := ac.adminService.SetTaskActive(taskID, *payload.IsActive); err != nil {
+		ac.respondContentError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Task updated successfully", "is_active": *payload.IsActive})
+}
+
+func (ac *AdminController) CreateQuestion(c *gin.Context) {
+	taskID, ok := parseIDParam(c, "id", "Invalid task ID")
+	if !ok {
+		return
+	}
+
+	var payload dto.QuestionUpsertDTO
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		return
+	}
+
+	question, err := ac.adminService.CreateQuestion(taskID, payload)
+	if err != nil {
+		ac.respondContentError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, question)
+}
+
+func (ac *AdminController) UpdateQuestion(c *gin.Context) {
+	taskID, ok := parseIDParam(c, "id", "Invalid task ID")
+	if !ok {
+		return
+	}
+	questionID, ok := parseIDParam(c, "questionId", "Invalid question ID")
+	if !ok {
+		return
+	}
+
+	var payload dto.QuestionUpsertDTO
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		return
+	}
+
+	question, err := ac.adminService.UpdateQuestion(taskID, questionID, payload)
+	if err != nil {
+		ac.respondContentError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, question)
+}
+
+func (ac *AdminController) DeleteQuestion(c *gin.Context) {
+	taskID, ok := parseIDParam(c, "id", "Invalid task ID")
+	if !ok {
+		return
+	}
+	questionID, ok := parseIDParam(c, "questionId", "Invalid question ID")
+	if !ok {
+		return
+	}
+
+	if err := ac.adminService.DeleteQuestion(taskID, questionID); err != nil {
+		ac.respondContentError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Question deleted successfully"})
+}
+
+func (ac *AdminController) ReorderQuestions(c *gin.Context) {
+	taskID, ok := parseIDParam(c, "id", "Invalid task ID")
+	if !ok {
+		return
+	}
+
+	var payload dto.ReorderQuestionsDTO
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		return
+	}
+
+	task, err := ac.adminService.ReorderQuestions(taskID, payload.QuestionIDs)
+	if err != nil {
+		ac.respondContentError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, task)
+}
+
+func (ac *AdminController) GetAllBadges(c *gin.Context) {
+	badges, err := ac.adminService.GetAllBadges()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch badges"})
+		return
+	}
+	c.JSON(http.StatusOK, badges)
+}
+
+func (ac *AdminController) CreateBadge(c *gin.Context) {
+	var payload dto.BadgeUpsertDTO
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		return
+	}
+
+	badge, err := ac.adminService.CreateBadge(payload)
+	if err != nil {
+		ac.respondContentError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, badge)
+}
+
+func (ac *AdminController) UpdateBadge(c *gin.Context) {
+	badgeID, ok := parseIDParam(c, "id", "Invalid badge ID")
+	if !ok {
+		return
+	}
+
+	var payload dto.BadgeUpsertDTO
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		return
+	}
+
+	badge, err := ac.adminService.UpdateBadge(badgeID, payload)
+	if err != nil {
+		ac.respondContentError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, badge)
+}
+
+func (ac *AdminController) DeleteBadge(c *gin.Context) {
+	badgeID, ok := parseIDParam(c, "id", "Invalid badge ID")
+	if !ok {
+		return
+	}
+
+	if err := ac.adminService.DeleteBadge(badgeID); err != nil {
+		ac.respondContentError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Badge deleted successfully"})
+}
+
+func (ac *AdminController) GetLevelingCurve(c *gin.Context) {
+	curve, err := ac.levelingService.GetCurve()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch leveling curve"})
+		return
+	}
+	c.JSON(http.StatusOK, curve)
+}
+
+func (ac *AdminController) UpdateLevelingCurve(c *gin.Context) {
+	var payload leveling.Curve
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		return
+	}
+
+	curve, recomputed, err := ac.levelingService.UpdateCurve(payload)
+	if err != nil {
+		ac.respondContentError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"curve": curve.Curve, "preview": curve.Preview, "recomputed_users": recomputed})
+}
+
+func (ac *AdminController) GetAllSeasons(c *gin.Context) {
+	seasons, err := ac.seasonService.GetSeasons()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch seasons"})
+		return
+	}
+	c.JSON(http.StatusOK, seasons)
+}
+
+func (ac *AdminController) CreateSeason(c *gin.Context) {
+	var payload dto.SeasonUpsertDTO
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		return
+	}
+
+	season, err := ac.seasonService.CreateSeason(payload)
+	if err != nil {
+		ac.respondContentError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, season)
+}
+
+func (ac *AdminController) UpdateSeason(c *gin.Context) {
+	seasonID, ok := parseIDParam(c, "id", "Invalid season ID")
+	if !ok {
+		return
+	}
+
+	var payload dto.SeasonUpsertDTO
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		return
+	}
+
+	season, err := ac.seasonService.UpdateSeason(seasonID, payload)
+	if err != nil {
+		ac.respondContentError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, season)
+}
+
+func (ac *AdminController) DeleteSeason(c *gin.Context) {
+	seasonID, ok := parseIDParam(c, "id", "Invalid season ID")
+	if !ok {
+		return
+	}
+
+	if err := ac.seasonService.DeleteSeason(seasonID); err != nil {
+		ac.respondContentError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Season deleted successfully"})
+}
+
+func (ac *AdminController) GetAllShopItems(c *gin.Context) {
+	items, err := ac.shopService.GetAllItems()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch shop items"})
+		return
+	}
+	c.JSON(http.StatusOK, items)
+}
+
+func (ac *AdminController) CreateShopItem(c *gin.Context) {
+	var payload dto.ShopItemUpsertDTO
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		return
+	}
+
+	item, err := ac.shopService.CreateItem(payload)
+	if err != nil {
+		ac.respondContentError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, item)
+}
+
+func (ac *AdminController) UpdateShopItem(c *gin.Context) {
+	itemID, ok := parseIDParam(c, "id", "Invalid shop item ID")
+	if !ok {
+		return
+	}
+
+	var payload dto.ShopItemUpsertDTO
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		return
+	}
+
+	item, err := ac.shopService.UpdateItem(itemID, payload)
+	if err != nil {
+		ac.respondContentError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, item)
+}
+
+func (ac *AdminController) DeleteShopItem(c *gin.Context) {
+	itemID, ok := parseIDParam(c, "id", "Invalid shop item ID")
+	if !ok {
+		return
+	}
+
+	if err := ac.shopService.DeleteItem(itemID); err != nil {
+		ac.respondContentError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Shop item deleted successfully"})
+}
+
+func (ac *AdminController) GetAllMultiplierEvents(c *gin.Context) {
+	events, err := ac.multiplierService.GetEvents()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch multiplier events"})
+		return
+	}
+	c.JSON(http.StatusOK, events)
+}
+
+func (ac *AdminController) CreateMultiplierEvent(c *gin.Context) {
+	var payload dto.MultiplierEventUpsertDTO
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		return
+	}
+
+	event, err := ac.multiplierService.CreateEvent(payload)
+	if err != nil {
+		ac.respondContentError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, event)
+}
+
+func (ac *AdminController) UpdateMultiplierEvent(c *gin.Context) {
+	eventID, ok := parseIDParam(c, "id", "Invalid multiplier event ID")
+	if !ok {
+		return
+	}
+
+	var payload dto.MultiplierEventUpsertDTO
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		return
+	}
+
+	event, err := ac.multiplierService.UpdateEvent(eventID, payload)
+	if err != nil {
+		ac.respondContentError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, event)
+}
+
+func (ac *AdminController) DeleteMultiplierEvent(c *gin.Context) {
+	eventID, ok := parseIDParam(c, "id", "Invalid multiplier event ID")
+	if !ok {
+		return
+	}
+
+	if err := ac.multiplierService.DeleteEvent(eventID); err != nil {
+		ac.respondContentError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Multiplier event deleted successfully"})
+}
+
+func (ac *AdminController) respondContentError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrValidation):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case err.Error() == "task not found" || err.Error() == "question not found" || err.Error() == "badge not found" || errors.Is(err, repositories.ErrSeasonNotFound) || errors.Is(err, repositories.ErrShopItemNotFound) ||
+		errors.Is(err, repositories.ErrMultiplierEventNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case err.Error() == "question order must contain every question of the task":
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, repositories.ErrShopItemPurchased):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		ac.logger.Error("Admin content operation failed", "err", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not save content"})
+	}
+}
+
+func parseIDParam(c *gin.Context, name string, message string) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param(name), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": message})
+		return 0, false
+	}
+	return uint(id), true
+}
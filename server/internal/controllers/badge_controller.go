@@ -0,0 +1,35 @@
+package controllers
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/Suplice/CodeQuest/internal/services"
+	"github.com/Suplice/CodeQuest/internal/utils/constants"
+	"github.com/gin-gonic/gin"
+)
+
+type BadgeController struct {
+	service *services.BadgeService
+	logger  *slog.Logger
+}
+
+func NewBadgeController(service *services.BadgeService, logger *slog.Logger) *BadgeController {
+	return &BadgeController{service: service, logger: logger}
+}
+
+func (bc *BadgeController) GetCatalog(ctx *gin.Context) {
+	userID := ctx.GetUint64("userID")
+	if userID == 0 {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": constants.ErrUnauthorized})
+		return
+	}
+
+	catalog, err := bc.service.GetCatalog(uint(userID))
+	if err != nil {
+		bc.logger.Error("Failed to get badge catalog", "err", err, "userID", userID)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch badges"})
+		return
+	}
+	ctx.JSON(http.StatusOK, catalog)
+}
@@ -0,0 +1,61 @@
+package controllers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/Suplice/CodeQuest/internal/repositories"
+	"github.com/Suplice/CodeQuest/internal/sandbox"
+	"github.com/Suplice/CodeQuest/internal/services"
+	"github.com/Suplice/CodeQuest/internal/utils/constants"
+	"github.com/gin-gonic/gin"
+)
+
+type CodeSubmissionController struct {
+	service *services.CodeSubmissionService
+	logger  *slog.Logger
+}
+
+func NewCodeSubmissionController(service *services.CodeSubmissionService, logger *slog.Logger) *CodeSubmissionController {
+	return &CodeSubmissionController{service: service, logger: logger}
+}
+
+type SubmitCodeRequest struct {
+	TaskID     uint   `json:"taskId" binding:"required"`
+	QuestionID uint   `json:"questionId" binding:"required"`
+	Code       string `json:"code" binding:"required,max=65536"`
+}
+
+func (csc *CodeSubmissionController) SubmitCode(ctx *gin.Context) {
+	userID64 := ctx.GetUint64("userID")
+	if userID64 == 0 {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": constants.ErrUnauthorized})
+		return
+	}
+
+	var req SubmitCodeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		return
+	}
+
+	result, err := csc.service.SubmitCode(ctx.Request.Context(), uint(userID64), req.TaskID, req.QuestionID, req.Code)
+	if err != nil {
+		switch {
+		case errors.Is(err, repositories.ErrQuestionNotFound):
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrNotCodeQuestion), errors.Is(err, sandbox.ErrUnsupportedLanguage), errors.Is(err, repositories.ErrTaskAlreadyCompleted):
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, repositories.ErrTaskInDuel):
+			ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case errors.Is(err, sandbox.ErrSandboxUnavailable):
+			ctx.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,191 @@
+package controllers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"slices"
+
+	"github.com/Suplice/CodeQuest/internal/dto"
+	"github.com/Suplice/CodeQuest/internal/models"
+	"github.com/Suplice/CodeQuest/internal/repositories"
+	"github.com/Suplice/CodeQuest/internal/services"
+	"github.com/Suplice/CodeQuest/internal/utils/constants"
+	"github.com/gin-gonic/gin"
+)
+
+type DuelController struct {
+	service *services.DuelService
+	logger  *slog.Logger
+}
+
+func NewDuelController(service *services.DuelService, logger *slog.Logger) *DuelController {
+	return &DuelController{service: service, logger: logger}
+}
+
+func (dc *DuelController) respondDuelError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrValidation):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, repositories.ErrDuelNotFound), errors.Is(err, repositories.ErrQuestionNotFound), errors.Is(err, repositories.ErrTaskNotDuelable):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrNotFriends):
+		ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, repositories.ErrInsufficientPoints), errors.Is(err, repositories.ErrDuelTaskStarted), errors.Is(err, repositories.ErrDuelNotPending), errors.Is(err, repositories.ErrDuelNotActive),
+		errors.Is(err, repositories.ErrDuelExpired), errors.Is(err, repositories.ErrDuelNotStarted),
+		errors.Is(err, repositories.ErrDuelFinishedByPlayer), errors.Is(err, repositories.ErrDuelQuestionAnswered):
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		dc.logger.Error("Duel request failed", "err", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Could not complete duel request"})
+	}
+}
+
+// CreateDuel serves POST /duels
+func (dc *DuelController) CreateDuel(ctx *gin.Context) {
+	userID := ctx.GetUint64("userID")
+	if userID == 0 {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": constants.ErrUnauthorized})
+		return
+	}
+
+	var payload dto.CreateDuelDTO
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		return
+	}
+
+	duel, err := dc.service.CreateDuel(uint(userID), payload)
+	if err != nil {
+		dc.respondDuelError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, duel)
+}
+
+// GetDuels serves GET /duels?status=
+func (dc *DuelController) GetDuels(ctx *gin.Context) {
+	userID := ctx.GetUint64("userID")
+	if userID == 0 {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": constants.ErrUnauthorized})
+		return
+	}
+
+	status := ctx.Query("status")
+	statuses := []string{models.DuelStatusPending, models.DuelStatusActive, models.DuelStatusFinished, models.DuelStatusExpired, models.DuelStatusDeclined}
+	if status != "" && !slices.Contains(statuses, status) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status"})
+		return
+	}
+
+	duels, err := dc.service.GetDuels(uint(userID), status)
+	if err != nil {
+		dc.respondDuelError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, duels)
+}
+
+// GetDuel serves GET /duels/:id. Loading an active duel starts the caller's clock.
+func (dc *DuelController) GetDuel(ctx *gin.Context) {
+	userID := ctx.GetUint64("userID")
+	if userID == 0 {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": constants.ErrUnauthorized})
+		return
+	}
+	duelID, ok := parseIDParam(ctx, "id", "Invalid duel ID")
+	if !ok {
+		return
+	}
+
+	duel, err := dc.service.GetDuel(duelID, uint(userID))
+	if err != nil {
+		dc.respondDuelError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, duel)
+}
+
+func (dc *DuelController) AcceptDuel(ctx *gin.Context) {
+	userID := ctx.GetUint64("userID")
+	if userID == 0 {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": constants.ErrUnauthorized})
+		return
+	}
+	duelID, ok := parseIDParam(ctx, "id", "Invalid duel ID")
+	if !ok {
+		return
+	}
+
+	duel, err := dc.service.AcceptDuel(duelID, uint(userID))
+	if err != nil {
+		dc.respondDuelError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, duel)
+}
+
+func (dc *DuelController) DeclineDuel(ctx *gin.Context) {
+	userID := ctx.GetUint64("userID")
+	if userID == 0 {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": constants.ErrUnauthorized})
+		return
+	}
+	duelID, ok := parseIDParam(ctx, "id", "Invalid duel ID")
+	if !ok {
+		return
+	}
+
+	duel, err := dc.service.DeclineDuel(duelID, uint(userID))
+	if err != nil {
+		dc.respondDuelError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, duel)
+}
+
+// SubmitAnswer serves POST /duels/:id/answers
+func (dc *DuelController) SubmitAnswer(ctx *gin.Context) {
+	userID := ctx.GetUint64("userID")
+	if userID == 0 {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": constants.ErrUnauthorized})
+		return
+	}
+	duelID, ok := parseIDParam(ctx, "id", "Invalid duel ID")
+	if !ok {
+		return
+	}
+
+	var payload dto.DuelAnswerDTO
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		return
+	}
+
+	result, err := dc.service.SubmitAnswer(duelID, uint(userID), payload)
+	if err != nil {
+		dc.respondDuelError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, result)
+}
+
+// GetHistory serves GET /duels/history/:friendId
+func (dc *DuelController) GetHistory(ctx *gin.Context) {
+	userID := ctx.GetUint64("userID")
+	if userID == 0 {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": constants.ErrUnauthorized})
+		return
+	}
+	friendID, ok := parseIDParam(ctx, "friendId", "Invalid friend ID")
+	if !ok {
+		return
+	}
+
+	history, err := dc.service.GetHistory(uint(userID), friendID)
+	if err != nil {
+		dc.respondDuelError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, history)
+}
@@ -0,0 +1,42 @@
+package controllers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/Suplice/CodeQuest/internal/services"
+	"github.com/Suplice/CodeQuest/internal/utils/constants"
+	"github.com/gin-gonic/gin"
+)
+
+type FeedController struct {
+	service *services.FeedService
+	logger  *slog.Logger
+}
+
+func NewFeedController(service *services.FeedService, logger *slog.Logger) *FeedController {
+	return &FeedController{service: service, logger: logger}
+}
+
+// GetFeed serves GET /feed?cursor=&limit=&type=task_completed,badge_earned
+func (fc *FeedController) GetFeed(ctx *gin.Context) {
+	userID := ctx.GetUint64("userID")
+	if userID == 0 {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": constants.ErrUnauthorized})
+		return
+	}
+
+	limit, _ := strconv.Atoi(ctx.Query("limit"))
+	page, err := fc.service.GetFeed(uint(userID), ctx.Query("cursor"), limit, queryTypes(ctx))
+	if err != nil {
+		if errors.Is(err, services.ErrValidation) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch feed"})
+		return
+	}
+	ctx.JSON(http.StatusOK, page)
+}
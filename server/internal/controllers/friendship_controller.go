@@ -1,10 +1,12 @@
 package controllers
 
 import (
+	"errors"
 	"log/slog"
 	"net/http"
 	"strconv"
 
+	"github.com/Suplice/CodeQuest/internal/repositories"
 	"github.com/Suplice/CodeQuest/internal/services"
 	"github.com/Suplice/CodeQuest/internal/utils/constants"
 	"github.com/gin-gonic/gin"
@@ -103,6 +105,10 @@ func (fc *FriendshipController) SendFriendRequest(ctx *gin.Context) {
 	if err != nil {
 		if err.Error() == "cannot add yourself as a friend" || err.Error() == "friendship already exists or request is pending" {
 			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		} else if errors.Is(err, repositories.ErrFriendRequestBlocked) {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		} else if errors.Is(err, repositories.ErrFriendRequestCooldown) {
+			ctx.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
 		} else {
 			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Could not send friend request"})
 		}
@@ -207,4 +213,134 @@ func (fc *FriendshipController) RemoveFriend(ctx *gin.Context) {
 	}
 
 	ctx.JSON(http.StatusOK, gin.H{"message": "Friend removed successfully"})
-}
\ No newline at end of file
+}
+
+type TargetUserPayload struct {
+	UserID uint `json:"userId" binding:"required"`
+}
+
+func (fc *FriendshipController) respondRestrictionError(ctx *gin.Context, err error, action string) {
+	switch {
+	case errors.Is(err, repositories.ErrBlockSelf), errors.Is(err, repositories.ErrMuteSelf), errors.Is(err, repositories.ErrUserBlocked):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, repositories.ErrUserNotFound), errors.Is(err, repositories.ErrUserNotBlocked), errors.Is(err, repositories.ErrUserNotMuted):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		fc.logger.Error("Error updating user restriction", "err", err, "action", action)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Could not " + action + " user"})
+	}
+}
+
+func (fc *FriendshipController) BlockUser(ctx *gin.Context) {
+	userID := ctx.GetUint64("userID")
+	if userID == 0 {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": constants.ErrUnauthorized})
+		return
+	}
+
+	var payload TargetUserPayload
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		return
+	}
+
+	if err := fc.service.BlockUser(uint(userID), payload.UserID); err != nil {
+		fc.respondRestrictionError(ctx, err, "block")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "User blocked successfully"})
+}
+
+func (fc *FriendshipController) UnblockUser(ctx *gin.Context) {
+	userID := ctx.GetUint64("userID")
+	if userID == 0 {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": constants.ErrUnauthorized})
+		return
+	}
+
+	targetID, err := strconv.ParseUint(ctx.Param("userId"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID format"})
+		return
+	}
+
+	if err := fc.service.UnblockUser(uint(userID), uint(targetID)); err != nil {
+		fc.respondRestrictionError(ctx, err, "unblock")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "User unblocked successfully"})
+}
+
+func (fc *FriendshipController) GetBlockedUsers(ctx *gin.Context) {
+	userID := ctx.GetUint64("userID")
+	if userID == 0 {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": constants.ErrUnauthorized})
+		return
+	}
+
+	users, err := fc.service.GetBlockedUsers(uint(userID))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Could not get blocked users"})
+		return
+	}
+	ctx.JSON(http.StatusOK, users)
+}
+
+func (fc *FriendshipController) MuteUser(ctx *gin.Context) {
+	userID := ctx.GetUint64("userID")
+	if userID == 0 {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": constants.ErrUnauthorized})
+		return
+	}
+
+	var payload TargetUserPayload
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		return
+	}
+
+	if err := fc.service.MuteUser(uint(userID), payload.UserID); err != nil {
+		fc.respondRestrictionError(ctx, err, "mute")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "User muted successfully"})
+}
+
+func (fc *FriendshipController) UnmuteUser(ctx *gin.Context) {
+	userID := ctx.GetUint64("userID")
+	if userID == 0 {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": constants.ErrUnauthorized})
+		return
+	}
+
+	targetID, err := strconv.ParseUint(ctx.Param("userId"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID format"})
+		return
+	}
+
+	if err := fc.service.UnmuteUser(uint(userID), uint(targetID)); err != nil {
+		fc.respondRestrictionError(ctx, err, "unmute")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "User unmuted successfully"})
+}
+
+func (fc *FriendshipController) GetMutedUsers(ctx *gin.Context) {
+	userID := ctx.GetUint64("userID")
+	if userID == 0 {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": constants.ErrUnauthorized})
+		return
+	}
+
+	users, err := fc.service.GetMutedUsers(uint(userID))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Could not get muted users"})
+		return
+	}
+	ctx.JSON(http.StatusOK, users)
+}
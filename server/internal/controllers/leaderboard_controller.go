@@ -1,10 +1,14 @@
 package controllers
 
 import (
+	"errors"
 	"log/slog"
 	"net/http"
+	"slices"
+	"strconv"
 	"strings"
 
+	"github.com/Suplice/CodeQuest/internal/repositories"
 	"github.com/Suplice/CodeQuest/internal/services"
 	"github.com/Suplice/CodeQuest/internal/utils/constants"
 	"github.com/gin-gonic/gin"
@@ -19,6 +23,51 @@ func NewLeaderboardController(service *services.LeaderboardService, logger *slog
 	return &LeaderboardController{service: service, logger: logger}
 }
 
+const (
+	maxLeaderboardPageSize = 100
+	defaultNeighbours      = 5
+	maxNeighbours          = 50
+)
+
+// leaderboardQuery reads the criteria, filter, period and language shared by the leaderboard
+// endpoints and answers with 400 when one is invalid.
+func (lc *LeaderboardController) leaderboardQuery(ctx *gin.Context) (criteria, filter, period, language string, ok bool) {
+	criteria = strings.ToLower(ctx.Param("criteria"))
+	if names := lc.service.Criteria(); !slices.Contains(names, criteria) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid criteria. Must be one of: " + strings.Join(names, ", ")})
+		return
+	}
+
+	filter = strings.ToLower(ctx.DefaultQuery("filter", "all"))
+	if filter != "all" && filter != "friends" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid filter. Must be 'all' or 'friends'"})
+		return
+	}
+
+	period = strings.ToLower(ctx.DefaultQuery("period", services.PeriodAll))
+	if period != services.PeriodAll && period != services.PeriodWeek && period != services.PeriodMonth && period != services.PeriodSeason {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid period. Must be 'all', 'week', 'month', or 'season'"})
+		return
+	}
+
+	language = strings.TrimSpace(ctx.Query("language"))
+	return criteria, filter, period, language, true
+}
+
+// respondLeaderboardError maps ranking errors the caller can fix to 4xx responses.
+func (lc *LeaderboardController) respondLeaderboardError(ctx *gin.Context, err error) bool {
+	switch {
+	case errors.Is(err, repositories.ErrNoActiveSeason):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, repositories.ErrCriteriaWithoutPeriods), errors.Is(err, repositories.ErrCriteriaWithoutLanguages):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		return false
+	}
+	return true
+}
+
+// GetLeaderboard serves GET /leaderboard/:criteria?filter=&period=&language=&limit=&offset=
 func (lc *LeaderboardController) GetLeaderboard(ctx *gin.Context) {
 	userID := ctx.GetUint64("userID")
 	if userID == 0 {
@@ -26,8 +75,92 @@ func (lc *LeaderboardController) GetLeaderboard(ctx *gin.Context) {
 		return
 	}
 
+	criteria, filter, period, language, ok := lc.leaderboardQuery(ctx)
+	if !ok {
+		return
+	}
+
+	limit, err := strconv.Atoi(ctx.DefaultQuery("limit", strconv.Itoa(maxLeaderboardPageSize)))
+	if err != nil || limit < 1 || limit > maxLeaderboardPageSize {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit. Must be between 1 and 100"})
+		return
+	}
+	offset, err := strconv.Atoi(ctx.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid offset"})
+		return
+	}
+
+	leaderboardData, err := lc.service.GetLeaderboard(criteria, filter, period, language, uint(userID), limit, offset)
+	if err != nil {
+		if lc.respondLeaderboardError(ctx, err) {
+			return
+		}
+		lc.logger.Error("Failed to get leaderboard data", "err", err, "criteria", criteria, "filter", filter, "period", period, "userID", userID)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Could not retrieve leaderboard data"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, leaderboardData)
+}
+
+// GetMyRank serves GET /leaderboard/:criteria/me?filter=&period=&language=&neighbours=
+func (lc *LeaderboardController) GetMyRank(ctx *gin.Context) {
+	userID := ctx.GetUint64("userID")
+	if userID == 0 {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": constants.ErrUnauthorized})
+		return
+	}
+
+	criteria, filter, period, language, ok := lc.leaderboardQuery(ctx)
+	if !ok {
+		return
+	}
+
+	neighbours, err := strconv.Atoi(ctx.DefaultQuery("neighbours", strconv.Itoa(defaultNeighbours)))
+	if err != nil || neighbours < 0 || neighbours > maxNeighbours {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid neighbours. Must be between 0 and 50"})
+		return
+	}
+
+	around, err := lc.service.GetLeaderboardAround(criteria, filter, period, language, uint(userID), neighbours)
+	if err != nil {
+		if lc.respondLeaderboardError(ctx, err) {
+			return
+		}
+		lc.logger.Error("Failed to get leaderboard rank", "err", err, "criteria", criteria, "filter", filter, "period", period, "userID", userID)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Could not retrieve leaderboard data"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, around)
+}
+
+func (lc *LeaderboardController) GetSeasons(ctx *gin.Context) {
+	seasons, err := lc.service.GetSeasons()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Could not retrieve seasons"})
+		return
+	}
+	ctx.JSON(http.StatusOK, seasons)
+}
+
+// GetSeasonStandings serves GET /seasons/:id/:criteria?filter=all|friends
+func (lc *LeaderboardController) GetSeasonStandings(ctx *gin.Context) {
+	userID := ctx.GetUint64("userID")
+	if userID == 0 {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": constants.ErrUnauthorized})
+		return
+	}
+
+	seasonID, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid season ID"})
+		return
+	}
+
 	criteria := strings.ToLower(ctx.Param("criteria"))
-	if criteria != "level" && criteria != "points" && criteria != "completed" {
+	if !slices.Contains(services.SeasonCriteria, criteria) {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid criteria. Must be 'level', 'points', or 'completed'"})
 		return
 	}
@@ -38,14 +171,15 @@ func (lc *LeaderboardController) GetLeaderboard(ctx *gin.Context) {
 		return
 	}
 
-	limit := 100 
-
-	leaderboardData, err := lc.service.GetLeaderboard(criteria, filter, uint(userID), limit)
+	standings, err := lc.service.GetSeasonStandings(uint(seasonID), criteria, filter, uint(userID), 100)
 	if err != nil {
-		lc.logger.Error("Failed to get leaderboard data", "err", err, "criteria", criteria, "filter", filter, "userID", userID)
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Could not retrieve leaderboard data"})
+		if errors.Is(err, repositories.ErrSeasonNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		lc.logger.Error("Failed to get season standings", "err", err, "seasonID", seasonID, "criteria", criteria)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Could not retrieve season standings"})
 		return
 	}
-
-	ctx.JSON(http.StatusOK, leaderboardData)
-}
\ No newline at end of file
+	ctx.JSON(http.StatusOK, standings)
+}
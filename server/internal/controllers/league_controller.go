@@ -0,0 +1,42 @@
+package controllers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/Suplice/CodeQuest/internal/repositories"
+	"github.com/Suplice/CodeQuest/internal/services"
+	"github.com/Suplice/CodeQuest/internal/utils/constants"
+	"github.com/gin-gonic/gin"
+)
+
+type LeagueController struct {
+	service *services.LeagueService
+	logger  *slog.Logger
+}
+
+func NewLeagueController(service *services.LeagueService, logger *slog.Logger) *LeagueController {
+	return &LeagueController{service: service, logger: logger}
+}
+
+// GetLeague serves GET /league with the caller's tier, cohort standings and recent results.
+func (lc *LeagueController) GetLeague(ctx *gin.Context) {
+	userID := ctx.GetUint64("userID")
+	if userID == 0 {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": constants.ErrUnauthorized})
+		return
+	}
+
+	league, err := lc.service.GetLeague(uint(userID))
+	if err != nil {
+		if errors.Is(err, repositories.ErrUserNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		lc.logger.Error("Failed to get league", "err", err, "userID", userID)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Could not retrieve league"})
+		return
+	}
+	ctx.JSON(http.StatusOK, league)
+}
@@ -0,0 +1,29 @@
+package controllers
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/Suplice/CodeQuest/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type MultiplierController struct {
+	service *services.MultiplierService
+	logger  *slog.Logger
+}
+
+func NewMultiplierController(service *services.MultiplierService, logger *slog.Logger) *MultiplierController {
+	return &MultiplierController{service: service, logger: logger}
+}
+
+// GetCurrentEvents serves GET /multipliers with the running and upcoming multiplier events.
+func (mc *MultiplierController) GetCurrentEvents(ctx *gin.Context) {
+	events, err := mc.service.GetCurrentEvents()
+	if err != nil {
+		mc.logger.Error("Failed to get multiplier events", "err", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Could not retrieve multiplier events"})
+		return
+	}
+	ctx.JSON(http.StatusOK, events)
+}
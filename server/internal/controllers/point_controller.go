@@ -0,0 +1,38 @@
+package controllers
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/Suplice/CodeQuest/internal/services"
+	"github.com/Suplice/CodeQuest/internal/utils/constants"
+	"github.com/gin-gonic/gin"
+)
+
+type PointController struct {
+	service *services.PointService
+	logger  *slog.Logger
+}
+
+func NewPointController(service *services.PointService, logger *slog.Logger) *PointController {
+	return &PointController{service: service, logger: logger}
+}
+
+func (pc *PointController) GetTransactions(ctx *gin.Context) {
+	userID := ctx.GetUint64("userID")
+	if userID == 0 {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": constants.ErrUnauthorized})
+		return
+	}
+
+	limit, _ := strconv.Atoi(ctx.DefaultQuery("limit", "50"))
+	offset, _ := strconv.Atoi(ctx.DefaultQuery("offset", "0"))
+
+	transactions, err := pc.service.GetTransactions(uint(userID), limit, offset)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch transactions"})
+		return
+	}
+	ctx.JSON(http.StatusOK, transactions)
+}
@@ -1,10 +1,12 @@
 package controllers
 
 import (
+	"errors"
 	"log/slog"
 	"net/http"
 	"strconv"
 
+	"github.com/Suplice/CodeQuest/internal/repositories"
 	"github.com/Suplice/CodeQuest/internal/services"
 	"github.com/Suplice/CodeQuest/internal/utils/constants"
 	"github.com/gin-gonic/gin"
@@ -33,6 +35,10 @@ func (pc *ProfileController) GetProfile(ctx *gin.Context) {
 		return }
 
 	profileData, err := pc.service.GetProfile(uint(profileID), uint(currentUserID))
+	if errors.Is(err, repositories.ErrUserNotFound) {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
 	if err != nil { 		
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid filter. Must be 'all' or 'friends'"})
 		return }
@@ -0,0 +1,42 @@
+package controllers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/Suplice/CodeQuest/internal/repositories"
+	"github.com/Suplice/CodeQuest/internal/services"
+	"github.com/Suplice/CodeQuest/internal/utils/constants"
+	"github.com/gin-gonic/gin"
+)
+
+type QuestController struct {
+	service *services.QuestService
+	logger  *slog.Logger
+}
+
+func NewQuestController(service *services.QuestService, logger *slog.Logger) *QuestController {
+	return &QuestController{service: service, logger: logger}
+}
+
+// GetQuests serves GET /quests with the caller's daily and weekly quests and their progress.
+func (qc *QuestController) GetQuests(ctx *gin.Context) {
+	userID := ctx.GetUint64("userID")
+	if userID == 0 {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": constants.ErrUnauthorized})
+		return
+	}
+
+	quests, err := qc.service.GetQuests(uint(userID))
+	if err != nil {
+		if errors.Is(err, repositories.ErrUserNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		qc.logger.Error("Failed to get quests", "err", err, "userID", userID)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Could not retrieve quests"})
+		return
+	}
+	ctx.JSON(http.StatusOK, quests)
+}
@@ -0,0 +1,151 @@
+package controllers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/Suplice/CodeQuest/internal/dto"
+	"github.com/Suplice/CodeQuest/internal/repositories"
+	"github.com/Suplice/CodeQuest/internal/services"
+	"github.com/Suplice/CodeQuest/internal/utils/constants"
+	"github.com/gin-gonic/gin"
+)
+
+type ShopController struct {
+	service *services.ShopService
+	logger  *slog.Logger
+}
+
+func NewShopController(service *services.ShopService, logger *slog.Logger) *ShopController {
+	return &ShopController{service: service, logger: logger}
+}
+
+// GetCatalog serves GET /shop with the items for sale.
+func (sc *ShopController) GetCatalog(ctx *gin.Context) {
+	userID := ctx.GetUint64("userID")
+	if userID == 0 {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": constants.ErrUnauthorized})
+		return
+	}
+
+	catalog, err := sc.service.GetCatalog(uint(userID))
+	if err != nil {
+		sc.respondShopError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, catalog)
+}
+
+// Purchase serves POST /shop/:id/purchase.
+func (sc *ShopController) Purchase(ctx *gin.Context) {
+	userID := ctx.GetUint64("userID")
+	if userID == 0 {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": constants.ErrUnauthorized})
+		return
+	}
+	itemID, ok := parseIDParam(ctx, "id", "Invalid item ID")
+	if !ok {
+		return
+	}
+
+	payload := dto.PurchaseItemDTO{Quantity: 1}
+	if ctx.Request.ContentLength != 0 {
+		if err := ctx.ShouldBindJSON(&payload); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+			return
+		}
+	}
+
+	result, err := sc.service.Purchase(uint(userID), itemID, payload.Quantity)
+	if err != nil {
+		sc.respondShopError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, result)
+}
+
+// GetInventory serves GET /inventory.
+func (sc *ShopController) GetInventory(ctx *gin.Context) {
+	userID := ctx.GetUint64("userID")
+	if userID == 0 {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": constants.ErrUnauthorized})
+		return
+	}
+
+	inventory, err := sc.service.GetInventory(uint(userID))
+	if err != nil {
+		sc.respondShopError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, inventory)
+}
+
+// Equip serves POST /inventory/:itemId/equip.
+func (sc *ShopController) Equip(ctx *gin.Context) {
+	sc.setEquipped(ctx, true)
+}
+
+// Unequip serves POST /inventory/:itemId/unequip.
+func (sc *ShopController) Unequip(ctx *gin.Context) {
+	sc.setEquipped(ctx, false)
+}
+
+func (sc *ShopController) setEquipped(ctx *gin.Context, equipped bool) {
+	userID := ctx.GetUint64("userID")
+	if userID == 0 {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": constants.ErrUnauthorized})
+		return
+	}
+	itemID, ok := parseIDParam(ctx, "itemId", "Invalid item ID")
+	if !ok {
+		return
+	}
+
+	inventory, err := sc.service.SetEquipped(uint(userID), itemID, equipped)
+	if err != nil {
+		sc.respondShopError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, inventory)
+}
+
+// UseHint serves POST /inventory/hints.
+func (sc *ShopController) UseHint(ctx *gin.Context) {
+	userID := ctx.GetUint64("userID")
+	if userID == 0 {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": constants.ErrUnauthorized})
+		return
+	}
+
+	var payload dto.UseHintDTO
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		return
+	}
+
+	hint, err := sc.service.UseHint(uint(userID), payload.QuestionID)
+	if err != nil {
+		sc.respondShopError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, hint)
+}
+
+func (sc *ShopController) respondShopError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrValidation):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, repositories.ErrUserNotFound), errors.Is(err, repositories.ErrShopItemNotFound), errors.Is(err, repositories.ErrItemNotOwned),
+		errors.Is(err, repositories.ErrQuestionNotFound), errors.Is(err, services.ErrNoHintAvailable):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, repositories.ErrCosmeticQuantity), errors.Is(err, repositories.ErrItemNotEquippable):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, repositories.ErrInsufficientPoints), errors.Is(err, repositories.ErrFreezeLimitReached),
+		errors.Is(err, repositories.ErrItemAlreadyOwned), errors.Is(err, repositories.ErrShopItemNotForSale), errors.Is(err, repositories.ErrNoHintTokens):
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		sc.logger.Error("Shop operation failed", "err", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Could not complete shop request"})
+	}
+}
@@ -0,0 +1,113 @@
+package controllers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/Suplice/CodeQuest/internal/dto"
+	"github.com/Suplice/CodeQuest/internal/repositories"
+	"github.com/Suplice/CodeQuest/internal/services"
+	"github.com/Suplice/CodeQuest/internal/utils/constants"
+	"github.com/gin-gonic/gin"
+)
+
+type StreakController struct {
+	service *services.StreakService
+	logger  *slog.Logger
+}
+
+func NewStreakController(service *services.StreakService, logger *slog.Logger) *StreakController {
+	return &StreakController{service: service, logger: logger}
+}
+
+func (sc *StreakController) GetMyStreak(ctx *gin.Context) {
+	userID := ctx.GetUint64("userID")
+	if userID == 0 {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": constants.ErrUnauthorized})
+		return
+	}
+
+	summary, err := sc.service.GetSummary(uint(userID))
+	if err != nil {
+		sc.logger.Error("Failed to get streak summary", "err", err, "userID", userID)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch streak"})
+		return
+	}
+	ctx.JSON(http.StatusOK, summary)
+}
+
+func (sc *StreakController) SetTimezone(ctx *gin.Context) {
+	userID := ctx.GetUint64("userID")
+	if userID == 0 {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": constants.ErrUnauthorized})
+		return
+	}
+
+	var payload dto.TimezoneDTO
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		return
+	}
+
+	if err := sc.service.SetTimezone(uint(userID), payload.Timezone); err != nil {
+		if errors.Is(err, services.ErrValidation) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": constants.ErrUpdateSettings})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": constants.SuccessUpdateSettings, "timezone": payload.Timezone})
+}
+
+func (sc *StreakController) BuyFreezes(ctx *gin.Context) {
+	userID := ctx.GetUint64("userID")
+	if userID == 0 {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": constants.ErrUnauthorized})
+		return
+	}
+
+	var payload dto.BuyFreezesDTO
+	if err := ctx.ShouldBindJSON(&payload); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		return
+	}
+
+	result, err := sc.service.BuyFreezes(uint(userID), payload.Quantity)
+	if err != nil {
+		sc.respondPurchaseError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, result)
+}
+
+func (sc *StreakController) RepairStreak(ctx *gin.Context) {
+	userID := ctx.GetUint64("userID")
+	if userID == 0 {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": constants.ErrUnauthorized})
+		return
+	}
+
+	result, err := sc.service.RepairStreak(uint(userID))
+	if err != nil {
+		sc.respondPurchaseError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, result)
+}
+
+func (sc *StreakController) respondPurchaseError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrValidation):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, repositories.ErrShopItemNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "streak freezes are not for sale"})
+	case errors.Is(err, repositories.ErrInsufficientPoints), errors.Is(err, repositories.ErrFreezeLimitReached),
+		errors.Is(err, repositories.ErrNoBrokenStreak), errors.Is(err, repositories.ErrRepairWindowPassed):
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		sc.logger.Error("Streak purchase failed", "err", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Could not complete purchase"})
+	}
+}
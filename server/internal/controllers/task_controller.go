@@ -1,10 +1,12 @@
 package controllers
 
 import (
+	"errors"
 	"log/slog"
 	"net/http"
 	"strconv"
 
+	"github.com/Suplice/CodeQuest/internal/repositories"
 	"github.com/Suplice/CodeQuest/internal/services"
 	"github.com/Suplice/CodeQuest/internal/utils/constants"
 	"github.com/gin-gonic/gin"
@@ -59,6 +61,11 @@ func (tc *TaskController) GetTaskForUser(ctx *gin.Context) {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID format"})
 		return
 	}
+
+	if ctx.GetUint64("userID") != userID {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "You are not authorized to view this user's tasks"})
+		return
+	}
 	task, err := tc.taskService.GetTaskForUser(uint(taskID), uint(userID))
 	if err != nil {
 		if err.Error() == "task not found" {
@@ -103,7 +110,16 @@ func (tc *TaskController) SubmitAnswer(ctx *gin.Context) {
 	)
 
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		switch {
+		case errors.Is(err, repositories.ErrQuestionNotFound):
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrCodeSubmissionRequired), errors.Is(err, repositories.ErrTaskAlreadyCompleted):
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, repositories.ErrTaskInDuel):
+			ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
 		return
 	}
 
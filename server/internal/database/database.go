@@ -1,7 +1,6 @@
 package database
 
 import (
-	"github.com/Suplice/CodeQuest/internal/models"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
@@ -24,26 +23,16 @@ func Connect(databaseURL string) (*gorm.DB, error) {
 	return db, nil
 }
 
+// Migrate applies all pending SQL migrations from the migrations directory. Schema changes
+// are never derived from the models: every model change needs a new numbered migration.
 func Migrate(db *gorm.DB) error {
-	err := db.AutoMigrate(
-		&models.User{},
-		&models.Settings{},
-		&models.Task{},
-		&models.TaskQuestion{},
-		&models.UserTaskProgress{},
-		&models.UserAnswer{},
-		&models.CodeSubmission{},
-		&models.Badge{},
-		&models.UserBadge{},
-		&models.Friendship{},
-		&models.ActivityLog{},
-	)
-
+	migrator, err := NewMigrator(db)
 	if err != nil {
 		return err
 	}
 
-	return nil
+	_, err = migrator.Up()
+	return err
 }
 
 func Close(db *gorm.DB) error {
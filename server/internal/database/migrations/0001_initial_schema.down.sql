@@ -0,0 +1,11 @@
+DROP TABLE IF EXISTS activity_logs;
+DROP TABLE IF EXISTS friendships;
+DROP TABLE IF EXISTS user_badges;
+DROP TABLE IF EXISTS badges;
+DROP TABLE IF EXISTS code_submissions;
+DROP TABLE IF EXISTS user_answers;
+DROP TABLE IF EXISTS user_task_progresses;
+DROP TABLE IF EXISTS task_questions;
+DROP TABLE IF EXISTS tasks;
+DROP TABLE IF EXISTS settings;
+DROP TABLE IF EXISTS users;
@@ -0,0 +1,194 @@
+-- Schema as previously created by GORM AutoMigrate. Every statement is idempotent so databases
+-- that were created before versioned migrations existed can adopt this history unchanged.
+
+CREATE TABLE IF NOT EXISTS users (
+    id               BIGSERIAL PRIMARY KEY,
+    created_at       TIMESTAMPTZ,
+    updated_at       TIMESTAMPTZ,
+    deleted_at       TIMESTAMPTZ,
+    username         VARCHAR(255) NOT NULL,
+    email            VARCHAR(255) NOT NULL,
+    provider         VARCHAR(255) NOT NULL,
+    avatar_url       VARCHAR(255) NOT NULL,
+    role             VARCHAR(255) NOT NULL,
+    password_hash    VARCHAR(255),
+    last_login_at    TIMESTAMPTZ,
+    google_id        VARCHAR(255),
+    github_id        VARCHAR(255),
+    level            BIGINT DEFAULT 1,
+    xp               BIGINT DEFAULT 0,
+    points           BIGINT DEFAULT 0,
+    streak_count     BIGINT DEFAULT 0,
+    last_active_date TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_users_deleted_at ON users (deleted_at);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email ON users (email);
+
+CREATE TABLE IF NOT EXISTS settings (
+    user_id       BIGSERIAL NOT NULL,
+    setting_key   TEXT NOT NULL,
+    setting_value TEXT NOT NULL,
+    created_at    TIMESTAMPTZ,
+    updated_at    TIMESTAMPTZ,
+    PRIMARY KEY (user_id, setting_key),
+    CONSTRAINT fk_settings_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS tasks (
+    id          BIGSERIAL PRIMARY KEY,
+    created_at  TIMESTAMPTZ,
+    updated_at  TIMESTAMPTZ,
+    deleted_at  TIMESTAMPTZ,
+    slug        VARCHAR(255),
+    title       VARCHAR(255) NOT NULL,
+    description VARCHAR(512),
+    type        VARCHAR(50) NOT NULL,
+    language    VARCHAR(50) NOT NULL,
+    difficulty  VARCHAR(50) NOT NULL,
+    points      BIGINT DEFAULT 0,
+    xp          BIGINT DEFAULT 0,
+    is_active   BOOLEAN DEFAULT true
+);
+-- Columns added after the first AutoMigrate-based releases.
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS slug VARCHAR(255);
+CREATE INDEX IF NOT EXISTS idx_tasks_deleted_at ON tasks (deleted_at);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tasks_slug ON tasks (slug) WHERE slug <> '' AND deleted_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS task_questions (
+    id              BIGSERIAL PRIMARY KEY,
+    created_at      TIMESTAMPTZ,
+    updated_at      TIMESTAMPTZ,
+    deleted_at      TIMESTAMPTZ,
+    task_id         BIGINT NOT NULL,
+    slug            VARCHAR(255),
+    question_text   TEXT NOT NULL,
+    type            VARCHAR(50) NOT NULL,
+    options         JSONB,
+    grading_config  JSONB,
+    correct_answer  VARCHAR(255),
+    test_input      TEXT,
+    expected_output TEXT,
+    explanation     TEXT,
+    position        BIGINT DEFAULT 0,
+    CONSTRAINT fk_tasks_task_questions FOREIGN KEY (task_id) REFERENCES tasks (id)
+);
+ALTER TABLE task_questions ADD COLUMN IF NOT EXISTS slug VARCHAR(255);
+ALTER TABLE task_questions ADD COLUMN IF NOT EXISTS grading_config JSONB;
+ALTER TABLE task_questions ADD COLUMN IF NOT EXISTS explanation TEXT;
+ALTER TABLE task_questions ADD COLUMN IF NOT EXISTS position BIGINT DEFAULT 0;
+CREATE INDEX IF NOT EXISTS idx_task_questions_deleted_at ON task_questions (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_task_questions_task_id ON task_questions (task_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_task_questions_slug ON task_questions (task_id, slug) WHERE slug <> '' AND deleted_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS user_task_progresses (
+    id           BIGSERIAL PRIMARY KEY,
+    created_at   TIMESTAMPTZ,
+    updated_at   TIMESTAMPTZ,
+    deleted_at   TIMESTAMPTZ,
+    user_id      BIGINT NOT NULL,
+    task_id      BIGINT NOT NULL,
+    progress     NUMERIC DEFAULT 0,
+    attempts     BIGINT DEFAULT 0,
+    mistakes     BIGINT DEFAULT 0,
+    is_completed BOOLEAN DEFAULT false,
+    completed_at TIMESTAMPTZ,
+    CONSTRAINT fk_users_task_progress FOREIGN KEY (user_id) REFERENCES users (id),
+    CONSTRAINT fk_tasks_user_progress FOREIGN KEY (task_id) REFERENCES tasks (id)
+);
+CREATE INDEX IF NOT EXISTS idx_user_task_progresses_deleted_at ON user_task_progresses (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_user_task_progresses_user_id ON user_task_progresses (user_id);
+CREATE INDEX IF NOT EXISTS idx_user_task_progresses_task_id ON user_task_progresses (task_id);
+
+CREATE TABLE IF NOT EXISTS user_answers (
+    id                    BIGSERIAL PRIMARY KEY,
+    created_at            TIMESTAMPTZ,
+    updated_at            TIMESTAMPTZ,
+    deleted_at            TIMESTAMPTZ,
+    user_task_progress_id BIGINT NOT NULL,
+    task_question_id      BIGINT NOT NULL,
+    answer_given          TEXT,
+    is_correct            BOOLEAN DEFAULT false,
+    attempts              BIGINT DEFAULT 1,
+    submitted_at          TIMESTAMPTZ,
+    CONSTRAINT fk_user_task_progresses_answers FOREIGN KEY (user_task_progress_id) REFERENCES user_task_progresses (id),
+    CONSTRAINT fk_user_answers_task_question FOREIGN KEY (task_question_id) REFERENCES task_questions (id)
+);
+CREATE INDEX IF NOT EXISTS idx_user_answers_deleted_at ON user_answers (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_user_answers_user_task_progress_id ON user_answers (user_task_progress_id);
+CREATE INDEX IF NOT EXISTS idx_user_answers_task_question_id ON user_answers (task_question_id);
+
+CREATE TABLE IF NOT EXISTS code_submissions (
+    id               BIGSERIAL PRIMARY KEY,
+    created_at       TIMESTAMPTZ,
+    updated_at       TIMESTAMPTZ,
+    deleted_at       TIMESTAMPTZ,
+    user_id          BIGINT NOT NULL,
+    task_id          BIGINT NOT NULL,
+    task_question_id BIGINT,
+    code             TEXT,
+    language         VARCHAR(50),
+    status           VARCHAR(50),
+    output           TEXT,
+    error_msg        TEXT,
+    submitted_at     TIMESTAMPTZ,
+    CONSTRAINT fk_code_submissions_user FOREIGN KEY (user_id) REFERENCES users (id),
+    CONSTRAINT fk_code_submissions_task FOREIGN KEY (task_id) REFERENCES tasks (id)
+);
+ALTER TABLE code_submissions ADD COLUMN IF NOT EXISTS task_question_id BIGINT;
+CREATE INDEX IF NOT EXISTS idx_code_submissions_deleted_at ON code_submissions (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_code_submissions_user_id ON code_submissions (user_id);
+CREATE INDEX IF NOT EXISTS idx_code_submissions_task_id ON code_submissions (task_id);
+CREATE INDEX IF NOT EXISTS idx_code_submissions_task_question_id ON code_submissions (task_question_id);
+
+CREATE TABLE IF NOT EXISTS badges (
+    id          BIGSERIAL PRIMARY KEY,
+    created_at  TIMESTAMPTZ,
+    updated_at  TIMESTAMPTZ,
+    deleted_at  TIMESTAMPTZ,
+    slug        VARCHAR(255),
+    name        VARCHAR(255) NOT NULL,
+    description VARCHAR(512),
+    icon_url    VARCHAR(255),
+    requirement VARCHAR(255)
+);
+ALTER TABLE badges ADD COLUMN IF NOT EXISTS slug VARCHAR(255);
+CREATE INDEX IF NOT EXISTS idx_badges_deleted_at ON badges (deleted_at);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_badges_slug ON badges (slug) WHERE slug <> '' AND deleted_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS user_badges (
+    user_id     BIGINT NOT NULL,
+    badge_id    BIGINT NOT NULL,
+    achieved_at TIMESTAMPTZ,
+    CONSTRAINT fk_users_badges FOREIGN KEY (user_id) REFERENCES users (id),
+    CONSTRAINT fk_user_badges_badge FOREIGN KEY (badge_id) REFERENCES badges (id)
+);
+CREATE INDEX IF NOT EXISTS idx_user_badges_user_id ON user_badges (user_id);
+CREATE INDEX IF NOT EXISTS idx_user_badges_badge_id ON user_badges (badge_id);
+
+CREATE TABLE IF NOT EXISTS friendships (
+    id         BIGSERIAL PRIMARY KEY,
+    user_id    BIGINT NOT NULL,
+    friend_id  BIGINT NOT NULL,
+    status     VARCHAR(50) NOT NULL,
+    created_at TIMESTAMPTZ,
+    CONSTRAINT fk_users_friends FOREIGN KEY (user_id) REFERENCES users (id),
+    CONSTRAINT fk_friendships_friend FOREIGN KEY (friend_id) REFERENCES users (id)
+);
+CREATE INDEX IF NOT EXISTS idx_friendships_user_id ON friendships (user_id);
+CREATE INDEX IF NOT EXISTS idx_friendships_friend_id ON friendships (friend_id);
+
+CREATE TABLE IF NOT EXISTS activity_logs (
+    id            BIGSERIAL PRIMARY KEY,
+    created_at    TIMESTAMPTZ,
+    updated_at    TIMESTAMPTZ,
+    deleted_at    TIMESTAMPTZ,
+    user_id       BIGINT NOT NULL,
+    action_type   VARCHAR(255) NOT NULL,
+    description   VARCHAR(512),
+    points_earned BIGINT,
+    xp_earned     BIGINT,
+    "timestamp"   TIMESTAMPTZ,
+    CONSTRAINT fk_users_activities FOREIGN KEY (user_id) REFERENCES users (id)
+);
+CREATE INDEX IF NOT EXISTS idx_activity_logs_deleted_at ON activity_logs (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_activity_logs_user_id ON activity_logs (user_id);
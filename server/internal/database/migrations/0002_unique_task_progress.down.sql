@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_user_task_progresses_user_task;
@@ -0,0 +1,46 @@
+-- Concurrent first answers could create several progress rows for the same user and task.
+-- Merge them into the oldest row, move their answers over and drop the rest before adding
+-- the unique index. Rewards that were already granted twice are not reverted.
+CREATE TEMP TABLE progress_duplicates ON COMMIT DROP AS
+SELECT p.id AS duplicate_id, k.keep_id
+FROM user_task_progresses p
+JOIN (
+    SELECT user_id, task_id, MIN(id) AS keep_id
+    FROM user_task_progresses
+    WHERE deleted_at IS NULL
+    GROUP BY user_id, task_id
+    HAVING COUNT(*) > 1
+) k ON k.user_id = p.user_id AND k.task_id = p.task_id
+WHERE p.deleted_at IS NULL AND p.id <> k.keep_id;
+
+UPDATE user_task_progresses keep
+SET attempts     = COALESCE(keep.attempts, 0) + agg.attempts,
+    mistakes     = COALESCE(keep.mistakes, 0) + agg.mistakes,
+    progress     = GREATEST(keep.progress, agg.progress),
+    is_completed = COALESCE(keep.is_completed, false) OR agg.is_completed,
+    completed_at = LEAST(keep.completed_at, agg.completed_at)
+FROM (
+    SELECT d.keep_id,
+           SUM(COALESCE(p.attempts, 0)) AS attempts,
+           SUM(COALESCE(p.mistakes, 0)) AS mistakes,
+           MAX(p.progress) AS progress,
+           BOOL_OR(COALESCE(p.is_completed, false)) AS is_completed,
+           MIN(p.completed_at) AS completed_at
+    FROM progress_duplicates d
+    JOIN user_task_progresses p ON p.id = d.duplicate_id
+    GROUP BY d.keep_id
+) agg
+WHERE keep.id = agg.keep_id;
+
+UPDATE user_answers a
+SET user_task_progress_id = d.keep_id
+FROM progress_duplicates d
+WHERE a.user_task_progress_id = d.duplicate_id;
+
+DELETE FROM user_task_progresses p
+USING progress_duplicates d
+WHERE p.id = d.duplicate_id;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_user_task_progresses_user_task
+    ON user_task_progresses (user_id, task_id)
+    WHERE deleted_at IS NULL;
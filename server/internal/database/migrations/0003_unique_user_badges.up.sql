@@ -0,0 +1,16 @@
+-- The old badge checker ran in a goroutine per answer and could award the same badge twice.
+-- Keep the earliest award of each badge and make user_id + badge_id unique so awarding is
+-- idempotent (INSERT ... ON CONFLICT DO NOTHING).
+DELETE FROM user_badges b
+USING (
+    SELECT ctid,
+           ROW_NUMBER() OVER (
+               PARTITION BY user_id, badge_id
+               ORDER BY COALESCE(achieved_at, '-infinity'::timestamptz)
+           ) AS rn
+    FROM user_badges
+) d
+WHERE b.ctid = d.ctid AND d.rn > 1;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_user_badges_user_badge
+    ON user_badges (user_id, badge_id);
@@ -0,0 +1,18 @@
+-- Admin-editable game settings such as the leveling curve. A missing row means the built-in
+-- default applies.
+CREATE TABLE IF NOT EXISTS game_configs (
+    key        VARCHAR(100) PRIMARY KEY,
+    value      JSONB NOT NULL,
+    updated_at TIMESTAMPTZ
+);
+
+-- Levels used to stop at 5. Recompute them with the default curve, which keeps the old
+-- thresholds and adds a level for every 500 XP past 1000. A stored level is never lowered.
+UPDATE users
+SET level = GREATEST(level, CASE
+    WHEN xp >= 1000 THEN 5 + (xp - 1000) / 500
+    WHEN xp >= 500 THEN 4
+    WHEN xp >= 250 THEN 3
+    WHEN xp >= 100 THEN 2
+    ELSE 1
+END);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS streak_histories;
+ALTER TABLE users DROP COLUMN IF EXISTS longest_streak;
+ALTER TABLE users DROP COLUMN IF EXISTS timezone;
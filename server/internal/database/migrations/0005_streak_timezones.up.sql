@@ -0,0 +1,14 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS timezone VARCHAR(64) NOT NULL DEFAULT 'UTC';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS longest_streak BIGINT DEFAULT 0;
+UPDATE users SET longest_streak = streak_count WHERE longest_streak < streak_count;
+
+CREATE TABLE IF NOT EXISTS streak_histories (
+    id         BIGSERIAL PRIMARY KEY,
+    user_id    BIGINT NOT NULL,
+    length     BIGINT NOT NULL,
+    started_on DATE NOT NULL,
+    ended_on   DATE NOT NULL,
+    created_at TIMESTAMPTZ,
+    CONSTRAINT fk_streak_histories_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_streak_histories_user_id ON streak_histories (user_id);
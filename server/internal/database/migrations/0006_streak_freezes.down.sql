@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS point_transactions;
+ALTER TABLE streak_histories DROP COLUMN IF EXISTS repaired_at;
+ALTER TABLE users DROP COLUMN IF EXISTS streak_freezes;
@@ -0,0 +1,14 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS streak_freezes BIGINT DEFAULT 0;
+ALTER TABLE streak_histories ADD COLUMN IF NOT EXISTS repaired_at TIMESTAMPTZ;
+
+CREATE TABLE IF NOT EXISTS point_transactions (
+    id           BIGSERIAL PRIMARY KEY,
+    user_id      BIGINT NOT NULL,
+    kind         VARCHAR(50) NOT NULL,
+    points       BIGINT NOT NULL DEFAULT 0,
+    freezes      BIGINT NOT NULL DEFAULT 0,
+    reference_id BIGINT,
+    created_at   TIMESTAMPTZ,
+    CONSTRAINT fk_point_transactions_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_point_transactions_user_created ON point_transactions (user_id, created_at);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_activity_logs_user_type_id;
+ALTER TABLE activity_logs DROP COLUMN IF EXISTS metadata;
+ALTER TABLE activity_logs DROP COLUMN IF EXISTS badge_id;
+ALTER TABLE activity_logs DROP COLUMN IF EXISTS task_id;
@@ -0,0 +1,6 @@
+ALTER TABLE activity_logs ADD COLUMN IF NOT EXISTS task_id BIGINT;
+ALTER TABLE activity_logs ADD COLUMN IF NOT EXISTS badge_id BIGINT;
+ALTER TABLE activity_logs ADD COLUMN IF NOT EXISTS metadata JSONB;
+
+-- Timelines are read per user, newest first, optionally filtered by type.
+CREATE INDEX IF NOT EXISTS idx_activity_logs_user_type_id ON activity_logs (user_id, action_type, id);
@@ -0,0 +1,4 @@
+-- Settings are only updated in place, so existing users need a row for the new key.
+INSERT INTO settings (user_id, setting_key, setting_value, created_at, updated_at)
+SELECT id, 'activityVisibility', 'friends', NOW(), NOW() FROM users
+ON CONFLICT (user_id, setting_key) DO NOTHING;
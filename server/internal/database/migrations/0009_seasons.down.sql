@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS season_standings;
+DROP TABLE IF EXISTS seasons;
+DROP INDEX IF EXISTS idx_point_transactions_kind_created;
+ALTER TABLE point_transactions DROP COLUMN IF EXISTS xp;
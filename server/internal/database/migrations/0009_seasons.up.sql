@@ -0,0 +1,44 @@
+ALTER TABLE point_transactions ADD COLUMN IF NOT EXISTS xp BIGINT NOT NULL DEFAULT 0;
+CREATE INDEX IF NOT EXISTS idx_point_transactions_kind_created ON point_transactions (kind, created_at);
+
+-- Task rewards were only recorded when the task granted points, and without XP.
+UPDATE point_transactions pt SET xp = t.xp
+FROM tasks t
+WHERE pt.kind = 'task_reward' AND pt.reference_id = t.id;
+
+-- Completions from before the ledger existed, so windowed leaderboards see them too.
+INSERT INTO point_transactions (user_id, kind, points, freezes, xp, reference_id, created_at)
+SELECT utp.user_id, 'task_reward', t.points, 0, t.xp, utp.task_id, utp.completed_at
+FROM user_task_progresses utp
+JOIN tasks t ON t.id = utp.task_id
+WHERE utp.is_completed AND utp.completed_at IS NOT NULL AND utp.deleted_at IS NULL
+  AND NOT EXISTS (
+    SELECT 1 FROM point_transactions pt
+    WHERE pt.user_id = utp.user_id AND pt.kind = 'task_reward' AND pt.reference_id = utp.task_id
+  );
+
+CREATE TABLE IF NOT EXISTS seasons (
+    id          BIGSERIAL PRIMARY KEY,
+    name        VARCHAR(255) NOT NULL,
+    starts_at   TIMESTAMPTZ NOT NULL,
+    ends_at     TIMESTAMPTZ NOT NULL,
+    archived_at TIMESTAMPTZ,
+    created_at  TIMESTAMPTZ,
+    updated_at  TIMESTAMPTZ,
+    CONSTRAINT chk_seasons_range CHECK (ends_at > starts_at)
+);
+CREATE INDEX IF NOT EXISTS idx_seasons_starts_at ON seasons (starts_at);
+
+CREATE TABLE IF NOT EXISTS season_standings (
+    id         BIGSERIAL PRIMARY KEY,
+    season_id  BIGINT NOT NULL,
+    criteria   VARCHAR(50) NOT NULL,
+    user_id    BIGINT NOT NULL,
+    rank       BIGINT NOT NULL,
+    value      BIGINT NOT NULL,
+    created_at TIMESTAMPTZ,
+    CONSTRAINT fk_season_standings_season FOREIGN KEY (season_id) REFERENCES seasons (id) ON DELETE CASCADE,
+    CONSTRAINT fk_season_standings_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_season_standings_season_criteria_user ON season_standings (season_id, criteria, user_id);
+CREATE INDEX IF NOT EXISTS idx_season_standings_season_criteria_rank ON season_standings (season_id, criteria, rank);
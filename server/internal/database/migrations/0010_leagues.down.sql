@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS league_members;
+DROP TABLE IF EXISTS league_cohorts;
+ALTER TABLE users DROP COLUMN IF EXISTS league_tier;
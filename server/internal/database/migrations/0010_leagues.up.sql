@@ -0,0 +1,27 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS league_tier VARCHAR(20) NOT NULL DEFAULT 'bronze';
+
+CREATE TABLE IF NOT EXISTS league_cohorts (
+    id           BIGSERIAL PRIMARY KEY,
+    week_start   TIMESTAMPTZ NOT NULL,
+    tier         VARCHAR(20) NOT NULL,
+    member_count BIGINT NOT NULL DEFAULT 0,
+    finalized_at TIMESTAMPTZ,
+    created_at   TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_league_cohorts_week_tier ON league_cohorts (week_start, tier);
+
+CREATE TABLE IF NOT EXISTS league_members (
+    id         BIGSERIAL PRIMARY KEY,
+    cohort_id  BIGINT NOT NULL,
+    user_id    BIGINT NOT NULL,
+    week_start TIMESTAMPTZ NOT NULL,
+    tier       VARCHAR(20) NOT NULL,
+    xp         BIGINT NOT NULL DEFAULT 0,
+    rank       BIGINT NOT NULL DEFAULT 0,
+    result     VARCHAR(20) NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ,
+    CONSTRAINT fk_league_members_cohort FOREIGN KEY (cohort_id) REFERENCES league_cohorts (id) ON DELETE CASCADE,
+    CONSTRAINT fk_league_members_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_league_members_cohort_id ON league_members (cohort_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_league_members_user_week ON league_members (user_id, week_start);
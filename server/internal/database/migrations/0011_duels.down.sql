@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS duel_answers;
+DROP TABLE IF EXISTS duels;
@@ -0,0 +1,40 @@
+CREATE TABLE IF NOT EXISTS duels (
+    id                     BIGSERIAL PRIMARY KEY,
+    challenger_id          BIGINT NOT NULL,
+    opponent_id            BIGINT NOT NULL,
+    task_id                BIGINT NOT NULL,
+    wager                  BIGINT NOT NULL DEFAULT 0,
+    status                 VARCHAR(20) NOT NULL,
+    winner_id              BIGINT,
+    challenger_correct     BIGINT NOT NULL DEFAULT 0,
+    opponent_correct       BIGINT NOT NULL DEFAULT 0,
+    challenger_started_at  TIMESTAMPTZ,
+    opponent_started_at    TIMESTAMPTZ,
+    challenger_finished_at TIMESTAMPTZ,
+    opponent_finished_at   TIMESTAMPTZ,
+    accepted_at            TIMESTAMPTZ,
+    finished_at            TIMESTAMPTZ,
+    expires_at             TIMESTAMPTZ NOT NULL,
+    created_at             TIMESTAMPTZ,
+    updated_at             TIMESTAMPTZ,
+    CONSTRAINT fk_duels_challenger FOREIGN KEY (challenger_id) REFERENCES users (id) ON DELETE CASCADE,
+    CONSTRAINT fk_duels_opponent FOREIGN KEY (opponent_id) REFERENCES users (id) ON DELETE CASCADE,
+    CONSTRAINT fk_duels_task FOREIGN KEY (task_id) REFERENCES tasks (id),
+    CONSTRAINT chk_duels_players CHECK (challenger_id <> opponent_id),
+    CONSTRAINT chk_duels_wager CHECK (wager >= 0)
+);
+CREATE INDEX IF NOT EXISTS idx_duels_challenger_id ON duels (challenger_id);
+CREATE INDEX IF NOT EXISTS idx_duels_opponent_id ON duels (opponent_id);
+CREATE INDEX IF NOT EXISTS idx_duels_status_expires ON duels (status, expires_at);
+
+CREATE TABLE IF NOT EXISTS duel_answers (
+    id               BIGSERIAL PRIMARY KEY,
+    duel_id          BIGINT NOT NULL,
+    user_id          BIGINT NOT NULL,
+    task_question_id BIGINT NOT NULL,
+    answer           TEXT,
+    is_correct       BOOLEAN NOT NULL,
+    created_at       TIMESTAMPTZ,
+    CONSTRAINT fk_duel_answers_duel FOREIGN KEY (duel_id) REFERENCES duels (id) ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_duel_answers_duel_user_question ON duel_answers (duel_id, user_id, task_question_id);
@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS user_quests (
+    id            BIGSERIAL PRIMARY KEY,
+    user_id       BIGINT NOT NULL,
+    slug          VARCHAR(100) NOT NULL,
+    period        VARCHAR(20) NOT NULL,
+    period_start  DATE NOT NULL,
+    title         VARCHAR(255) NOT NULL,
+    metric        VARCHAR(50) NOT NULL,
+    difficulty    VARCHAR(20) NOT NULL DEFAULT '',
+    language      VARCHAR(50) NOT NULL DEFAULT '',
+    target        BIGINT NOT NULL,
+    progress      BIGINT NOT NULL DEFAULT 0,
+    reward_xp     BIGINT NOT NULL DEFAULT 0,
+    reward_points BIGINT NOT NULL DEFAULT 0,
+    completed_at  TIMESTAMPTZ,
+    created_at    TIMESTAMPTZ,
+    CONSTRAINT fk_user_quests_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE,
+    CONSTRAINT chk_user_quests_progress CHECK (progress >= 0 AND progress <= target)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_user_quests_user_slug_period ON user_quests (user_id, slug, period_start);
+CREATE INDEX IF NOT EXISTS idx_user_quests_user_period ON user_quests (user_id, period_start);
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS hint_unlocks;
+DROP TABLE IF EXISTS user_items;
+DROP TABLE IF EXISTS shop_items;
+ALTER TABLE users DROP COLUMN IF EXISTS profile_theme;
+ALTER TABLE users DROP COLUMN IF EXISTS avatar_frame;
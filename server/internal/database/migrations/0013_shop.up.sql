@@ -0,0 +1,41 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS avatar_frame VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS profile_theme VARCHAR(255) NOT NULL DEFAULT '';
+
+CREATE TABLE IF NOT EXISTS shop_items (
+    id          BIGSERIAL PRIMARY KEY,
+    slug        VARCHAR(255) NOT NULL,
+    name        VARCHAR(255) NOT NULL,
+    description VARCHAR(512),
+    kind        VARCHAR(50) NOT NULL,
+    price       BIGINT NOT NULL,
+    asset_url   VARCHAR(255) NOT NULL DEFAULT '',
+    is_active   BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at  TIMESTAMPTZ,
+    updated_at  TIMESTAMPTZ,
+    CONSTRAINT chk_shop_items_price CHECK (price >= 0)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_shop_items_slug ON shop_items (slug);
+
+CREATE TABLE IF NOT EXISTS user_items (
+    id         BIGSERIAL PRIMARY KEY,
+    user_id    BIGINT NOT NULL,
+    item_id    BIGINT NOT NULL,
+    quantity   BIGINT NOT NULL DEFAULT 0,
+    equipped   BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    CONSTRAINT fk_user_items_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE,
+    CONSTRAINT fk_user_items_item FOREIGN KEY (item_id) REFERENCES shop_items (id),
+    CONSTRAINT chk_user_items_quantity CHECK (quantity >= 0)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_user_items_user_item ON user_items (user_id, item_id);
+
+CREATE TABLE IF NOT EXISTS hint_unlocks (
+    id               BIGSERIAL PRIMARY KEY,
+    user_id          BIGINT NOT NULL,
+    task_question_id BIGINT NOT NULL,
+    created_at       TIMESTAMPTZ,
+    CONSTRAINT fk_hint_unlocks_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE,
+    CONSTRAINT fk_hint_unlocks_question FOREIGN KEY (task_question_id) REFERENCES task_questions (id) ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_hint_unlocks_user_question ON hint_unlocks (user_id, task_question_id);
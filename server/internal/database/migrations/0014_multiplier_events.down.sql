@@ -0,0 +1,3 @@
+ALTER TABLE point_transactions DROP COLUMN IF EXISTS points_multiplier;
+ALTER TABLE point_transactions DROP COLUMN IF EXISTS xp_multiplier;
+DROP TABLE IF EXISTS multiplier_events;
@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS multiplier_events (
+    id            BIGSERIAL PRIMARY KEY,
+    name          VARCHAR(255) NOT NULL,
+    starts_at     TIMESTAMPTZ NOT NULL,
+    ends_at       TIMESTAMPTZ NOT NULL,
+    language      VARCHAR(50) NOT NULL DEFAULT '',
+    difficulty    VARCHAR(50) NOT NULL DEFAULT '',
+    task_type     VARCHAR(50) NOT NULL DEFAULT '',
+    xp_factor     DOUBLE PRECISION NOT NULL,
+    points_factor DOUBLE PRECISION NOT NULL,
+    created_at    TIMESTAMPTZ,
+    updated_at    TIMESTAMPTZ,
+    CONSTRAINT chk_multiplier_events_window CHECK (ends_at > starts_at),
+    CONSTRAINT chk_multiplier_events_factors CHECK (xp_factor > 0 AND points_factor > 0)
+);
+CREATE INDEX IF NOT EXISTS idx_multiplier_events_window ON multiplier_events (starts_at, ends_at);
+
+ALTER TABLE point_transactions ADD COLUMN IF NOT EXISTS xp_multiplier DOUBLE PRECISION NOT NULL DEFAULT 1;
+ALTER TABLE point_transactions ADD COLUMN IF NOT EXISTS points_multiplier DOUBLE PRECISION NOT NULL DEFAULT 1;
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS user_blocks;
+DELETE FROM friendships WHERE status = 'declined';
+ALTER TABLE friendships DROP COLUMN IF EXISTS declined_at;
@@ -0,0 +1,14 @@
+ALTER TABLE friendships ADD COLUMN IF NOT EXISTS declined_at TIMESTAMPTZ;
+
+CREATE TABLE IF NOT EXISTS user_blocks (
+    id         BIGSERIAL PRIMARY KEY,
+    user_id    BIGINT NOT NULL,
+    target_id  BIGINT NOT NULL,
+    kind       VARCHAR(50) NOT NULL,
+    created_at TIMESTAMPTZ,
+    CONSTRAINT fk_user_blocks_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE,
+    CONSTRAINT fk_user_blocks_target FOREIGN KEY (target_id) REFERENCES users (id) ON DELETE CASCADE,
+    CONSTRAINT chk_user_blocks_self CHECK (user_id <> target_id)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_user_blocks_pair ON user_blocks (user_id, target_id);
+CREATE INDEX IF NOT EXISTS idx_user_blocks_target_id ON user_blocks (target_id);
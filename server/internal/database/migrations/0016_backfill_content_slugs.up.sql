@@ -0,0 +1,25 @@
+-- Content created before slugs existed has none, and imports match tasks and badges by their
+-- stored slug. Store the slug content export derives, so an exported bundle re-imports onto the
+-- same rows: the slugified title, or task-<id> when that is empty or already taken.
+WITH derived AS (
+    SELECT id,
+           TRIM(BOTH '-' FROM REGEXP_REPLACE(
+               REPLACE(REPLACE(LOWER(TRIM(title)), '#', 'sharp'), '+', 'plus'),
+               '[^a-z0-9]+', '-', 'g')) AS slug
+    FROM tasks
+    WHERE COALESCE(slug, '') = '' AND deleted_at IS NULL
+), ranked AS (
+    SELECT id, slug, ROW_NUMBER() OVER (PARTITION BY slug ORDER BY id) AS n
+    FROM derived
+)
+UPDATE tasks t
+SET slug = CASE
+    WHEN r.slug = '' OR r.n > 1
+         OR EXISTS (SELECT 1 FROM tasks o WHERE o.slug = r.slug AND o.deleted_at IS NULL)
+    THEN 'task-' || t.id
+    ELSE r.slug
+END
+FROM ranked r
+WHERE t.id = r.id;
+
+UPDATE badges SET slug = 'badge-' || id WHERE COALESCE(slug, '') = '' AND deleted_at IS NULL;
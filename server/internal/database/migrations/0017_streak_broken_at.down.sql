@@ -0,0 +1 @@
+ALTER TABLE streak_histories DROP COLUMN IF EXISTS broken_at;
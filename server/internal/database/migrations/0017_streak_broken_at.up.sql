@@ -0,0 +1,6 @@
+-- The repair window runs from the moment a streak broke, not from when the streak job got to
+-- it. Rows written before this column existed did not record the timezone they were counted
+-- in, so they are backfilled in UTC: a streak breaks once the day after its last day is over.
+ALTER TABLE streak_histories ADD COLUMN IF NOT EXISTS broken_at TIMESTAMPTZ;
+UPDATE streak_histories SET broken_at = (ended_on + 2)::timestamp AT TIME ZONE 'UTC' WHERE broken_at IS NULL;
+ALTER TABLE streak_histories ALTER COLUMN broken_at SET NOT NULL;
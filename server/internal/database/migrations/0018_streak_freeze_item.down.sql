@@ -0,0 +1,2 @@
+-- The item may have been bought or edited since, so it is left in place.
+SELECT 1;
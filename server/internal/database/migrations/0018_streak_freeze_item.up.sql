@@ -0,0 +1,5 @@
+-- Streak freezes are only sold through the shop, so every database needs the item. Its price
+-- is the one the streak endpoint used to charge.
+INSERT INTO shop_items (slug, name, description, kind, price, is_active, created_at, updated_at)
+SELECT 'streak-freeze', 'Streak Freeze', 'Keeps your streak alive through a missed day.', 'streak_freeze', 50, TRUE, NOW(), NOW()
+WHERE NOT EXISTS (SELECT 1 FROM shop_items WHERE kind = 'streak_freeze' OR slug = 'streak-freeze');
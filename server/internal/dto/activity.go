@@ -0,0 +1,32 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/Suplice/CodeQuest/internal/models"
+)
+
+type ActivityPageDTO struct {
+	Items      []models.ActivityLog `json:"items"`
+	NextCursor string               `json:"next_cursor,omitempty"`
+}
+
+// FeedItemDTO is one or more consecutive entries of the same kind by one friend, e.g. three
+// Python tasks completed within an hour.
+type FeedItemDTO struct {
+	User         UserShortInfo        `json:"user"`
+	Type         string               `json:"type"`
+	Summary      string               `json:"summary"`
+	Count        int                  `json:"count"`
+	Language     string               `json:"language,omitempty"`
+	XPEarned     int                  `json:"xp_earned"`
+	PointsEarned int                  `json:"points_earned"`
+	FirstAt      time.Time            `json:"first_at"`
+	LastAt       time.Time            `json:"last_at"`
+	Entries      []models.ActivityLog `json:"entries"`
+}
+
+type FeedPageDTO struct {
+	Items      []FeedItemDTO `json:"items"`
+	NextCursor string        `json:"next_cursor,omitempty"`
+}
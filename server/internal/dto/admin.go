@@ -0,0 +1,77 @@
+package dto
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+type TaskUpsertDTO struct {
+	Slug        string              `json:"slug" binding:"max=255"`
+	Title       string              `json:"title" binding:"required,max=255"`
+	Description string              `json:"description" binding:"max=512"`
+	Type        string              `json:"type" binding:"required"`
+	Language    string              `json:"language" binding:"required,max=50"`
+	Difficulty  string              `json:"difficulty" binding:"required"`
+	Points      int                 `json:"points" binding:"min=0"`
+	XP          int                 `json:"xp" binding:"min=0"`
+	IsActive    *bool               `json:"is_active"`
+	Questions   []QuestionUpsertDTO `json:"questions" binding:"dive"`
+}
+
+type QuestionUpsertDTO struct {
+	Slug           string         `json:"slug" binding:"max=255"`
+	QuestionText   string         `json:"question_text" binding:"required"`
+	Type           string         `json:"type" binding:"required"`
+	Options        datatypes.JSON `json:"options"`
+	GradingConfig  datatypes.JSON `json:"grading_config"`
+	CorrectAnswer  string         `json:"correct_answer" binding:"max=255"`
+	TestInput      string         `json:"test_input"`
+	ExpectedOutput string         `json:"expected_output"`
+	Explanation    string         `json:"explanation"`
+}
+
+type TaskActiveDTO struct {
+	IsActive *bool `json:"is_active" binding:"required"`
+}
+
+type ReorderQuestionsDTO struct {
+	QuestionIDs []uint `json:"question_ids" binding:"required,min=1"`
+}
+
+type BadgeUpsertDTO struct {
+	Slug        string `json:"slug" binding:"max=255"`
+	Name        string `json:"name" binding:"required,max=255"`
+	Description string `json:"description" binding:"max=512"`
+	IconURL     string `json:"icon_url" binding:"max=255"`
+	Requirement string `json:"requirement" binding:"required,max=255"`
+}
+
+type SeasonUpsertDTO struct {
+	Name     string    `json:"name" binding:"required,max=255"`
+	StartsAt time.Time `json:"starts_at" binding:"required"`
+	EndsAt   time.Time `json:"ends_at" binding:"required"`
+}
+
+type ShopItemUpsertDTO struct {
+	Slug        string `json:"slug" binding:"max=255"`
+	Name        string `json:"name" binding:"required,max=255"`
+	Description string `json:"description" binding:"max=512"`
+	Kind        string `json:"kind" binding:"required"`
+	Price       int    `json:"price" binding:"min=0"`
+	AssetURL    string `json:"asset_url" binding:"max=255"`
+	IsActive    *bool  `json:"is_active"`
+}
+
+// MultiplierEventUpsertDTO schedules a multiplier event. Omitted factors default to 1, so
+// "double XP" only needs xp_factor.
+type MultiplierEventUpsertDTO struct {
+	Name         string    `json:"name" binding:"required,max=255"`
+	StartsAt     time.Time `json:"starts_at" binding:"required"`
+	EndsAt       time.Time `json:"ends_at" binding:"required"`
+	Language     string    `json:"language" binding:"max=50"`
+	Difficulty   string    `json:"difficulty" binding:"max=50"`
+	TaskType     string    `json:"task_type" binding:"max=50"`
+	XPFactor     *float64  `json:"xp_factor"`
+	PointsFactor *float64  `json:"points_factor"`
+}
@@ -0,0 +1,29 @@
+package dto
+
+import "time"
+
+type BadgeDTO struct {
+	ID          uint              `json:"id"`
+	Slug        string            `json:"slug"`
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	IconURL     string            `json:"icon_url"`
+	Requirement string            `json:"requirement"`
+	Earned      bool              `json:"earned"`
+	AchievedAt  *time.Time        `json:"achieved_at,omitempty"`
+	Progress    *BadgeProgressDTO `json:"progress,omitempty"`
+}
+
+type BadgeProgressDTO struct {
+	Current int     `json:"current"`
+	Target  int     `json:"target"`
+	Percent float64 `json:"percent"`
+	Label   string  `json:"label"`
+}
+
+type EarnedBadgeDTO struct {
+	ID      uint   `json:"id"`
+	Slug    string `json:"slug"`
+	Name    string `json:"name"`
+	IconURL string `json:"icon_url"`
+}
@@ -0,0 +1,59 @@
+package dto
+
+import "time"
+
+// CreateDuelDTO challenges a friend on a task, or on a random task of Difficulty when TaskID
+// is not set.
+type CreateDuelDTO struct {
+	OpponentID uint   `json:"opponent_id" binding:"required"`
+	TaskID     uint   `json:"task_id"`
+	Difficulty string `json:"difficulty"`
+	Wager      int    `json:"wager" binding:"min=0"`
+}
+
+type DuelAnswerDTO struct {
+	QuestionID uint   `json:"question_id" binding:"required"`
+	Answer     string `json:"answer"`
+}
+
+// DuelPlayerDTO is one side of a duel. The score and time of the other player stay hidden
+// until the duel is over.
+type DuelPlayerDTO struct {
+	User       UserShortInfo `json:"user"`
+	Started    bool          `json:"started"`
+	Finished   bool          `json:"finished"`
+	Answered   *int          `json:"answered,omitempty"`
+	Correct    *int          `json:"correct,omitempty"`
+	DurationMs *int64        `json:"duration_ms,omitempty"`
+}
+
+type DuelDTO struct {
+	ID         uint              `json:"id"`
+	Status     string            `json:"status"`
+	TaskID     uint              `json:"task_id"`
+	TaskTitle  string            `json:"task_title"`
+	Difficulty string            `json:"difficulty"`
+	Wager      int               `json:"wager"`
+	Challenger DuelPlayerDTO     `json:"challenger"`
+	Opponent   DuelPlayerDTO     `json:"opponent"`
+	WinnerID   *uint             `json:"winner_id,omitempty"`
+	ExpiresAt  time.Time         `json:"expires_at"`
+	AcceptedAt *time.Time        `json:"accepted_at,omitempty"`
+	FinishedAt *time.Time        `json:"finished_at,omitempty"`
+	CreatedAt  time.Time         `json:"created_at"`
+	Questions  []TaskQuestionDTO `json:"questions,omitempty"`
+}
+
+type DuelAnswerResultDTO struct {
+	IsCorrect bool    `json:"is_correct"`
+	Duel      DuelDTO `json:"duel"`
+}
+
+// DuelHistoryDTO is the record between the caller and a friend with their past duels.
+type DuelHistoryDTO struct {
+	FriendID uint      `json:"friend_id"`
+	Wins     int       `json:"wins"`
+	Losses   int       `json:"losses"`
+	Draws    int       `json:"draws"`
+	Duels    []DuelDTO `json:"duels"`
+}
@@ -10,6 +10,8 @@ type UserShortInfo struct {
 	AvatarURL string `json:"avatarURL"`
 	Level     int    `json:"level"`
 	Points    int    `json:"points"`
+	// AvatarFrame is the asset of the user's equipped avatar frame, where it is shown.
+	AvatarFrame string `json:"avatarFrame,omitempty"`
 }
 
 type FriendshipDTO struct {
@@ -17,4 +19,9 @@ type FriendshipDTO struct {
 	Status    string        `json:"status"`
 	CreatedAt time.Time     `json:"createdAt"`
 	OtherUser UserShortInfo `json:"otherUser"` 
-}
\ No newline at end of file
+}
+// RestrictedUserDTO is a user the current user has blocked or muted, and since when.
+type RestrictedUserDTO struct {
+	User  UserShortInfo `json:"user"`
+	Since time.Time     `json:"since"`
+}
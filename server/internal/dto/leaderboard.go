@@ -5,3 +5,11 @@ type LeaderboardEntryDTO struct {
 	Value           int           `json:"value"`          
 	CompletedCourses *int          `json:"completedCourses,omitempty"` 
 }
+
+// LeaderboardAroundDTO is the caller's place on a leaderboard with the users ranked around
+// them. Me is nil when the caller is not ranked.
+type LeaderboardAroundDTO struct {
+	Me      *LeaderboardEntryDTO  `json:"me"`
+	Entries []LeaderboardEntryDTO `json:"entries"`
+	Total   int                   `json:"total"`
+}
@@ -0,0 +1,33 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/Suplice/CodeQuest/internal/models"
+)
+
+// LeagueDTO is the caller's league for the current week. Cohort is nil until the caller earns
+// XP this week, which is what places them in a cohort.
+type LeagueDTO struct {
+	Tier      string                `json:"tier"`
+	WeekStart time.Time             `json:"week_start"`
+	WeekEnd   time.Time             `json:"week_end"`
+	Cohort    *LeagueCohortDTO      `json:"cohort"`
+	History   []models.LeagueMember `json:"history"`
+}
+
+type LeagueCohortDTO struct {
+	ID           uint                `json:"id"`
+	PromoteCount int                 `json:"promote_count"`
+	DemoteCount  int                 `json:"demote_count"`
+	Standings    []LeagueStandingDTO `json:"standings"`
+}
+
+// LeagueStandingDTO is a member's current place in a cohort. Zone is "promotion" or "demotion"
+// when the week ending now would move the member, and empty otherwise.
+type LeagueStandingDTO struct {
+	Rank int           `json:"rank"`
+	User UserShortInfo `json:"user"`
+	XP   int           `json:"xp"`
+	Zone string        `json:"zone,omitempty"`
+}
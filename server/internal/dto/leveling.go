@@ -0,0 +1,18 @@
+package dto
+
+import "github.com/Suplice/CodeQuest/internal/leveling"
+
+type LevelUpDTO struct {
+	PreviousLevel int `json:"previous_level"`
+	Level         int `json:"level"`
+}
+
+type LevelThresholdDTO struct {
+	Level int `json:"level"`
+	XP    int `json:"xp"`
+}
+
+type LevelingCurveDTO struct {
+	Curve   leveling.Curve      `json:"curve"`
+	Preview []LevelThresholdDTO `json:"preview"`
+}
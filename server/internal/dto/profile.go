@@ -7,6 +7,8 @@ import (
 type FriendshipStatusDTO struct {
 	Status       string `json:"status"` 
 	FriendshipID uint   `json:"friendshipId,omitempty"` 
+	// Muted is set when the viewer muted the profile user.
+	Muted bool `json:"muted,omitempty"`
 }
 
 type ProfileDTO struct {
@@ -14,6 +16,7 @@ type ProfileDTO struct {
 	TotalCompleted     int64              `json:"totalCompleted"`    
 	TotalMistakes      int64              `json:"totalMistakes"`     
 	TasksWithProgress  []TaskForUserDTO   `json:"tasksWithProgress"` 
+	Badges             []BadgeDTO         `json:"badges"`
 	FriendshipWithView *FriendshipStatusDTO `json:"friendshipWithView,omitempty"` 
 }
 
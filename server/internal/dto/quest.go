@@ -0,0 +1,29 @@
+package dto
+
+import "time"
+
+// QuestsDTO is the caller's quests for the current day and week in their timezone.
+type QuestsDTO struct {
+	Daily  QuestPeriodDTO `json:"daily"`
+	Weekly QuestPeriodDTO `json:"weekly"`
+}
+
+type QuestPeriodDTO struct {
+	Start    time.Time  `json:"start"`
+	ResetsAt time.Time  `json:"resets_at"`
+	Quests   []QuestDTO `json:"quests"`
+}
+
+type QuestDTO struct {
+	ID           uint       `json:"id"`
+	Slug         string     `json:"slug"`
+	Title        string     `json:"title"`
+	Difficulty   string     `json:"difficulty,omitempty"`
+	Language     string     `json:"language,omitempty"`
+	Progress     int        `json:"progress"`
+	Target       int        `json:"target"`
+	RewardXP     int        `json:"reward_xp"`
+	RewardPoints int        `json:"reward_points"`
+	Completed    bool       `json:"completed"`
+	CompletedAt  *time.Time `json:"completed_at,omitempty"`
+}
@@ -0,0 +1,42 @@
+package dto
+
+import "github.com/Suplice/CodeQuest/internal/models"
+
+// ShopItemDTO is a catalog entry with how many of it the caller holds.
+type ShopItemDTO struct {
+	models.ShopItem
+	Owned int `json:"owned"`
+}
+
+// InventoryDTO is what the caller holds: their balance, streak freezes, equipped cosmetics
+// and inventory items.
+type InventoryDTO struct {
+	Points        int               `json:"points"`
+	StreakFreezes int               `json:"streak_freezes"`
+	AvatarFrame   string            `json:"avatar_frame"`
+	ProfileTheme  string            `json:"profile_theme"`
+	Items         []models.UserItem `json:"items"`
+}
+
+type ShopPurchaseDTO struct {
+	Item          models.ShopItem `json:"item"`
+	Quantity      int             `json:"quantity"`
+	Points        int             `json:"points"`
+	StreakFreezes int             `json:"streak_freezes"`
+}
+
+// PurchaseItemDTO buys quantity of an item, one when omitted.
+type PurchaseItemDTO struct {
+	Quantity int `json:"quantity"`
+}
+
+// UseHintDTO asks for the hint of a question, spending a hint token the first time.
+type UseHintDTO struct {
+	QuestionID uint `json:"questionId" binding:"required"`
+}
+
+type QuestionHintDTO struct {
+	QuestionID       uint    `json:"question_id"`
+	EliminatedOption *string `json:"eliminated_option,omitempty"`
+	FirstCharacter   *string `json:"first_character,omitempty"`
+}
@@ -0,0 +1,41 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/Suplice/CodeQuest/internal/models"
+)
+
+type StreakSummaryDTO struct {
+	Current        int                    `json:"current"`
+	Longest        int                    `json:"longest"`
+	ActiveToday    bool                   `json:"active_today"`
+	LastActiveDate time.Time              `json:"last_active_date"`
+	Timezone       string                 `json:"timezone"`
+	Freezes        int                    `json:"freezes"`
+	MaxFreezes     int                    `json:"max_freezes"`
+	FreezePrice    int                    `json:"freeze_price"`
+	Repair         *StreakRepairDTO       `json:"repair,omitempty"`
+	History        []models.StreakHistory `json:"history"`
+}
+
+// StreakRepairDTO describes a broken streak that can still be bought back.
+type StreakRepairDTO struct {
+	Length    int       `json:"length"`
+	Price     int       `json:"price"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type BuyFreezesDTO struct {
+	Quantity int `json:"quantity" binding:"required,min=1"`
+}
+
+type StreakPurchaseDTO struct {
+	Points      int `json:"points"`
+	Freezes     int `json:"freezes"`
+	StreakCount int `json:"streak_count"`
+}
+
+type TimezoneDTO struct {
+	Timezone string `json:"timezone" binding:"required,max=64"`
+}
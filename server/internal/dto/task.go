@@ -0,0 +1,20 @@
+package dto
+
+import (
+	"gorm.io/datatypes"
+)
+
+// TaskQuestionDTO is the client-facing view of a question. Answer fields stay empty
+// until the user has answered the question correctly or completed the whole task.
+type TaskQuestionDTO struct {
+	ID             uint           `json:"ID"`
+	TaskID         uint           `json:"task_id"`
+	QuestionText   string         `json:"question_text"`
+	Type           string         `json:"type"`
+	Options        datatypes.JSON `json:"options"`
+	IsRevealed     bool           `json:"is_revealed"`
+	CorrectAnswer  *string        `json:"correct_answer,omitempty"`
+	TestInput      *string        `json:"test_input,omitempty"`
+	ExpectedOutput *string        `json:"expected_output,omitempty"`
+	Explanation    *string        `json:"explanation,omitempty"`
+}
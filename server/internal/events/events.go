@@ -0,0 +1,95 @@
+package events
+
+import (
+	"log/slog"
+	"sync"
+	"time"
+)
+
+type Type string
+
+const (
+	// AnswerSubmitted carries the question ID, whether the answer was correct and whether it
+	// was the first correct answer to the question in its payload.
+	AnswerSubmitted Type = "answer_submitted"
+	// TaskCompleted is emitted once per user and task, after the rewards have been committed.
+	TaskCompleted Type = "task_completed"
+	BadgeEarned   Type = "badge_earned"
+	// LevelUp carries the previous and new level in its payload.
+	LevelUp         Type = "level_up"
+	FriendAccepted  Type = "friend_accepted"
+	StreakMilestone Type = "streak_milestone"
+	// QuestCompleted carries the quest's slug, title and period; XP and Points are its reward.
+	QuestCompleted Type = "quest_completed"
+	// PointsChanged is emitted after points were staked, paid out, refunded or spent, i.e.
+	// changed other than by a task or quest reward. Only UserID is set.
+	PointsChanged Type = "points_changed"
+)
+
+type Event struct {
+	Type       Type           `json:"type"`
+	UserID     uint           `json:"user_id"`
+	TaskID     uint           `json:"task_id,omitempty"`
+	BadgeID    uint           `json:"badge_id,omitempty"`
+	XP         int            `json:"xp,omitempty"`
+	Points     int            `json:"points,omitempty"`
+	Payload    map[string]any `json:"payload,omitempty"`
+	OccurredAt time.Time      `json:"occurred_at"`
+}
+
+// Handler reacts to an event and may return follow-up events, which are dispatched in turn.
+type Handler func(Event) ([]Event, error)
+
+// Dispatcher delivers events synchronously to their subscribers. Handler errors are logged
+// rather than returned: by the time an event is dispatched the action that caused it has
+// already been committed and must not be reported as failed.
+type Dispatcher struct {
+	mu       sync.RWMutex
+	handlers map[Type][]Handler
+	logger   *slog.Logger
+}
+
+func NewDispatcher(logger *slog.Logger) *Dispatcher {
+	return &Dispatcher{handlers: make(map[Type][]Handler), logger: logger}
+}
+
+func (d *Dispatcher) Subscribe(eventType Type, handler Handler) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.handlers[eventType] = append(d.handlers[eventType], handler)
+}
+
+// Dispatch delivers the event and every follow-up event it causes, returning the follow-ups
+// in the order they were emitted.
+func (d *Dispatcher) Dispatch(event Event) []Event {
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now()
+	}
+
+	var emitted []Event
+	queue := []Event{event}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		d.mu.RLock()
+		handlers := d.handlers[current.Type]
+		d.mu.RUnlock()
+
+		for _, handler := range handlers {
+			followUps, err := handler(current)
+			if err != nil {
+				d.logger.Error("Event handler failed", "err", err, "type", current.Type, "userID", current.UserID)
+				continue
+			}
+			for _, followUp := range followUps {
+				if followUp.OccurredAt.IsZero() {
+					followUp.OccurredAt = time.Now()
+				}
+				emitted = append(emitted, followUp)
+				queue = append(queue, followUp)
+			}
+		}
+	}
+	return emitted
+}
@@ -0,0 +1,120 @@
+package grading
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+var (
+	ErrUnknownStrategy = errors.New("unknown grading strategy")
+	ErrInvalidConfig   = errors.New("invalid grading configuration")
+)
+
+// Config is the per-question grading configuration stored in TaskQuestion.GradingConfig.
+// An empty config falls back to the default strategy registered for the question type.
+type Config struct {
+	Strategy      string   `json:"strategy,omitempty" yaml:"strategy,omitempty"`
+	CaseSensitive bool     `json:"case_sensitive,omitempty" yaml:"case_sensitive,omitempty"`
+	Pattern       string   `json:"pattern,omitempty" yaml:"pattern,omitempty"`
+	Tolerance     float64  `json:"tolerance,omitempty" yaml:"tolerance,omitempty"`
+	Accepted      []string `json:"accepted,omitempty" yaml:"accepted,omitempty"`
+}
+
+type Grader interface {
+	Grade(given string, correct string, cfg Config) (bool, error)
+	Validate(correct string, cfg Config) error
+}
+
+type Registry struct {
+	mu         sync.RWMutex
+	strategies map[string]Grader
+	defaults   map[string]string
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		strategies: make(map[string]Grader),
+		defaults:   make(map[string]string),
+	}
+}
+
+// NewDefaultRegistry returns a registry with all built-in strategies and the defaults
+// used for the question types shipped with CodeQuest.
+func NewDefaultRegistry() *Registry {
+	r := NewRegistry()
+	r.Register(StrategyExact, exactGrader{})
+	r.Register(StrategyCaseInsensitive, caseInsensitiveGrader{})
+	r.Register(StrategyWhitespace, whitespaceGrader{})
+	r.Register(StrategyRegex, regexGrader{})
+	r.Register(StrategyNumeric, numericGrader{})
+	r.Register(StrategyAnyOf, anyOfGrader{})
+	r.Register(StrategyOutput, outputGrader{})
+
+	// Quiz answers have always been compared ignoring case, so existing questions keep grading alike.
+	r.SetDefault("QUIZ", StrategyCaseInsensitive)
+	r.SetDefault("FILL_BLANK", StrategyWhitespace)
+	r.SetDefault("CODE", StrategyOutput)
+	return r
+}
+
+func (r *Registry) Register(name string, grader Grader) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.strategies[name] = grader
+}
+
+func (r *Registry) SetDefault(questionType string, strategy string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.defaults[questionType] = strategy
+}
+
+func (r *Registry) resolve(questionType string, rawConfig []byte) (Grader, Config, error) {
+	cfg, err := ParseConfig(rawConfig)
+	if err != nil {
+		return nil, cfg, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	name := cfg.Strategy
+	if name == "" {
+		name = r.defaults[questionType]
+	}
+	grader, ok := r.strategies[name]
+	if !ok {
+		return nil, cfg, fmt.Errorf("%w: %q", ErrUnknownStrategy, name)
+	}
+	return grader, cfg, nil
+}
+
+func (r *Registry) Grade(questionType string, rawConfig []byte, given string, correct string) (bool, error) {
+	grader, cfg, err := r.resolve(questionType, rawConfig)
+	if err != nil {
+		return false, err
+	}
+	return grader.Grade(given, correct, cfg)
+}
+
+// Validate checks that a question's configuration can be graded, e.g. that a regex compiles.
+func (r *Registry) Validate(questionType string, rawConfig []byte, correct string) error {
+	grader, cfg, err := r.resolve(questionType, rawConfig)
+	if err != nil {
+		return err
+	}
+	return grader.Validate(correct, cfg)
+}
+
+func ParseConfig(raw []byte) (Config, error) {
+	var cfg Config
+	if len(raw) == 0 || string(raw) == "null" {
+		return cfg, nil
+	}
+	if err := json.Unmarshal(raw, &cfg); err != nil {
+		return cfg, fmt.Errorf("%w: %v", ErrInvalidConfig, err)
+	}
+	return cfg, nil
+}
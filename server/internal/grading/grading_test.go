@@ -0,0 +1,120 @@
+package grading
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestStrategies(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy string
+		cfg      Config
+		given    string
+		correct  string
+		want     bool
+	}{
+		{"exact match", StrategyExact, Config{}, " fmt ", "fmt", true},
+		{"exact keeps case", StrategyExact, Config{}, "FMT", "fmt", false},
+		{"case insensitive", StrategyCaseInsensitive, Config{}, "FMT ", "fmt", true},
+
+		{"whitespace collapses runs", StrategyWhitespace, Config{}, "SELECT   *\n FROM t", "SELECT * FROM t", true},
+		{"whitespace ignores case", StrategyWhitespace, Config{}, "select * from t", "SELECT * FROM t", true},
+		{"whitespace case sensitive", StrategyWhitespace, Config{CaseSensitive: true}, "select * from t", "SELECT * FROM t", false},
+		{"whitespace keeps words apart", StrategyWhitespace, Config{}, "SELECT*FROM t", "SELECT * FROM t", false},
+
+		{"regex matches", StrategyRegex, Config{Pattern: `len\((s|str)\)`}, " len(s) ", "", true},
+		{"regex ignores case", StrategyRegex, Config{Pattern: `len\(s\)`}, "LEN(s)", "", true},
+		{"regex case sensitive", StrategyRegex, Config{Pattern: `len\(s\)`, CaseSensitive: true}, "LEN(s)", "", false},
+		{"regex is anchored", StrategyRegex, Config{Pattern: `len\(s\)`}, "x := len(s)", "", false},
+		{"regex anchors alternatives", StrategyRegex, Config{Pattern: `a|b`}, "ab", "", false},
+
+		{"numeric equal", StrategyNumeric, Config{}, "42", "42", true},
+		{"numeric within tolerance", StrategyNumeric, Config{Tolerance: 0.01}, "3.14", "3.14159", true},
+		{"numeric outside tolerance", StrategyNumeric, Config{Tolerance: 0.001}, "3.14", "3.14159", false},
+		{"numeric decimal comma", StrategyNumeric, Config{}, "2,5", "2.5", true},
+		{"numeric thousands separator", StrategyNumeric, Config{}, "1,000", "1000", false},
+		{"numeric negative thousands separator", StrategyNumeric, Config{}, "-1,000", "-1000", false},
+		{"numeric comma and dot", StrategyNumeric, Config{}, "1,000.5", "1000.5", false},
+		{"numeric two commas", StrategyNumeric, Config{}, "1,000,000", "1000000", false},
+		{"numeric decimal comma before long fraction", StrategyNumeric, Config{}, "0,125", "0.125", true},
+		{"numeric not a number", StrategyNumeric, Config{}, "forty-two", "42", false},
+
+		{"any of correct answer", StrategyAnyOf, Config{Accepted: []string{"golang"}}, "Go", "go", true},
+		{"any of alternative", StrategyAnyOf, Config{Accepted: []string{"golang"}}, " GoLang ", "go", true},
+		{"any of case sensitive", StrategyAnyOf, Config{Accepted: []string{"golang"}, CaseSensitive: true}, "GoLang", "go", false},
+		{"any of no match", StrategyAnyOf, Config{Accepted: []string{"golang"}}, "rust", "go", false},
+
+		{"output trailing whitespace", StrategyOutput, Config{}, "1 \r\n2\t\n\n", "1\n2", true},
+		{"output leading whitespace", StrategyOutput, Config{}, " 1\n2", "1\n2", false},
+	}
+
+	r := NewDefaultRegistry()
+	for _, tt := range tests {
+		grader := r.strategies[tt.strategy]
+		got, err := grader.Grade(tt.given, tt.correct, tt.cfg)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s: Grade(%q, %q) = %v, want %v", tt.name, tt.given, tt.correct, got, tt.want)
+		}
+	}
+}
+
+func TestDefaults(t *testing.T) {
+	tests := []struct {
+		questionType string
+		given        string
+		correct      string
+		want         bool
+	}{
+		{"QUIZ", "b) Slices", "B) slices", true},
+		{"QUIZ", "A", "B", false},
+		{"FILL_BLANK", "make( []int,  3 )", "make( []int, 3 )", true},
+		{"CODE", "hello\n", "hello", true},
+	}
+
+	r := NewDefaultRegistry()
+	for _, tt := range tests {
+		got, err := r.Grade(tt.questionType, nil, tt.given, tt.correct)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.questionType, err)
+		}
+		if got != tt.want {
+			t.Errorf("%s: Grade(%q, %q) = %v, want %v", tt.questionType, tt.given, tt.correct, got, tt.want)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  string
+		correct string
+		wantErr error
+	}{
+		{"default strategy", ``, "A", nil},
+		{"unknown strategy", `{"strategy":"fuzzy"}`, "A", ErrUnknownStrategy},
+		{"malformed config", `{"strategy":`, "A", ErrInvalidConfig},
+		{"regex without pattern", `{"strategy":"regex"}`, "", ErrInvalidConfig},
+		{"regex that does not compile", `{"strategy":"regex","pattern":"("}`, "", ErrInvalidConfig},
+		{"numeric answer not a number", `{"strategy":"numeric"}`, "abc", ErrInvalidConfig},
+		{"numeric answer with thousands separator", `{"strategy":"numeric"}`, "1,000", ErrInvalidConfig},
+		{"numeric negative tolerance", `{"strategy":"numeric","tolerance":-1}`, "1", ErrInvalidConfig},
+		{"any of without alternatives", `{"strategy":"any_of"}`, "go", ErrInvalidConfig},
+		{"any of", `{"strategy":"any_of","accepted":["golang"]}`, "go", nil},
+	}
+
+	r := NewDefaultRegistry()
+	for _, tt := range tests {
+		err := r.Validate("QUIZ", []byte(tt.config), tt.correct)
+		if tt.wantErr == nil && err != nil {
+			t.Errorf("%s: unexpected error %v", tt.name, err)
+		}
+		if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+			t.Errorf("%s: error %v, want %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package grading
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	StrategyExact           = "exact"
+	StrategyCaseInsensitive = "case_insensitive"
+	StrategyWhitespace      = "whitespace"
+	StrategyRegex           = "regex"
+	StrategyNumeric         = "numeric"
+	StrategyAnyOf           = "any_of"
+	StrategyOutput          = "output"
+)
+
+type exactGrader struct{}
+
+func (exactGrader) Grade(given, correct string, cfg Config) (bool, error) {
+	return strings.TrimSpace(given) == strings.TrimSpace(correct), nil
+}
+
+func (exactGrader) Validate(correct string, cfg Config) error {
+	return nil
+}
+
+type caseInsensitiveGrader struct{}
+
+func (caseInsensitiveGrader) Grade(given, correct string, cfg Config) (bool, error) {
+	return strings.EqualFold(strings.TrimSpace(given), strings.TrimSpace(correct)), nil
+}
+
+func (caseInsensitiveGrader) Validate(correct string, cfg Config) error {
+	return nil
+}
+
+// whitespaceGrader collapses runs of whitespace, so "SELECT   *" matches "SELECT *".
+type whitespaceGrader struct{}
+
+func (whitespaceGrader) Grade(given, correct string, cfg Config) (bool, error) {
+	return equalNormalized(given, correct, cfg.CaseSensitive), nil
+}
+
+func (whitespaceGrader) Validate(correct string, cfg Config) error {
+	return nil
+}
+
+type regexGrader struct{}
+
+func (regexGrader) Grade(given, correct string, cfg Config) (bool, error) {
+	re, err := compilePattern(cfg)
+	if err != nil {
+		return false, err
+	}
+	return re.MatchString(strings.TrimSpace(given)), nil
+}
+
+func (regexGrader) Validate(correct string, cfg Config) error {
+	if cfg.Pattern == "" {
+		return fmt.Errorf("%w: regex strategy requires a pattern", ErrInvalidConfig)
+	}
+	_, err := compilePattern(cfg)
+	return err
+}
+
+func compilePattern(cfg Config) (*regexp.Regexp, error) {
+	pattern := "^(?:" + cfg.Pattern + ")$"
+	if !cfg.CaseSensitive {
+		pattern = "(?i)" + pattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidConfig, err)
+	}
+	return re, nil
+}
+
+type numericGrader struct{}
+
+func (numericGrader) Grade(given, correct string, cfg Config) (bool, error) {
+	want, err := parseNumber(correct)
+	if err != nil {
+		return false, fmt.Errorf("%w: correct answer is not a number", ErrInvalidConfig)
+	}
+	got, err := parseNumber(given)
+	if err != nil {
+		return false, nil
+	}
+	return math.Abs(got-want) <= cfg.Tolerance, nil
+}
+
+func (numericGrader) Validate(correct string, cfg Config) error {
+	if _, err := parseNumber(correct); err != nil {
+		return fmt.Errorf("%w: correct answer is not a number", ErrInvalidConfig)
+	}
+	if cfg.Tolerance < 0 {
+		return fmt.Errorf("%w: tolerance cannot be negative", ErrInvalidConfig)
+	}
+	return nil
+}
+
+// thousandsPattern matches numbers like "1,000" where the comma reads as a thousands separator.
+var thousandsPattern = regexp.MustCompile(`^[+-]?[1-9][0-9]{0,2},[0-9]{3}$`)
+
+// parseNumber accepts a comma as the decimal separator ("2,5") only when it is the sole
+// separator and can't be read as a thousands separator.
+func parseNumber(s string) (float64, error) {
+	s = strings.TrimSpace(s)
+	if strings.Contains(s, ",") {
+		if strings.Count(s, ",") > 1 || strings.Contains(s, ".") || thousandsPattern.MatchString(s) {
+			return 0, fmt.Errorf("ambiguous number %q", s)
+		}
+		s = strings.Replace(s, ",", ".", 1)
+	}
+	return strconv.ParseFloat(s, 64)
+}
+
+// anyOfGrader accepts the correct answer or any of the configured alternative spellings.
+type anyOfGrader struct{}
+
+func (anyOfGrader) Grade(given, correct string, cfg Config) (bool, error) {
+	if equalNormalized(given, correct, cfg.CaseSensitive) {
+		return true, nil
+	}
+	for _, accepted := range cfg.Accepted {
+		if equalNormalized(given, accepted, cfg.CaseSensitive) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (anyOfGrader) Validate(correct string, cfg Config) error {
+	if len(cfg.Accepted) == 0 {
+		return fmt.Errorf("%w: any_of strategy requires accepted answers", ErrInvalidConfig)
+	}
+	return nil
+}
+
+// outputGrader compares program output ignoring trailing whitespace on each line
+// and trailing blank lines, which users rarely control precisely.
+type outputGrader struct{}
+
+func (outputGrader) Grade(given, correct string, cfg Config) (bool, error) {
+	return normalizeOutput(given) == normalizeOutput(correct), nil
+}
+
+func (outputGrader) Validate(correct string, cfg Config) error {
+	return nil
+}
+
+func normalizeOutput(s string) string {
+	lines := strings.Split(strings.ReplaceAll(s, "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t")
+	}
+	return strings.TrimRight(strings.Join(lines, "\n"), "\n")
+}
+
+func equalNormalized(a, b string, caseSensitive bool) bool {
+	a = strings.Join(strings.Fields(a), " ")
+	b = strings.Join(strings.Fields(b), " ")
+	if caseSensitive {
+		return a == b
+	}
+	return strings.EqualFold(a, b)
+}
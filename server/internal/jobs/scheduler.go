@@ -0,0 +1,75 @@
+// Package jobs runs periodic background work inside the API process.
+package jobs
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(ctx context.Context) error
+}
+
+// Scheduler runs every job once on start and then on its interval until the context is
+// cancelled. Runs of the same job never overlap; a failed run is logged and retried on the
+// next tick.
+type Scheduler struct {
+	jobs   []Job
+	logger *slog.Logger
+	wg     sync.WaitGroup
+}
+
+func NewScheduler(logger *slog.Logger) *Scheduler {
+	return &Scheduler{logger: logger}
+}
+
+func (s *Scheduler) Add(job Job) {
+	s.jobs = append(s.jobs, job)
+}
+
+func (s *Scheduler) Start(ctx context.Context) {
+	for _, job := range s.jobs {
+		s.wg.Add(1)
+		go s.loop(ctx, job)
+	}
+}
+
+// Wait blocks until every job loop has stopped after the context was cancelled.
+func (s *Scheduler) Wait() {
+	s.wg.Wait()
+}
+
+func (s *Scheduler) loop(ctx context.Context, job Job) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+
+	for {
+		s.run(ctx, job)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Scheduler) run(ctx context.Context, job Job) {
+	defer func() {
+		if r := recover(); r != nil {
+			s.logger.Error("Job panicked", "job", job.Name, "panic", r)
+		}
+	}()
+
+	started := time.Now()
+	if err := job.Run(ctx); err != nil {
+		s.logger.Error("Job failed", "job", job.Name, "err", err, "duration", time.Since(started))
+		return
+	}
+	s.logger.Info("Job finished", "job", job.Name, "duration", time.Since(started))
+}
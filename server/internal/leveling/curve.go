@@ -0,0 +1,129 @@
+package leveling
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Suplice/CodeQuest/internal/models"
+)
+
+const (
+	KindTable     = "table"
+	KindQuadratic = "quadratic"
+)
+
+var ErrInvalidCurve = errors.New("invalid leveling curve")
+
+// Curve defines the total XP needed to reach each level; level 1 always starts at 0 XP.
+//
+// A table curve lists the totals for level 2, 3, ... in Thresholds. Past the end of the table
+// every further level costs as much as the last step. A quadratic curve needs
+// A*(level-1)^2 + B*(level-1) XP. MaxLevel caps either curve, 0 means no cap.
+type Curve struct {
+	Kind       string `json:"kind"`
+	Thresholds []int  `json:"thresholds,omitempty"`
+	A          int    `json:"a,omitempty"`
+	B          int    `json:"b,omitempty"`
+	MaxLevel   int    `json:"max_level,omitempty"`
+}
+
+// Default keeps the original hardcoded thresholds (100, 250, 500, 1000 XP for levels 2-5), so
+// existing users keep their level, and adds a level for every further 500 XP.
+func Default() Curve {
+	return Curve{Kind: KindTable, Thresholds: []int{100, 250, 500, 1000}}
+}
+
+func (c Curve) Validate() error {
+	if c.MaxLevel < 0 {
+		return fmt.Errorf("%w: max_level cannot be negative", ErrInvalidCurve)
+	}
+
+	switch c.Kind {
+	case KindTable:
+		if len(c.Thresholds) == 0 {
+			return fmt.Errorf("%w: a table curve needs at least one threshold", ErrInvalidCurve)
+		}
+		previous := 0
+		for i, t := range c.Thresholds {
+			if t <= previous {
+				return fmt.Errorf("%w: threshold for level %d must be greater than %d", ErrInvalidCurve, i+2, previous)
+			}
+			previous = t
+		}
+	case KindQuadratic:
+		if c.A < 0 || c.B < 0 || c.A+c.B == 0 {
+			return fmt.Errorf("%w: a and b must not be negative and at least one must be positive", ErrInvalidCurve)
+		}
+	default:
+		return fmt.Errorf("%w: kind must be %s or %s", ErrInvalidCurve, KindTable, KindQuadratic)
+	}
+	return nil
+}
+
+// Threshold returns the total XP needed to reach the level.
+func (c Curve) Threshold(level int) int {
+	if level <= 1 {
+		return 0
+	}
+
+	steps := level - 1
+	if c.Kind == KindQuadratic {
+		return c.A*steps*steps + c.B*steps
+	}
+
+	if steps <= len(c.Thresholds) {
+		return c.Thresholds[steps-1]
+	}
+	last := c.Thresholds[len(c.Thresholds)-1]
+	step := last
+	if len(c.Thresholds) > 1 {
+		step = last - c.Thresholds[len(c.Thresholds)-2]
+	}
+	return last + (steps-len(c.Thresholds))*step
+}
+
+// LevelFor returns the level a user with the given total XP has reached.
+func (c Curve) LevelFor(xp int) int {
+	if xp < c.Threshold(2) {
+		return 1
+	}
+
+	// Thresholds grow strictly, so find an upper bound by doubling and binary search below it.
+	low, high := 2, 4
+	for c.Threshold(high) <= xp {
+		low, high = high, high*2
+	}
+	for high-low > 1 {
+		mid := (low + high) / 2
+		if c.Threshold(mid) <= xp {
+			low = mid
+		} else {
+			high = mid
+		}
+	}
+
+	if c.MaxLevel > 0 && low > c.MaxLevel {
+		return c.MaxLevel
+	}
+	return low
+}
+
+// Raise returns the level of a user at current with the given total XP. A level once reached
+// is kept, so a steeper curve or XP taken away never lowers it.
+func (c Curve) Raise(current, xp int) int {
+	return max(current, c.LevelFor(xp))
+}
+
+// Annotate fills the user's derived XP-to-next-level and level progress fields.
+func (c Curve) Annotate(user *models.User) {
+	level := max(user.Level, 1)
+	if c.MaxLevel > 0 && level >= c.MaxLevel {
+		user.XPToNextLevel = 0
+		user.LevelProgress = 100
+		return
+	}
+
+	start, next := c.Threshold(level), c.Threshold(level+1)
+	user.XPToNextLevel = max(next-user.XP, 0)
+	user.LevelProgress = min(max(float64(user.XP-start)/float64(next-start)*100, 0), 100)
+}
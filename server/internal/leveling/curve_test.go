@@ -0,0 +1,41 @@
+package leveling
+
+import "testing"
+
+func TestDefaultKeepsOriginalLevels(t *testing.T) {
+	tests := []struct {
+		xp    int
+		level int
+	}{
+		{0, 1}, {99, 1}, {100, 2}, {249, 2}, {250, 3}, {299, 3},
+		{499, 3}, {500, 4}, {599, 4}, {999, 4}, {1000, 5}, {1499, 5}, {1500, 6},
+	}
+
+	curve := Default()
+	if err := curve.Validate(); err != nil {
+		t.Fatalf("default curve is invalid: %v", err)
+	}
+	for _, tt := range tests {
+		if got := curve.LevelFor(tt.xp); got != tt.level {
+			t.Errorf("LevelFor(%d) = %d, want %d", tt.xp, got, tt.level)
+		}
+	}
+}
+
+func TestRaiseNeverLowersLevel(t *testing.T) {
+	steep := Curve{Kind: KindTable, Thresholds: []int{1000, 5000}}
+	tests := []struct {
+		current, xp, want int
+	}{
+		{1, 0, 1},
+		{1, 1000, 2},
+		{2, 5000, 3},
+		{4, 1200, 4},
+		{5, 0, 5},
+	}
+	for _, tt := range tests {
+		if got := steep.Raise(tt.current, tt.xp); got != tt.want {
+			t.Errorf("Raise(%d, %d) = %d, want %d", tt.current, tt.xp, got, tt.want)
+		}
+	}
+}
@@ -3,19 +3,25 @@ package models
 import (
 	"time"
 
+	"gorm.io/datatypes"
 	"gorm.io/gorm"
 )
 
 
 
+// ActivityLog is one entry of a user's timeline. ActionType is the name of the event that
+// caused it and Metadata holds the event payload.
 type ActivityLog struct {
 	gorm.Model
-	UserID     uint      `gorm:"not null;index" json:"user_id"`
-	ActionType string    `gorm:"size:255;not null" json:"action_type"` 
+	UserID     uint      `gorm:"not null;index;index:idx_activity_logs_user_type_id,priority:1" json:"user_id"`
+	ActionType string    `gorm:"size:255;not null;index:idx_activity_logs_user_type_id,priority:2" json:"action_type"` 
 	Description string   `gorm:"size:512" json:"description"`
 	PointsEarned int     `json:"points_earned"`
 	XPEarned     int     `json:"xp_earned"`
+	TaskID       *uint   `json:"task_id,omitempty"`
+	BadgeID      *uint   `json:"badge_id,omitempty"`
+	Metadata     datatypes.JSON `json:"metadata,omitempty"`
 	Timestamp    time.Time `gorm:"autoCreateTime" json:"timestamp"`
 
 	User User `gorm:"foreignKey:UserID" json:"-"`
-}
\ No newline at end of file
+}
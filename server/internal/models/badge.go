@@ -10,6 +10,7 @@ import (
 
 type Badge struct {
 	gorm.Model
+	Slug         string `gorm:"size:255;uniqueIndex:idx_badges_slug,where:slug <> '' AND deleted_at IS NULL" json:"slug"`
 	Name         string `gorm:"size:255;not null" json:"name"`
 	Description  string `gorm:"size:512" json:"description"`
 	IconURL      string `gorm:"size:255" json:"icon_url"`
@@ -17,8 +18,8 @@ type Badge struct {
 }
 
 type UserBadge struct {
-	UserID  uint `gorm:"not null;index" json:"user_id"`
-	BadgeID uint `gorm:"not null;index" json:"badge_id"`
+	UserID  uint `gorm:"not null;index;uniqueIndex:idx_user_badges_user_badge" json:"user_id"`
+	BadgeID uint `gorm:"not null;index;uniqueIndex:idx_user_badges_user_badge" json:"badge_id"`
 	AchievedAt time.Time `gorm:"autoCreateTime" json:"achieved_at"`
 
 	User  User  `gorm:"foreignKey:UserID" json:"-"`
@@ -6,18 +6,29 @@ import (
 	"gorm.io/gorm"
 )
 
+const (
+	SubmissionStatusPending           = "PENDING"
+	SubmissionStatusAccepted          = "ACCEPTED"
+	SubmissionStatusWrongAnswer       = "WRONG_ANSWER"
+	SubmissionStatusCompileError      = "COMPILE_ERROR"
+	SubmissionStatusRuntimeError      = "RUNTIME_ERROR"
+	SubmissionStatusTimeLimitExceeded = "TIME_LIMIT_EXCEEDED"
+	SubmissionStatusMemoryExceeded    = "MEMORY_LIMIT_EXCEEDED"
+	SubmissionStatusSystemError       = "SYSTEM_ERROR"
+)
 
 type CodeSubmission struct {
 	gorm.Model
-	UserID      uint      `gorm:"not null;index" json:"user_id"`
-	TaskID      uint      `gorm:"not null;index" json:"task_id"`
-	Code        string    `gorm:"type:text" json:"code"`
-	Language    string    `gorm:"size:50" json:"language"`
-	Status      string    `gorm:"size:50" json:"status"` 
-	Output      string    `gorm:"type:text" json:"output"`
-	ErrorMsg    string    `gorm:"type:text" json:"error_msg"`
-	SubmittedAt time.Time `gorm:"autoCreateTime" json:"submitted_at"`
+	UserID         uint      `gorm:"not null;index" json:"user_id"`
+	TaskID         uint      `gorm:"not null;index" json:"task_id"`
+	TaskQuestionID uint      `gorm:"index" json:"task_question_id"`
+	Code           string    `gorm:"type:text" json:"code"`
+	Language       string    `gorm:"size:50" json:"language"`
+	Status         string    `gorm:"size:50" json:"status"` 
+	Output         string    `gorm:"type:text" json:"output"`
+	ErrorMsg       string    `gorm:"type:text" json:"error_msg"`
+	SubmittedAt    time.Time `gorm:"autoCreateTime" json:"submitted_at"`
 
 	User        User      `gorm:"foreignKey:UserID" json:"-"`
 	Task        Task      `gorm:"foreignKey:TaskID" json:"task"`
-}
\ No newline at end of file
+}
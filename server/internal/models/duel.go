@@ -0,0 +1,54 @@
+package models
+
+import "time"
+
+const (
+	DuelStatusPending  = "pending"
+	DuelStatusActive   = "active"
+	DuelStatusFinished = "finished"
+	DuelStatusExpired  = "expired"
+	DuelStatusDeclined = "declined"
+)
+
+// Duel is a challenge between two friends on the questions of one task. Both wagers are held
+// in escrow: the challenger's when the duel is created and the opponent's when it is accepted.
+// Each player's clock starts when they first load the questions and stops with their last
+// answer. ExpiresAt is the deadline to accept while pending and to finish while active.
+type Duel struct {
+	ID                   uint       `gorm:"primarykey" json:"id"`
+	ChallengerID         uint       `gorm:"not null;index" json:"challenger_id"`
+	OpponentID           uint       `gorm:"not null;index" json:"opponent_id"`
+	TaskID               uint       `gorm:"not null" json:"task_id"`
+	Wager                int        `gorm:"not null;default:0" json:"wager"`
+	Status               string     `gorm:"size:20;not null;index:idx_duels_status_expires,priority:1" json:"status"`
+	WinnerID             *uint      `json:"winner_id,omitempty"`
+	ChallengerCorrect    int        `gorm:"not null;default:0" json:"challenger_correct"`
+	OpponentCorrect      int        `gorm:"not null;default:0" json:"opponent_correct"`
+	ChallengerStartedAt  *time.Time `json:"challenger_started_at,omitempty"`
+	OpponentStartedAt    *time.Time `json:"opponent_started_at,omitempty"`
+	ChallengerFinishedAt *time.Time `json:"challenger_finished_at,omitempty"`
+	OpponentFinishedAt   *time.Time `json:"opponent_finished_at,omitempty"`
+	AcceptedAt           *time.Time `json:"accepted_at,omitempty"`
+	FinishedAt           *time.Time `json:"finished_at,omitempty"`
+	ExpiresAt            time.Time  `gorm:"not null;index:idx_duels_status_expires,priority:2" json:"expires_at"`
+	CreatedAt            time.Time  `json:"created_at"`
+	UpdatedAt            time.Time  `json:"updated_at"`
+
+	Challenger User         `gorm:"foreignKey:ChallengerID" json:"-"`
+	Opponent   User         `gorm:"foreignKey:OpponentID" json:"-"`
+	Task       Task         `gorm:"foreignKey:TaskID" json:"-"`
+	Answers    []DuelAnswer `gorm:"foreignKey:DuelID" json:"-"`
+}
+
+// DuelAnswer is a player's only attempt at one question of a duel.
+type DuelAnswer struct {
+	ID             uint      `gorm:"primarykey" json:"id"`
+	DuelID         uint      `gorm:"not null;uniqueIndex:idx_duel_answers_duel_user_question,priority:1" json:"duel_id"`
+	UserID         uint      `gorm:"not null;uniqueIndex:idx_duel_answers_duel_user_question,priority:2" json:"user_id"`
+	TaskQuestionID uint      `gorm:"not null;uniqueIndex:idx_duel_answers_duel_user_question,priority:3" json:"task_question_id"`
+	Answer         string    `gorm:"type:text" json:"answer"`
+	IsCorrect      bool      `gorm:"not null" json:"is_correct"`
+	CreatedAt      time.Time `json:"created_at"`
+
+	Duel Duel `gorm:"foreignKey:DuelID;constraint:OnDelete:CASCADE" json:"-"`
+}
@@ -11,6 +11,9 @@ type Friendship struct {
 	FriendID   uint      `gorm:"not null;index" json:"friend_id"`
 	Status     string    `gorm:"size:50;not null" json:"status"` 
 	CreatedAt  time.Time `gorm:"autoCreateTime" json:"created_at"`
+	// DeclinedAt is set when the request was declined. Declined requests are kept so the
+	// sender has to wait before asking again.
+	DeclinedAt *time.Time `json:"declined_at,omitempty"`
 
 	User   User `gorm:"foreignKey:UserID" json:"-"`
 	Friend User `gorm:"foreignKey:FriendID" json:"friend"`
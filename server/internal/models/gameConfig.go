@@ -0,0 +1,16 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+const GameConfigLevelingCurve = "leveling_curve"
+
+// GameConfig holds admin-editable game settings as JSON documents keyed by name.
+type GameConfig struct {
+	Key       string         `gorm:"primaryKey;size:100" json:"key"`
+	Value     datatypes.JSON `gorm:"not null" json:"value"`
+	UpdatedAt time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+}
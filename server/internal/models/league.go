@@ -0,0 +1,48 @@
+package models
+
+import "time"
+
+const (
+	LeagueBronze   = "bronze"
+	LeagueSilver   = "silver"
+	LeagueGold     = "gold"
+	LeaguePlatinum = "platinum"
+	LeagueDiamond  = "diamond"
+)
+
+// LeagueTiers lists the tiers from lowest to highest.
+var LeagueTiers = []string{LeagueBronze, LeagueSilver, LeagueGold, LeaguePlatinum, LeagueDiamond}
+
+const (
+	LeagueResultPromoted = "promoted"
+	LeagueResultDemoted  = "demoted"
+	LeagueResultStayed   = "stayed"
+)
+
+// LeagueCohort is a group of users of one tier competing on XP earned during the UTC week
+// starting at WeekStart. FinalizedAt is set once the week is over and results are stored.
+type LeagueCohort struct {
+	ID          uint       `gorm:"primarykey" json:"id"`
+	WeekStart   time.Time  `gorm:"not null;index:idx_league_cohorts_week_tier,priority:1" json:"week_start"`
+	Tier        string     `gorm:"size:20;not null;index:idx_league_cohorts_week_tier,priority:2" json:"tier"`
+	MemberCount int        `gorm:"not null;default:0" json:"member_count"`
+	FinalizedAt *time.Time `json:"finalized_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// LeagueMember places a user in a cohort for one week. XP, Rank and Result are filled in when
+// the cohort is finalized and make up the user's league history.
+type LeagueMember struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CohortID  uint      `gorm:"not null;index" json:"cohort_id"`
+	UserID    uint      `gorm:"not null;uniqueIndex:idx_league_members_user_week,priority:1" json:"user_id"`
+	WeekStart time.Time `gorm:"not null;uniqueIndex:idx_league_members_user_week,priority:2" json:"week_start"`
+	Tier      string    `gorm:"size:20;not null" json:"tier"`
+	XP        int       `gorm:"not null;default:0" json:"xp"`
+	Rank      int       `gorm:"not null;default:0" json:"rank"`
+	Result    string    `gorm:"size:20;not null;default:''" json:"result"`
+	CreatedAt time.Time `json:"created_at"`
+
+	Cohort LeagueCohort `gorm:"foreignKey:CohortID;constraint:OnDelete:CASCADE" json:"-"`
+	User   User         `gorm:"foreignKey:UserID" json:"-"`
+}
@@ -0,0 +1,30 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// MultiplierEvent multiplies the rewards of tasks completed during [StartsAt, EndsAt), e.g. a
+// double XP weekend. Empty Language, Difficulty and TaskType match every task. Events running
+// at the same time stack: their factors are multiplied.
+type MultiplierEvent struct {
+	ID           uint      `gorm:"primarykey" json:"id"`
+	Name         string    `gorm:"size:255;not null" json:"name"`
+	StartsAt     time.Time `gorm:"not null;index:idx_multiplier_events_window,priority:1" json:"starts_at"`
+	EndsAt       time.Time `gorm:"not null;index:idx_multiplier_events_window,priority:2" json:"ends_at"`
+	Language     string    `gorm:"size:50;not null;default:''" json:"language,omitempty"`
+	Difficulty   string    `gorm:"size:50;not null;default:''" json:"difficulty,omitempty"`
+	TaskType     string    `gorm:"size:50;not null;default:''" json:"task_type,omitempty"`
+	XPFactor     float64   `gorm:"not null" json:"xp_factor"`
+	PointsFactor float64   `gorm:"not null" json:"points_factor"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// Matches reports whether the event's scope covers the task.
+func (e MultiplierEvent) Matches(task Task) bool {
+	return (e.Language == "" || strings.EqualFold(e.Language, task.Language)) &&
+		(e.Difficulty == "" || e.Difficulty == task.Difficulty) &&
+		(e.TaskType == "" || e.TaskType == task.Type)
+}
@@ -0,0 +1,29 @@
+package models
+
+import "testing"
+
+func TestMultiplierEventMatches(t *testing.T) {
+	task := Task{Language: "go", Difficulty: DifficultyEasy, Type: TaskTypeQuiz}
+
+	tests := []struct {
+		name  string
+		event MultiplierEvent
+		want  bool
+	}{
+		{"unscoped", MultiplierEvent{}, true},
+		{"same language", MultiplierEvent{Language: "go"}, true},
+		{"language ignores case", MultiplierEvent{Language: "Go"}, true},
+		{"other language", MultiplierEvent{Language: "python"}, false},
+		{"same difficulty", MultiplierEvent{Difficulty: DifficultyEasy}, true},
+		{"other difficulty", MultiplierEvent{Difficulty: DifficultyHard}, false},
+		{"same type", MultiplierEvent{TaskType: TaskTypeQuiz}, true},
+		{"other type", MultiplierEvent{TaskType: TaskTypeCode}, false},
+		{"full scope", MultiplierEvent{Language: "go", Difficulty: DifficultyEasy, TaskType: TaskTypeQuiz}, true},
+		{"one field off", MultiplierEvent{Language: "go", Difficulty: DifficultyEasy, TaskType: TaskTypeFillBlank}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.event.Matches(task); got != tt.want {
+			t.Errorf("%s: Matches = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+const (
+	PointTransactionTaskReward = "task_reward"
+	// Freezes are now bought in the shop; older ledgers still hold freeze_purchase entries.
+	PointTransactionFreezePurchase = "freeze_purchase"
+	PointTransactionFreezeUsed     = "freeze_used"
+	PointTransactionStreakRepair   = "streak_repair"
+	PointTransactionDuelWager      = "duel_wager"
+	PointTransactionDuelPayout     = "duel_payout"
+	PointTransactionDuelRefund     = "duel_refund"
+	PointTransactionQuestReward    = "quest_reward"
+	PointTransactionShopPurchase   = "shop_purchase"
+)
+
+// PointTransaction is one entry in a user's ledger. Points and Freezes are signed changes to
+// User.Points and User.StreakFreezes and XP is the experience granted with a task or quest
+// reward; ReferenceID points at the task, streak history row, duel, quest or shop item the
+// entry is about.
+type PointTransaction struct {
+	ID          uint      `gorm:"primarykey" json:"id"`
+	UserID      uint      `gorm:"not null;index:idx_point_transactions_user_created" json:"user_id"`
+	Kind        string    `gorm:"size:50;not null;index:idx_point_transactions_kind_created,priority:1" json:"kind"`
+	Points      int       `gorm:"not null;default:0" json:"points"`
+	Freezes     int       `gorm:"not null;default:0" json:"freezes"`
+	XP          int       `gorm:"not null;default:0" json:"xp"`
+	ReferenceID *uint     `json:"reference_id,omitempty"`
+	CreatedAt   time.Time `gorm:"index:idx_point_transactions_user_created;index:idx_point_transactions_kind_created,priority:2" json:"created_at"`
+
+	// XPMultiplier and PointsMultiplier are the multiplier event factors applied to a task
+	// reward, 1 when no event was running.
+	XPMultiplier     float64 `gorm:"not null;default:1" json:"xp_multiplier"`
+	PointsMultiplier float64 `gorm:"not null;default:1" json:"points_multiplier"`
+
+	User User `gorm:"foreignKey:UserID" json:"-"`
+}
@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// UserQuest is a quest assigned to a user for the day or week starting at PeriodStart, a
+// calendar date in the user's timezone. The template's goal and reward are copied when the
+// quest is assigned, so later changes to the template do not affect it.
+type UserQuest struct {
+	ID           uint       `gorm:"primarykey" json:"id"`
+	UserID       uint       `gorm:"not null;uniqueIndex:idx_user_quests_user_slug_period,priority:1;index:idx_user_quests_user_period,priority:1" json:"user_id"`
+	Slug         string     `gorm:"size:100;not null;uniqueIndex:idx_user_quests_user_slug_period,priority:2" json:"slug"`
+	Period       string     `gorm:"size:20;not null" json:"period"`
+	PeriodStart  time.Time  `gorm:"type:date;not null;uniqueIndex:idx_user_quests_user_slug_period,priority:3;index:idx_user_quests_user_period,priority:2" json:"period_start"`
+	Title        string     `gorm:"size:255;not null" json:"title"`
+	Metric       string     `gorm:"size:50;not null" json:"metric"`
+	Difficulty   string     `gorm:"size:20;not null;default:''" json:"difficulty,omitempty"`
+	Language     string     `gorm:"size:50;not null;default:''" json:"language,omitempty"`
+	Target       int        `gorm:"not null" json:"target"`
+	Progress     int        `gorm:"not null;default:0" json:"progress"`
+	RewardXP     int        `gorm:"not null;default:0" json:"reward_xp"`
+	RewardPoints int        `gorm:"not null;default:0" json:"reward_points"`
+	CompletedAt  *time.Time `json:"completed_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+
+	User User `gorm:"foreignKey:UserID" json:"-"`
+}
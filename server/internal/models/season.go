@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// Season is a time window for the seasonal leaderboard, [StartsAt, EndsAt). Once it has ended
+// its final standings are copied into SeasonStanding and ArchivedAt is set.
+type Season struct {
+	ID         uint       `gorm:"primarykey" json:"id"`
+	Name       string     `gorm:"size:255;not null" json:"name"`
+	StartsAt   time.Time  `gorm:"not null;index" json:"starts_at"`
+	EndsAt     time.Time  `gorm:"not null" json:"ends_at"`
+	ArchivedAt *time.Time `json:"archived_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// SeasonStanding is a user's final rank in an archived season for one leaderboard criteria.
+type SeasonStanding struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	SeasonID  uint      `gorm:"not null;uniqueIndex:idx_season_standings_season_criteria_user,priority:1;index:idx_season_standings_season_criteria_rank,priority:1" json:"season_id"`
+	Criteria  string    `gorm:"size:50;not null;uniqueIndex:idx_season_standings_season_criteria_user,priority:2;index:idx_season_standings_season_criteria_rank,priority:2" json:"criteria"`
+	UserID    uint      `gorm:"not null;uniqueIndex:idx_season_standings_season_criteria_user,priority:3" json:"user_id"`
+	Rank      int       `gorm:"not null;index:idx_season_standings_season_criteria_rank,priority:3" json:"rank"`
+	Value     int       `gorm:"not null" json:"value"`
+	CreatedAt time.Time `json:"created_at"`
+
+	Season Season `gorm:"foreignKey:SeasonID;constraint:OnDelete:CASCADE" json:"-"`
+	User   User   `gorm:"foreignKey:UserID" json:"-"`
+}
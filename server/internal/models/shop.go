@@ -0,0 +1,57 @@
+package models
+
+import "time"
+
+const (
+	ShopItemAvatarFrame  = "avatar_frame"
+	ShopItemProfileTheme = "profile_theme"
+	ShopItemHintToken    = "hint_token"
+	ShopItemStreakFreeze = "streak_freeze"
+)
+
+var ShopItemKinds = []string{ShopItemAvatarFrame, ShopItemProfileTheme, ShopItemHintToken, ShopItemStreakFreeze}
+
+// IsCosmeticItem reports whether items of the kind are owned once and equipped, rather than
+// bought in quantity and used up.
+func IsCosmeticItem(kind string) bool {
+	return kind == ShopItemAvatarFrame || kind == ShopItemProfileTheme
+}
+
+// ShopItem is an item users can buy with points. AssetURL is what clients render for a
+// cosmetic item; inactive items are no longer sold but stay with the users who bought them.
+type ShopItem struct {
+	ID          uint      `gorm:"primarykey" json:"id"`
+	Slug        string    `gorm:"size:255;not null;uniqueIndex" json:"slug"`
+	Name        string    `gorm:"size:255;not null" json:"name"`
+	Description string    `gorm:"size:512" json:"description"`
+	Kind        string    `gorm:"size:50;not null" json:"kind"`
+	Price       int       `gorm:"not null" json:"price"`
+	AssetURL    string    `gorm:"size:255;not null;default:''" json:"asset_url"`
+	IsActive    bool      `gorm:"not null" json:"is_active"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// UserItem is an entry in a user's inventory. Streak freezes are kept on User.StreakFreezes
+// instead, where the streak job spends them.
+type UserItem struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	UserID    uint      `gorm:"not null;uniqueIndex:idx_user_items_user_item,priority:1" json:"user_id"`
+	ItemID    uint      `gorm:"not null;uniqueIndex:idx_user_items_user_item,priority:2" json:"item_id"`
+	Quantity  int       `gorm:"not null;default:0" json:"quantity"`
+	Equipped  bool      `gorm:"not null;default:false" json:"equipped"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	Item ShopItem `gorm:"foreignKey:ItemID" json:"item"`
+	User User     `gorm:"foreignKey:UserID" json:"-"`
+}
+
+// HintUnlock records that a user spent a hint token on a question, so showing the hint again
+// is free.
+type HintUnlock struct {
+	ID             uint      `gorm:"primarykey" json:"id"`
+	UserID         uint      `gorm:"not null;uniqueIndex:idx_hint_unlocks_user_question,priority:1" json:"user_id"`
+	TaskQuestionID uint      `gorm:"not null;uniqueIndex:idx_hint_unlocks_user_question,priority:2" json:"task_question_id"`
+	CreatedAt      time.Time `json:"created_at"`
+}
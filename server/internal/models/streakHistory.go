@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// StreakHistory records a streak once it has ended. StartedOn and EndedOn are calendar days
+// in the user's timezone at the time the streak ended; BrokenAt is the local midnight after
+// the first missed day, when the streak lapsed.
+type StreakHistory struct {
+	ID         uint       `gorm:"primarykey" json:"id"`
+	UserID     uint       `gorm:"not null;index" json:"user_id"`
+	Length     int        `gorm:"not null" json:"length"`
+	StartedOn  time.Time  `gorm:"type:date;not null" json:"started_on"`
+	EndedOn    time.Time  `gorm:"type:date;not null" json:"ended_on"`
+	BrokenAt   time.Time  `gorm:"not null" json:"broken_at"`
+	RepairedAt *time.Time `json:"repaired_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+
+	User User `gorm:"foreignKey:UserID" json:"-"`
+}
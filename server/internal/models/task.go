@@ -6,8 +6,24 @@ import (
 	"gorm.io/gorm"
 )
 
+const (
+	TaskTypeQuiz      = "QUIZ"
+	TaskTypeFillBlank = "FILL_BLANK"
+	TaskTypeCode      = "CODE"
+)
+
+const (
+	DifficultyEasy   = "EASY"
+	DifficultyMedium = "MEDIUM"
+	DifficultyHard   = "HARD"
+)
+
+// BlankMarker marks the gap a FILL_BLANK question asks the user to complete.
+const BlankMarker = "___"
+
 type Task struct {
 	gorm.Model
+	Slug         string     `gorm:"size:255;uniqueIndex:idx_tasks_slug,where:slug <> '' AND deleted_at IS NULL" json:"slug"`
 	Title        string     `gorm:"size:255;not null" json:"title"`
 	Description  string     `gorm:"size:512" json:"description"`
 	Type         string     `gorm:"size:50;not null" json:"type"` 
@@ -7,13 +7,17 @@ import (
 
 type TaskQuestion struct {
 	gorm.Model
-	TaskID         uint           `gorm:"not null;index" json:"task_id"`
+	TaskID         uint           `gorm:"not null;index;uniqueIndex:idx_task_questions_slug,where:slug <> '' AND deleted_at IS NULL" json:"task_id"`
+	Slug           string         `gorm:"size:255;uniqueIndex:idx_task_questions_slug,where:slug <> '' AND deleted_at IS NULL" json:"slug"`
 	QuestionText   string         `gorm:"type:text;not null" json:"question_text"`
 	Type           string         `gorm:"size:50;not null" json:"type"` 
 	Options        datatypes.JSON `json:"options"`
+	GradingConfig  datatypes.JSON `json:"grading_config"`
 	CorrectAnswer  string         `gorm:"size:255" json:"correct_answer"`
 	TestInput      string         `gorm:"type:text" json:"test_input"`
 	ExpectedOutput string         `gorm:"type:text" json:"expected_output"`
+	Explanation    string         `gorm:"type:text" json:"explanation"`
+	Position       int            `gorm:"default:0" json:"position"`
 
 	Task Task `gorm:"foreignKey:TaskID" json:"-"`
 }
@@ -22,7 +22,18 @@ type User struct {
 	XP             int       `gorm:"default:0" json:"xp"`
 	Points         int       `gorm:"default:0" json:"points"`
 	StreakCount    int       `gorm:"default:0" json:"streakCount"`
+	LongestStreak  int       `gorm:"default:0" json:"longestStreak"`
+	StreakFreezes  int       `gorm:"default:0" json:"streakFreezes"`
 	LastActiveDate time.Time `json:"lastActiveDate"`
+	Timezone       string    `gorm:"size:64;not null;default:UTC" json:"timezone"`
+	LeagueTier     string    `gorm:"size:20;not null;default:bronze" json:"leagueTier"`
+	// Asset URLs of the equipped shop cosmetics, empty when none is equipped.
+	AvatarFrame    string    `gorm:"size:255;not null;default:''" json:"avatarFrame"`
+	ProfileTheme   string    `gorm:"size:255;not null;default:''" json:"profileTheme"`
+
+	// Derived from the leveling curve when the user is returned, never stored.
+	XPToNextLevel  int       `gorm:"-" json:"xpToNextLevel"`
+	LevelProgress  float64   `gorm:"-" json:"levelProgress"`
 
 	TaskProgress []UserTaskProgress `gorm:"foreignKey:UserID" json:"task_progress"`
 	Friends      []Friendship       `gorm:"foreignKey:UserID" json:"friends"`
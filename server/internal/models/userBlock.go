@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+const (
+	UserBlockKindBlock = "block"
+	UserBlockKindMute  = "mute"
+)
+
+// UserBlock is one user's restriction on another. A block cuts every tie between the two
+// users in both directions; a mute only hides the target's activity from the user's feed.
+// A user holds at most one restriction per target, and a block replaces a mute.
+type UserBlock struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	UserID    uint      `gorm:"not null;uniqueIndex:idx_user_blocks_pair,priority:1" json:"user_id"`
+	TargetID  uint      `gorm:"not null;uniqueIndex:idx_user_blocks_pair,priority:2;index" json:"target_id"`
+	Kind      string    `gorm:"size:50;not null" json:"kind"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+
+	Target User `gorm:"foreignKey:TargetID" json:"-"`
+}
@@ -8,8 +8,8 @@ import (
 
 type UserTaskProgress struct {
     gorm.Model
-    UserID uint `gorm:"not null;index" json:"user_id"`
-    TaskID uint `gorm:"not null;index" json:"task_id"`
+    UserID uint `gorm:"not null;index;uniqueIndex:idx_user_task_progresses_user_task,where:deleted_at IS NULL" json:"user_id"`
+    TaskID uint `gorm:"not null;index;uniqueIndex:idx_user_task_progresses_user_task,where:deleted_at IS NULL" json:"task_id"`
 
     Progress    float64    `gorm:"default:0" json:"progress"`
     Attempts    int        `gorm:"default:0" json:"attempts"`
@@ -0,0 +1,146 @@
+// Package quests defines the daily and weekly quest templates and how activity counts toward
+// them. Quests are assigned per user and period; a user's periods follow their own timezone,
+// like streaks.
+package quests
+
+import (
+	"hash/fnv"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Suplice/CodeQuest/internal/streak"
+)
+
+const (
+	PeriodDaily  = "daily"
+	PeriodWeekly = "weekly"
+)
+
+const (
+	MetricTasksCompleted = "tasks_completed"
+	MetricXPEarned       = "xp_earned"
+	MetricCorrectAnswers = "correct_answers"
+	MetricPerfectTasks   = "perfect_tasks"
+)
+
+// Template is a quest users can be given. Difficulty and Language, when set, restrict the
+// task metrics to matching tasks.
+type Template struct {
+	Slug         string
+	Period       string
+	Title        string
+	Metric       string
+	Difficulty   string
+	Language     string
+	Target       int
+	RewardXP     int
+	RewardPoints int
+}
+
+// Catalog lists the quest templates. Assigned quests keep a copy of their template, so
+// changing or removing one only affects future periods.
+var Catalog = []Template{
+	{Slug: "daily-complete-3", Period: PeriodDaily, Title: "Complete 3 tasks", Metric: MetricTasksCompleted, Target: 3, RewardXP: 30, RewardPoints: 10},
+	{Slug: "daily-earn-50-xp", Period: PeriodDaily, Title: "Earn 50 XP", Metric: MetricXPEarned, Target: 50, RewardXP: 20, RewardPoints: 5},
+	{Slug: "daily-correct-10", Period: PeriodDaily, Title: "Answer 10 questions correctly", Metric: MetricCorrectAnswers, Target: 10, RewardXP: 20, RewardPoints: 5},
+	{Slug: "daily-perfect-task", Period: PeriodDaily, Title: "Finish a task without mistakes", Metric: MetricPerfectTasks, Target: 1, RewardXP: 25, RewardPoints: 10},
+	{Slug: "daily-medium-task", Period: PeriodDaily, Title: "Finish a MEDIUM task", Metric: MetricTasksCompleted, Difficulty: "MEDIUM", Target: 1, RewardXP: 25, RewardPoints: 10},
+	{Slug: "weekly-complete-15", Period: PeriodWeekly, Title: "Complete 15 tasks", Metric: MetricTasksCompleted, Target: 15, RewardXP: 150, RewardPoints: 50},
+	{Slug: "weekly-earn-500-xp", Period: PeriodWeekly, Title: "Earn 500 XP", Metric: MetricXPEarned, Target: 500, RewardXP: 100, RewardPoints: 40},
+	{Slug: "weekly-perfect-5", Period: PeriodWeekly, Title: "Finish 5 tasks without mistakes", Metric: MetricPerfectTasks, Target: 5, RewardXP: 120, RewardPoints: 40},
+	{Slug: "weekly-hard-task", Period: PeriodWeekly, Title: "Finish a HARD task", Metric: MetricTasksCompleted, Difficulty: "HARD", Target: 1, RewardXP: 100, RewardPoints: 30},
+	{Slug: "weekly-hard-go", Period: PeriodWeekly, Title: "Finish a HARD Go task", Metric: MetricTasksCompleted, Difficulty: "HARD", Language: "Go", Target: 1, RewardXP: 150, RewardPoints: 50},
+}
+
+// PerPeriod is how many quests a user gets each period.
+var PerPeriod = map[string]int{PeriodDaily: 3, PeriodWeekly: 2}
+
+// PeriodStart returns the start of the day, or of the Monday starting the week, that contains
+// now in loc. Like streak days, a day starts at local midnight unless clocks skip it.
+func PeriodStart(period string, now time.Time, loc *time.Location) time.Time {
+	local := now.In(loc)
+	if period == PeriodWeekly {
+		back := (int(local.Weekday()) + 6) % 7
+		// Count days from noon, which every day has, then find where Monday starts.
+		return streak.Day(time.Date(local.Year(), local.Month(), local.Day()-back, 12, 0, 0, 0, loc), loc)
+	}
+	return streak.Day(local, loc)
+}
+
+// PeriodEnd returns when the period starting at start is over.
+func PeriodEnd(period string, start time.Time) time.Time {
+	days := 1
+	if period == PeriodWeekly {
+		days = 7
+	}
+	return streak.Day(time.Date(start.Year(), start.Month(), start.Day()+days, 12, 0, 0, 0, start.Location()), start.Location())
+}
+
+// Pick chooses the user's quests for a period. The choice only depends on the user and the
+// period, so assigning twice yields the same quests.
+func Pick(userID uint, period string, start time.Time) []Template {
+	var pool []Template
+	for _, t := range Catalog {
+		if t.Period == period {
+			pool = append(pool, t)
+		}
+	}
+
+	seed := strconv.FormatUint(uint64(userID), 10) + "|" + start.Format("2006-01-02") + "|"
+	score := func(t Template) uint64 {
+		h := fnv.New64a()
+		h.Write([]byte(seed + t.Slug))
+		return h.Sum64()
+	}
+	slices.SortFunc(pool, func(a, b Template) int {
+		sa, sb := score(a), score(b)
+		switch {
+		case sa < sb:
+			return -1
+		case sa > sb:
+			return 1
+		}
+		return strings.Compare(a.Slug, b.Slug)
+	})
+	return pool[:min(PerPeriod[period], len(pool))]
+}
+
+// Activity is what one answer or task completion contributed.
+type Activity struct {
+	CorrectAnswers int
+	TaskCompleted  bool
+	Perfect        bool
+	XP             int
+	Difficulty     string
+	Language       string
+}
+
+// Increment returns how much the activity advances a quest with the metric and filters.
+func Increment(metric, difficulty, language string, a Activity) int {
+	if metric == MetricCorrectAnswers {
+		return a.CorrectAnswers
+	}
+	if !a.TaskCompleted {
+		return 0
+	}
+	if difficulty != "" && !strings.EqualFold(difficulty, a.Difficulty) {
+		return 0
+	}
+	if language != "" && !strings.EqualFold(language, a.Language) {
+		return 0
+	}
+
+	switch metric {
+	case MetricTasksCompleted:
+		return 1
+	case MetricXPEarned:
+		return a.XP
+	case MetricPerfectTasks:
+		if a.Perfect {
+			return 1
+		}
+	}
+	return 0
+}
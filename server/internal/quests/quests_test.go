@@ -0,0 +1,43 @@
+package quests
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+func TestPeriods(t *testing.T) {
+	warsaw, err := time.LoadLocation("Europe/Warsaw")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Chile moves its clocks forward at midnight, so 2026-09-06 starts at 01:00.
+	santiago, err := time.LoadLocation("America/Santiago")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		period     string
+		now        time.Time
+		loc        *time.Location
+		start, end time.Time
+	}{
+		{"day", PeriodDaily, time.Date(2026, 3, 29, 23, 30, 0, 0, time.UTC), warsaw, time.Date(2026, 3, 30, 0, 0, 0, 0, warsaw), time.Date(2026, 3, 31, 0, 0, 0, 0, warsaw)},
+		{"day clocks spring forward", PeriodDaily, time.Date(2026, 3, 29, 12, 0, 0, 0, warsaw), warsaw, time.Date(2026, 3, 29, 0, 0, 0, 0, warsaw), time.Date(2026, 3, 30, 0, 0, 0, 0, warsaw)},
+		{"week", PeriodWeekly, time.Date(2026, 3, 29, 12, 0, 0, 0, warsaw), warsaw, time.Date(2026, 3, 23, 0, 0, 0, 0, warsaw), time.Date(2026, 3, 30, 0, 0, 0, 0, warsaw)},
+		{"day without midnight", PeriodDaily, time.Date(2026, 9, 6, 12, 0, 0, 0, santiago), santiago, time.Date(2026, 9, 6, 1, 0, 0, 0, santiago), time.Date(2026, 9, 7, 0, 0, 0, 0, santiago)},
+		{"day before a day without midnight", PeriodDaily, time.Date(2026, 9, 5, 12, 0, 0, 0, santiago), santiago, time.Date(2026, 9, 5, 0, 0, 0, 0, santiago), time.Date(2026, 9, 6, 1, 0, 0, 0, santiago)},
+		{"week over a day without midnight", PeriodWeekly, time.Date(2026, 9, 6, 12, 0, 0, 0, santiago), santiago, time.Date(2026, 8, 31, 0, 0, 0, 0, santiago), time.Date(2026, 9, 7, 0, 0, 0, 0, santiago)},
+	}
+	for _, tt := range tests {
+		start := PeriodStart(tt.period, tt.now, tt.loc)
+		if !start.Equal(tt.start) {
+			t.Errorf("%s: start %v, want %v", tt.name, start, tt.start)
+		}
+		if end := PeriodEnd(tt.period, start); !end.Equal(tt.end) {
+			t.Errorf("%s: end %v, want %v", tt.name, end, tt.end)
+		}
+	}
+}
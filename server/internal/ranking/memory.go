@@ -0,0 +1,159 @@
+package ranking
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// MemoryStore keeps boards in process. Each board is a slice sorted best first next to a
+// score index, so reads are binary searches; writes shift the slice, which is cheap for
+// the board sizes a single instance serves.
+type MemoryStore struct {
+	mu     sync.RWMutex
+	boards map[string]*memoryBoard
+}
+
+type memoryBoard struct {
+	scores  map[uint]float64
+	order   []Entry
+	expires time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{boards: make(map[string]*memoryBoard)}
+}
+
+// before reports whether a ranks ahead of b.
+func before(a, b Entry) bool {
+	if a.Score != b.Score {
+		return a.Score > b.Score
+	}
+	return member(a.UserID) > member(b.UserID)
+}
+
+func (b *memoryBoard) expired(now time.Time) bool {
+	return !b.expires.IsZero() && !now.Before(b.expires)
+}
+
+// search returns the position e has, or would have, on the board.
+func (b *memoryBoard) search(e Entry) int {
+	return sort.Search(len(b.order), func(i int) bool { return !before(b.order[i], e) })
+}
+
+func (b *memoryBoard) set(userID uint, score float64) {
+	if old, ok := b.scores[userID]; ok {
+		i := b.search(Entry{UserID: userID, Score: old})
+		b.order = append(b.order[:i], b.order[i+1:]...)
+	}
+	e := Entry{UserID: userID, Score: score}
+	i := b.search(e)
+	b.order = append(b.order, Entry{})
+	copy(b.order[i+1:], b.order[i:])
+	b.order[i] = e
+	b.scores[userID] = score
+}
+
+// board returns a live board or nil. Callers hold at least the read lock.
+func (s *MemoryStore) board(name string) *memoryBoard {
+	b, ok := s.boards[name]
+	if !ok || b.expired(time.Now()) {
+		return nil
+	}
+	return b
+}
+
+func (s *MemoryStore) Replace(_ context.Context, board string, scores map[uint]float64, ttl time.Duration) error {
+	b := &memoryBoard{scores: make(map[uint]float64, len(scores)), order: make([]Entry, 0, len(scores))}
+	for userID, score := range scores {
+		b.scores[userID] = score
+		b.order = append(b.order, Entry{UserID: userID, Score: score})
+	}
+	sort.Slice(b.order, func(i, j int) bool { return before(b.order[i], b.order[j]) })
+	if ttl > 0 {
+		b.expires = time.Now().Add(ttl)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for name, old := range s.boards {
+		if old.expired(time.Now()) {
+			delete(s.boards, name)
+		}
+	}
+	if len(scores) == 0 {
+		delete(s.boards, board)
+		return nil
+	}
+	s.boards[board] = b
+	return nil
+}
+
+func (s *MemoryStore) Incr(_ context.Context, board string, userID uint, delta float64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if b := s.board(board); b != nil {
+		b.set(userID, b.scores[userID]+delta)
+	}
+	return nil
+}
+
+func (s *MemoryStore) Set(_ context.Context, board string, userID uint, score float64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if b := s.board(board); b != nil {
+		b.set(userID, score)
+	}
+	return nil
+}
+
+func (s *MemoryStore) Exists(_ context.Context, board string) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.board(board) != nil, nil
+}
+
+func (s *MemoryStore) Count(_ context.Context, board string) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if b := s.board(board); b != nil {
+		return len(b.order), nil
+	}
+	return 0, nil
+}
+
+func (s *MemoryStore) Range(_ context.Context, board string, offset, limit int) ([]Entry, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	b := s.board(board)
+	if b == nil || offset >= len(b.order) {
+		return nil, nil
+	}
+	end := min(offset+limit, len(b.order))
+	return append([]Entry(nil), b.order[offset:end]...), nil
+}
+
+func (s *MemoryStore) Position(_ context.Context, board string, userID uint) (int, float64, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	b := s.board(board)
+	if b == nil {
+		return 0, 0, false, nil
+	}
+	score, ok := b.scores[userID]
+	if !ok {
+		return 0, 0, false, nil
+	}
+	return b.search(Entry{UserID: userID, Score: score}), score, true, nil
+}
+
+func (s *MemoryStore) CountAbove(_ context.Context, board string, score float64) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	b := s.board(board)
+	if b == nil {
+		return 0, nil
+	}
+	return sort.Search(len(b.order), func(i int) bool { return b.order[i].Score <= score }), nil
+}
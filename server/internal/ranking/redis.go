@@ -0,0 +1,331 @@
+package ranking
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"time"
+)
+
+const (
+	redisKeyPrefix    = "codequest:ranking:"
+	redisBatchSize    = 500
+	redisIdleConns    = 8
+	redisDialTimeout  = 3 * time.Second
+	redisQueryTimeout = 5 * time.Second
+)
+
+// Boards are only written while they exist, so increments never create partial boards.
+const (
+	incrScript = `if redis.call('EXISTS', KEYS[1]) == 1 then return redis.call('ZINCRBY', KEYS[1], ARGV[1], ARGV[2]) end return false`
+	setScript  = `if redis.call('EXISTS', KEYS[1]) == 1 then return redis.call('ZADD', KEYS[1], ARGV[1], ARGV[2]) end return false`
+)
+
+// RedisStore keeps boards as Redis sorted sets. It speaks RESP directly and uses only
+// commands and Lua scripting available on Redis-compatible servers, so it can be pointed at
+// a local stand-in.
+type RedisStore struct {
+	addr     string
+	password string
+	idle     chan *redisConn
+}
+
+func NewRedisStore(addr, password string) *RedisStore {
+	return &RedisStore{addr: addr, password: password, idle: make(chan *redisConn, redisIdleConns)}
+}
+
+func (s *RedisStore) key(board string) string {
+	return redisKeyPrefix + board
+}
+
+func (s *RedisStore) Replace(ctx context.Context, board string, scores map[uint]float64, ttl time.Duration) error {
+	key := s.key(board)
+	if len(scores) == 0 {
+		_, err := s.do(ctx, []string{"DEL", key})
+		return err
+	}
+
+	tmp := key + ":rebuild"
+	cmds := [][]string{{"MULTI"}, {"DEL", tmp}}
+	zadd := []string{"ZADD", tmp}
+	for userID, score := range scores {
+		zadd = append(zadd, formatScore(score), member(userID))
+		if len(zadd) == 2+2*redisBatchSize {
+			cmds = append(cmds, zadd)
+			zadd = []string{"ZADD", tmp}
+		}
+	}
+	if len(zadd) > 2 {
+		cmds = append(cmds, zadd)
+	}
+	cmds = append(cmds, []string{"RENAME", tmp, key})
+	if ttl > 0 {
+		cmds = append(cmds, []string{"PEXPIRE", key, strconv.FormatInt(ttl.Milliseconds(), 10)})
+	}
+	cmds = append(cmds, []string{"EXEC"})
+
+	replies, err := s.pipeline(ctx, cmds)
+	if err != nil {
+		return err
+	}
+	if exec := replies[len(replies)-1]; exec == nil {
+		return errors.New("redis transaction aborted")
+	}
+	return nil
+}
+
+func (s *RedisStore) Incr(ctx context.Context, board string, userID uint, delta float64) error {
+	_, err := s.do(ctx, []string{"EVAL", incrScript, "1", s.key(board), formatScore(delta), member(userID)})
+	return err
+}
+
+func (s *RedisStore) Set(ctx context.Context, board string, userID uint, score float64) error {
+	_, err := s.do(ctx, []string{"EVAL", setScript, "1", s.key(board), formatScore(score), member(userID)})
+	return err
+}
+
+func (s *RedisStore) Exists(ctx context.Context, board string) (bool, error) {
+	reply, err := s.do(ctx, []string{"EXISTS", s.key(board)})
+	if err != nil {
+		return false, err
+	}
+	n, err := replyInt(reply)
+	return n == 1, err
+}
+
+func (s *RedisStore) Count(ctx context.Context, board string) (int, error) {
+	reply, err := s.do(ctx, []string{"ZCARD", s.key(board)})
+	if err != nil {
+		return 0, err
+	}
+	return replyInt(reply)
+}
+
+func (s *RedisStore) Range(ctx context.Context, board string, offset, limit int) ([]Entry, error) {
+	if limit <= 0 {
+		return nil, nil
+	}
+	reply, err := s.do(ctx, []string{"ZREVRANGE", s.key(board), strconv.Itoa(offset), strconv.Itoa(offset + limit - 1), "WITHSCORES"})
+	if err != nil {
+		return nil, err
+	}
+	items, ok := reply.([]any)
+	if !ok || len(items)%2 != 0 {
+		return nil, fmt.Errorf("unexpected ZREVRANGE reply %T", reply)
+	}
+
+	entries := make([]Entry, 0, len(items)/2)
+	for i := 0; i < len(items); i += 2 {
+		userID, err := strconv.ParseUint(replyString(items[i]), 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		score, err := strconv.ParseFloat(replyString(items[i+1]), 64)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, Entry{UserID: uint(userID), Score: score})
+	}
+	return entries, nil
+}
+
+func (s *RedisStore) Position(ctx context.Context, board string, userID uint) (int, float64, bool, error) {
+	key := s.key(board)
+	replies, err := s.pipeline(ctx, [][]string{{"ZREVRANK", key, member(userID)}, {"ZSCORE", key, member(userID)}})
+	if err != nil {
+		return 0, 0, false, err
+	}
+	if replies[0] == nil || replies[1] == nil {
+		return 0, 0, false, nil
+	}
+	position, err := replyInt(replies[0])
+	if err != nil {
+		return 0, 0, false, err
+	}
+	score, err := strconv.ParseFloat(replyString(replies[1]), 64)
+	if err != nil {
+		return 0, 0, false, err
+	}
+	return position, score, true, nil
+}
+
+func (s *RedisStore) CountAbove(ctx context.Context, board string, score float64) (int, error) {
+	reply, err := s.do(ctx, []string{"ZCOUNT", s.key(board), "(" + formatScore(score), "+inf"})
+	if err != nil {
+		return 0, err
+	}
+	return replyInt(reply)
+}
+
+func formatScore(score float64) string {
+	return strconv.FormatFloat(score, 'f', -1, 64)
+}
+
+func (s *RedisStore) do(ctx context.Context, cmd []string) (any, error) {
+	replies, err := s.pipeline(ctx, [][]string{cmd})
+	if err != nil {
+		return nil, err
+	}
+	return replies[0], nil
+}
+
+// pipeline sends the commands in one write and reads one reply per command. An error reply
+// to any command is returned as the error.
+func (s *RedisStore) pipeline(ctx context.Context, cmds [][]string) ([]any, error) {
+	conn, err := s.conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	replies, err := conn.roundTrip(ctx, cmds)
+	var replyErr redisError
+	if err != nil && !errors.As(err, &replyErr) {
+		conn.Close()
+		return nil, err
+	}
+	s.release(conn)
+	return replies, err
+}
+
+func (s *RedisStore) conn(ctx context.Context) (*redisConn, error) {
+	select {
+	case c := <-s.idle:
+		return c, nil
+	default:
+	}
+
+	dialer := net.Dialer{Timeout: redisDialTimeout}
+	nc, err := dialer.DialContext(ctx, "tcp", s.addr)
+	if err != nil {
+		return nil, err
+	}
+	c := &redisConn{Conn: nc, r: bufio.NewReader(nc), w: bufio.NewWriter(nc)}
+	if s.password != "" {
+		if _, err := c.roundTrip(ctx, [][]string{{"AUTH", s.password}}); err != nil {
+			c.Close()
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+func (s *RedisStore) release(c *redisConn) {
+	select {
+	case s.idle <- c:
+	default:
+		c.Close()
+	}
+}
+
+type redisError string
+
+func (e redisError) Error() string { return "redis: " + string(e) }
+
+type redisConn struct {
+	net.Conn
+	r *bufio.Reader
+	w *bufio.Writer
+}
+
+func (c *redisConn) roundTrip(ctx context.Context, cmds [][]string) ([]any, error) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(redisQueryTimeout)
+	}
+	if err := c.SetDeadline(deadline); err != nil {
+		return nil, err
+	}
+
+	for _, cmd := range cmds {
+		fmt.Fprintf(c.w, "*%d\r\n", len(cmd))
+		for _, arg := range cmd {
+			fmt.Fprintf(c.w, "$%d\r\n%s\r\n", len(arg), arg)
+		}
+	}
+	if err := c.w.Flush(); err != nil {
+		return nil, err
+	}
+
+	replies := make([]any, len(cmds))
+	var firstErr error
+	for i := range cmds {
+		reply, err := c.readReply()
+		var replyErr redisError
+		if err != nil && !errors.As(err, &replyErr) {
+			return nil, err
+		}
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+		replies[i] = reply
+	}
+	return replies, firstErr
+}
+
+// readReply parses one RESP2 reply. Nil bulk strings and arrays are returned as nil.
+func (c *redisConn) readReply() (any, error) {
+	line, err := c.r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if len(line) < 3 || line[len(line)-2] != '\r' {
+		return nil, fmt.Errorf("malformed redis reply %q", line)
+	}
+	kind, body := line[0], line[1:len(line)-2]
+
+	switch kind {
+	case '+':
+		return body, nil
+	case '-':
+		return nil, redisError(body)
+	case ':':
+		return strconv.ParseInt(body, 10, 64)
+	case '$':
+		n, err := strconv.Atoi(body)
+		if err != nil || n < 0 {
+			return nil, err
+		}
+		buf := make([]byte, n+2)
+		if _, err := io.ReadFull(c.r, buf); err != nil {
+			return nil, err
+		}
+		return string(buf[:n]), nil
+	case '*':
+		n, err := strconv.Atoi(body)
+		if err != nil || n < 0 {
+			return nil, err
+		}
+		items := make([]any, n)
+		var firstErr error
+		for i := range items {
+			item, err := c.readReply()
+			var replyErr redisError
+			if err != nil && !errors.As(err, &replyErr) {
+				return nil, err
+			}
+			if err != nil && firstErr == nil {
+				firstErr = err
+			}
+			items[i] = item
+		}
+		return items, firstErr
+	}
+	return nil, fmt.Errorf("unknown redis reply type %q", kind)
+}
+
+func replyInt(reply any) (int, error) {
+	n, ok := reply.(int64)
+	if !ok {
+		return 0, fmt.Errorf("unexpected redis reply %T, want integer", reply)
+	}
+	return int(n), nil
+}
+
+func replyString(reply any) string {
+	s, _ := reply.(string)
+	return s
+}
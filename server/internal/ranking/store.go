@@ -0,0 +1,43 @@
+// Package ranking keeps leaderboards as sorted sets, so that a page or a user's rank is read
+// in O(log n) instead of sorting every user per request.
+//
+// A board maps user IDs to scores. Higher scores come first; users sharing a score are ordered
+// by their decimal ID compared as strings, highest first, which is what Redis does for
+// ZREVRANGE. Ranks are competition ranks: one more than the number of strictly higher scores.
+package ranking
+
+import (
+	"context"
+	"strconv"
+	"time"
+)
+
+type Entry struct {
+	UserID uint
+	Score  float64
+}
+
+// Store is a set of named boards. Boards are created only by Replace, so a board that was
+// never built (or expired) reads as missing and callers can fall back to the database.
+type Store interface {
+	// Replace swaps the board for scores. It expires after ttl, or never when ttl is 0. With
+	// no scores the board is removed, as Redis cannot hold an empty sorted set.
+	Replace(ctx context.Context, board string, scores map[uint]float64, ttl time.Duration) error
+	// Incr adds delta to the user's score if the board exists.
+	Incr(ctx context.Context, board string, userID uint, delta float64) error
+	// Set sets the user's score if the board exists.
+	Set(ctx context.Context, board string, userID uint, score float64) error
+	Exists(ctx context.Context, board string) (bool, error)
+	Count(ctx context.Context, board string) (int, error)
+	// Range returns up to limit entries starting at the 0-based position offset, best first.
+	Range(ctx context.Context, board string, offset, limit int) ([]Entry, error)
+	// Position returns the user's 0-based position and score; ok is false when the user is
+	// not on the board.
+	Position(ctx context.Context, board string, userID uint) (position int, score float64, ok bool, err error)
+	// CountAbove returns how many users score strictly higher than score.
+	CountAbove(ctx context.Context, board string, score float64) (int, error)
+}
+
+func member(userID uint) string {
+	return strconv.FormatUint(uint64(userID), 10)
+}
@@ -0,0 +1,227 @@
+package ranking
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+)
+
+// storeHarness is a Store under test with a way to let time pass for its TTLs.
+type storeHarness struct {
+	store Store
+	wait  func(time.Duration)
+}
+
+// TestStores runs every store against the same expectations, so the in-memory store stays a
+// faithful stand-in for Redis.
+func TestStores(t *testing.T) {
+	stores := map[string]func(t *testing.T) storeHarness{
+		"memory": func(t *testing.T) storeHarness {
+			return storeHarness{store: NewMemoryStore(), wait: time.Sleep}
+		},
+		"redis": func(t *testing.T) storeHarness {
+			server := miniredis.RunT(t)
+			server.RequireAuth("secret")
+			return storeHarness{store: NewRedisStore(server.Addr(), "secret"), wait: server.FastForward}
+		},
+	}
+
+	tests := []struct {
+		name string
+		run  func(t *testing.T, h storeHarness)
+	}{
+		{"missing board", testMissingBoard},
+		{"order and ties", testOrderAndTies},
+		{"positions", testPositions},
+		{"updates", testUpdates},
+		{"replace", testReplace},
+		{"large board", testLargeBoard},
+		{"expiry", testExpiry},
+	}
+
+	for name, newStore := range stores {
+		t.Run(name, func(t *testing.T) {
+			for _, tt := range tests {
+				t.Run(tt.name, func(t *testing.T) {
+					tt.run(t, newStore(t))
+				})
+			}
+		})
+	}
+}
+
+func mustReplace(t *testing.T, s Store, board string, scores map[uint]float64, ttl time.Duration) {
+	t.Helper()
+	if err := s.Replace(context.Background(), board, scores, ttl); err != nil {
+		t.Fatalf("Replace(%s): %v", board, err)
+	}
+}
+
+func assertRange(t *testing.T, s Store, board string, offset, limit int, want []Entry) {
+	t.Helper()
+	got, err := s.Range(context.Background(), board, offset, limit)
+	if err != nil {
+		t.Fatalf("Range(%s, %d, %d): %v", board, offset, limit, err)
+	}
+	if len(got) == 0 && len(want) == 0 {
+		return
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Range(%s, %d, %d) = %v, want %v", board, offset, limit, got, want)
+	}
+}
+
+func assertPosition(t *testing.T, s Store, board string, userID uint, wantPosition int, wantScore float64, wantOK bool) {
+	t.Helper()
+	position, score, ok, err := s.Position(context.Background(), board, userID)
+	if err != nil {
+		t.Fatalf("Position(%s, %d): %v", board, userID, err)
+	}
+	if ok != wantOK || position != wantPosition || score != wantScore {
+		t.Errorf("Position(%s, %d) = %d, %v, %v, want %d, %v, %v", board, userID, position, score, ok, wantPosition, wantScore, wantOK)
+	}
+}
+
+func assertCount(t *testing.T, s Store, board string, want int) {
+	t.Helper()
+	got, err := s.Count(context.Background(), board)
+	if err != nil {
+		t.Fatalf("Count(%s): %v", board, err)
+	}
+	if got != want {
+		t.Errorf("Count(%s) = %d, want %d", board, got, want)
+	}
+}
+
+func assertExists(t *testing.T, s Store, board string, want bool) {
+	t.Helper()
+	got, err := s.Exists(context.Background(), board)
+	if err != nil {
+		t.Fatalf("Exists(%s): %v", board, err)
+	}
+	if got != want {
+		t.Errorf("Exists(%s) = %v, want %v", board, got, want)
+	}
+}
+
+func testMissingBoard(t *testing.T, h storeHarness) {
+	ctx := context.Background()
+	if err := h.store.Incr(ctx, "xp:all", 1, 10); err != nil {
+		t.Fatalf("Incr: %v", err)
+	}
+	if err := h.store.Set(ctx, "xp:all", 2, 10); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+
+	// Writes to a board that was never built must not create a partial one.
+	assertExists(t, h.store, "xp:all", false)
+	assertCount(t, h.store, "xp:all", 0)
+	assertRange(t, h.store, "xp:all", 0, 10, nil)
+	assertPosition(t, h.store, "xp:all", 1, 0, 0, false)
+	above, err := h.store.CountAbove(ctx, "xp:all", 0)
+	if err != nil || above != 0 {
+		t.Errorf("CountAbove = %d, %v, want 0, nil", above, err)
+	}
+}
+
+func testOrderAndTies(t *testing.T, h storeHarness) {
+	// Tied users are ordered by their IDs compared as strings, highest first: "9" > "2" > "10".
+	mustReplace(t, h.store, "xp:all", map[uint]float64{2: 5, 9: 5, 10: 5, 3: 7, 4: 1}, 0)
+
+	assertExists(t, h.store, "xp:all", true)
+	assertCount(t, h.store, "xp:all", 5)
+	assertRange(t, h.store, "xp:all", 0, 10, []Entry{{3, 7}, {9, 5}, {2, 5}, {10, 5}, {4, 1}})
+	assertRange(t, h.store, "xp:all", 1, 2, []Entry{{9, 5}, {2, 5}})
+	assertRange(t, h.store, "xp:all", 4, 10, []Entry{{4, 1}})
+	assertRange(t, h.store, "xp:all", 5, 10, nil)
+	assertRange(t, h.store, "xp:all", 0, 0, nil)
+}
+
+func testPositions(t *testing.T, h storeHarness) {
+	ctx := context.Background()
+	mustReplace(t, h.store, "xp:all", map[uint]float64{1: 30, 2: 20, 3: 20, 4: 10}, 0)
+
+	assertPosition(t, h.store, "xp:all", 1, 0, 30, true)
+	assertPosition(t, h.store, "xp:all", 3, 1, 20, true)
+	assertPosition(t, h.store, "xp:all", 2, 2, 20, true)
+	assertPosition(t, h.store, "xp:all", 4, 3, 10, true)
+	assertPosition(t, h.store, "xp:all", 5, 0, 0, false)
+
+	for score, want := range map[float64]int{40: 0, 30: 0, 25: 1, 20: 1, 10: 3, 0: 4} {
+		got, err := h.store.CountAbove(ctx, "xp:all", score)
+		if err != nil {
+			t.Fatalf("CountAbove(%v): %v", score, err)
+		}
+		if got != want {
+			t.Errorf("CountAbove(%v) = %d, want %d", score, got, want)
+		}
+	}
+}
+
+func testUpdates(t *testing.T, h storeHarness) {
+	ctx := context.Background()
+	mustReplace(t, h.store, "xp:all", map[uint]float64{1: 30, 2: 20, 3: 10}, 0)
+
+	if err := h.store.Incr(ctx, "xp:all", 3, 25); err != nil {
+		t.Fatalf("Incr: %v", err)
+	}
+	if err := h.store.Incr(ctx, "xp:all", 4, 15); err != nil {
+		t.Fatalf("Incr new user: %v", err)
+	}
+	if err := h.store.Set(ctx, "xp:all", 1, 5); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if err := h.store.Set(ctx, "xp:all", 5, 0.5); err != nil {
+		t.Fatalf("Set new user: %v", err)
+	}
+
+	assertCount(t, h.store, "xp:all", 5)
+	assertRange(t, h.store, "xp:all", 0, 10, []Entry{{3, 35}, {2, 20}, {4, 15}, {1, 5}, {5, 0.5}})
+	assertPosition(t, h.store, "xp:all", 1, 3, 5, true)
+}
+
+func testReplace(t *testing.T, h storeHarness) {
+	mustReplace(t, h.store, "xp:all", map[uint]float64{1: 30, 2: 20}, 0)
+	mustReplace(t, h.store, "points:all", map[uint]float64{1: 1}, 0)
+
+	mustReplace(t, h.store, "xp:all", map[uint]float64{3: 10}, 0)
+	assertRange(t, h.store, "xp:all", 0, 10, []Entry{{3, 10}})
+	assertPosition(t, h.store, "xp:all", 1, 0, 0, false)
+
+	// Boards are independent, and replacing with no scores leaves the board missing.
+	mustReplace(t, h.store, "xp:all", nil, 0)
+	assertExists(t, h.store, "xp:all", false)
+	assertRange(t, h.store, "points:all", 0, 10, []Entry{{1, 1}})
+}
+
+func testLargeBoard(t *testing.T, h storeHarness) {
+	// More users than one ZADD batch of the Redis store.
+	scores := make(map[uint]float64, 1234)
+	for id := uint(1); id <= 1234; id++ {
+		scores[id] = float64(id)
+	}
+	mustReplace(t, h.store, "xp:all", scores, 0)
+
+	assertCount(t, h.store, "xp:all", 1234)
+	assertRange(t, h.store, "xp:all", 0, 2, []Entry{{1234, 1234}, {1233, 1233}})
+	assertRange(t, h.store, "xp:all", 1232, 5, []Entry{{2, 2}, {1, 1}})
+	assertPosition(t, h.store, "xp:all", 600, 634, 600, true)
+}
+
+func testExpiry(t *testing.T, h storeHarness) {
+	mustReplace(t, h.store, "xp:week", map[uint]float64{1: 10}, 50*time.Millisecond)
+	mustReplace(t, h.store, "xp:all", map[uint]float64{1: 10}, 0)
+	assertExists(t, h.store, "xp:week", true)
+
+	h.wait(60 * time.Millisecond)
+
+	assertExists(t, h.store, "xp:week", false)
+	assertExists(t, h.store, "xp:all", true)
+	if err := h.store.Incr(context.Background(), "xp:week", 1, 5); err != nil {
+		t.Fatalf("Incr: %v", err)
+	}
+	assertExists(t, h.store, "xp:week", false)
+}
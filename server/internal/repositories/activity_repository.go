@@ -0,0 +1,91 @@
+package repositories
+
+import (
+	"log/slog"
+
+	"github.com/Suplice/CodeQuest/internal/dto"
+	"github.com/Suplice/CodeQuest/internal/models"
+	"github.com/Suplice/CodeQuest/internal/utils/constants"
+	"gorm.io/gorm"
+)
+
+type ActivityRepository struct {
+	db     *gorm.DB
+	logger *slog.Logger
+}
+
+func NewActivityRepository(_db *gorm.DB, _logger *slog.Logger) *ActivityRepository {
+	return &ActivityRepository{db: _db, logger: _logger}
+}
+
+func (ar *ActivityRepository) Create(entry *models.ActivityLog) error {
+	if err := ar.db.Create(entry).Error; err != nil {
+		ar.logger.Error("Failed to write activity log", "err", err, "userID", entry.UserID, "type", entry.ActionType)
+		return err
+	}
+	return nil
+}
+
+// GetTimeline returns up to limit entries of the user older than the cursor ID (0 for the
+// newest), optionally restricted to the given action types.
+func (ar *ActivityRepository) GetTimeline(userID, cursor uint, limit int, types []string) ([]models.ActivityLog, error) {
+	query := ar.db.Where("user_id = ?", userID)
+	if cursor > 0 {
+		query = query.Where("id < ?", cursor)
+	}
+	if len(types) > 0 {
+		query = query.Where("action_type IN ?", types)
+	}
+
+	var entries []models.ActivityLog
+	if err := query.Order("id DESC").Limit(limit).Find(&entries).Error; err != nil {
+		ar.logger.Error("Failed to get activity timeline", "err", err, "userID", userID)
+		return nil, err
+	}
+	return entries, nil
+}
+
+// GetFriendFeed returns up to limit entries of the given types written by the user's accepted
+// friends, newest first and older than the cursor ID. Friends who made their activity private,
+// and users the user blocked or muted, are left out.
+func (ar *ActivityRepository) GetFriendFeed(userID, cursor uint, limit int, types []string) ([]models.ActivityLog, error) {
+	friendIDs := ar.db.Model(&models.Friendship{}).
+		Select("CASE WHEN user_id = ? THEN friend_id ELSE user_id END", userID).
+		Where("(user_id = ? OR friend_id = ?) AND status = ?", userID, userID, "accepted")
+	privateIDs := ar.db.Model(&models.Settings{}).
+		Select("user_id").
+		Where("setting_key = ? AND setting_value = ?", constants.SettingActivityVisibility, constants.ActivityVisibilityPrivate)
+
+	restrictedIDs := ar.db.Model(&models.UserBlock{}).
+		Select("target_id").
+		Where("user_id = ?", userID)
+
+	query := ar.db.Where("user_id IN (?) AND user_id NOT IN (?) AND user_id NOT IN (?) AND user_id NOT IN (?) AND action_type IN ?",
+		friendIDs, privateIDs, restrictedIDs, blockedUserIDs(ar.db, userID), types)
+	if cursor > 0 {
+		query = query.Where("id < ?", cursor)
+	}
+
+	var entries []models.ActivityLog
+	if err := query.Order("id DESC").Limit(limit).Find(&entries).Error; err != nil {
+		ar.logger.Error("Failed to get friend feed", "err", err, "userID", userID)
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (ar *ActivityRepository) GetUsersShortInfo(userIDs []uint) ([]dto.UserShortInfo, error) {
+	var result []dto.UserShortInfo
+	if len(userIDs) == 0 {
+		return result, nil
+	}
+	err := ar.db.Model(&models.User{}).
+		Select("id", "username", "avatar_url", "level", "points").
+		Where("id IN ?", userIDs).
+		Scan(&result).Error
+	if err != nil {
+		ar.logger.Error("Failed to get users for feed", "err", err)
+		return nil, err
+	}
+	return result, nil
+}
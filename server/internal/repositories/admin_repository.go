@@ -1,6 +1,7 @@
 package repositories
 
 import (
+	"errors"
 	"log/slog"
 
 	"github.com/Suplice/CodeQuest/internal/models"
@@ -52,12 +53,273 @@ func (ar *AdminRepository) DeleteTask(taskID uint) error {
 	})
 }
 
+func (ar *AdminRepository) GetAllTasks() ([]models.Task, error) {
+	var tasks []models.Task
+	if err := ar.db.Preload("TaskQuestions", orderQuestions).Order("id ASC").Find(&tasks).Error; err != nil {
+		ar.logger.Error("Failed to get tasks for admin", "err", err)
+		return nil, err
+	}
+	return tasks, nil
+}
+
+func (ar *AdminRepository) GetTaskByID(taskID uint) (*models.Task, error) {
+	var task models.Task
+	if err := ar.db.Preload("TaskQuestions", orderQuestions).First(&task, taskID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("task not found")
+		}
+		return nil, err
+	}
+	return &task, nil
+}
+
+func (ar *AdminRepository) CreateTask(task *models.Task) error {
+	return ar.db.Transaction(func(tx *gorm.DB) error {
+		questions := task.TaskQuestions
+		task.TaskQuestions = nil
+
+		if err := tx.Create(task).Error; err != nil {
+			ar.logger.Error("Failed to create task", "err", err)
+			return err
+		}
+
+		for i := range questions {
+			questions[i].TaskID = task.ID
+			questions[i].Position = i
+		}
+		if len(questions) > 0 {
+			if err := tx.Create(&questions).Error; err != nil {
+				ar.logger.Error("Failed to create task questions", "err", err, "taskID", task.ID)
+				return err
+			}
+		}
+
+		task.TaskQuestions = questions
+		return nil
+	})
+}
+
+// TaskSlugTaken reports whether a task other than excludeID already uses the slug.
+func (ar *AdminRepository) TaskSlugTaken(slug string, excludeID uint) (bool, error) {
+	var count int64
+	if err := ar.db.Model(&models.Task{}).Where("slug = ? AND id <> ?", slug, excludeID).Count(&count).Error; err != nil {
+		ar.logger.Error("Failed to check task slug", "err", err, "slug", slug)
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (ar *AdminRepository) UpdateTask(task *models.Task) error {
+	result := ar.db.Model(&models.Task{}).Where("id = ?", task.ID).Updates(map[string]interface{}{
+		"slug":        task.Slug,
+		"title":       task.Title,
+		"description": task.Description,
+		"type":        task.Type,
+		"language":    task.Language,
+		"difficulty":  task.Difficulty,
+		"points":      task.Points,
+		"xp":          task.XP,
+		"is_active":   task.IsActive,
+	})
+	if result.Error != nil {
+		ar.logger.Error("Failed to update task", "err", result.Error, "taskID", task.ID)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("task not found")
+	}
+	return nil
+}
+
+func (ar *AdminRepository) SetTaskActive(taskID uint, isActive bool) error {
+	result := ar.db.Model(&models.Task{}).Where("id = ?", taskID).Update("is_active", isActive)
+	if result.Error != nil {
+		ar.logger.Error("Failed to toggle task", "err", result.Error, "taskID", taskID)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("task not found")
+	}
+	return nil
+}
+
+func (ar *AdminRepository) GetQuestion(taskID, questionID uint) (*models.TaskQuestion, error) {
+	var question models.TaskQuestion
+	if err := ar.db.Where("id = ? AND task_id = ?", questionID, taskID).First(&question).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("question not found")
+		}
+		return nil, err
+	}
+	return &question, nil
+}
+
+func (ar *AdminRepository) CreateQuestion(question *models.TaskQuestion) error {
+	return ar.db.Transaction(func(tx *gorm.DB) error {
+		var nextPosition int
+		if err := tx.Model(&models.TaskQuestion{}).
+			Where("task_id = ?", question.TaskID).
+			Select("COALESCE(MAX(position) + 1, 0)").
+			Scan(&nextPosition).Error; err != nil {
+			return err
+		}
+
+		question.Position = nextPosition
+		if err := tx.Create(question).Error; err != nil {
+			ar.logger.Error("Failed to create question", "err", err, "taskID", question.TaskID)
+			return err
+		}
+		return nil
+	})
+}
+
+func (ar *AdminRepository) UpdateQuestion(question *models.TaskQuestion) error {
+	result := ar.db.Model(&models.TaskQuestion{}).
+		Where("id = ? AND task_id = ?", question.ID, question.TaskID).
+		Updates(map[string]interface{}{
+			"slug":            question.Slug,
+			"question_text":   question.QuestionText,
+			"type":            question.Type,
+			"options":         question.Options,
+			"grading_config":  question.GradingConfig,
+			"correct_answer":  question.CorrectAnswer,
+			"test_input":      question.TestInput,
+			"expected_output": question.ExpectedOutput,
+			"explanation":     question.Explanation,
+		})
+	if result.Error != nil {
+		ar.logger.Error("Failed to update question", "err", result.Error, "questionID", question.ID)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("question not found")
+	}
+	return nil
+}
+
+func (ar *AdminRepository) DeleteQuestion(taskID, questionID uint) error {
+	result := ar.db.Where("id = ? AND task_id = ?", questionID, taskID).Delete(&models.TaskQuestion{})
+	if result.Error != nil {
+		ar.logger.Error("Failed to delete question", "err", result.Error, "questionID", questionID)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("question not found")
+	}
+	return nil
+}
+
+// ReorderQuestions assigns positions following questionIDs, which must list every question of the task exactly once.
+func (ar *AdminRepository) ReorderQuestions(taskID uint, questionIDs []uint) error {
+	return ar.db.Transaction(func(tx *gorm.DB) error {
+		var existingIDs []uint
+		if err := tx.Model(&models.TaskQuestion{}).Where("task_id = ?", taskID).Pluck("id", &existingIDs).Error; err != nil {
+			return err
+		}
+
+		existing := make(map[uint]bool, len(existingIDs))
+		for _, id := range existingIDs {
+			existing[id] = true
+		}
+		if len(questionIDs) != len(existingIDs) {
+			return errors.New("question order must contain every question of the task")
+		}
+		for _, id := range questionIDs {
+			if !existing[id] {
+				return errors.New("question order must contain every question of the task")
+			}
+			delete(existing, id)
+		}
+
+		for position, id := range questionIDs {
+			if err := tx.Model(&models.TaskQuestion{}).Where("id = ?", id).Update("position", position).Error; err != nil {
+				ar.logger.Error("Failed to reorder question", "err", err, "questionID", id)
+				return err
+			}
+		}
+		return nil
+	})
+}
+
 type SystemStats struct {
 	TotalUsers     int64 `json:"total_users"`
 	TotalTasks     int64 `json:"total_tasks"`
 	TotalCompleted int64 `json:"total_completed_tasks"`
 }
 
+func (ar *AdminRepository) GetAllBadges() ([]models.Badge, error) {
+	var badges []models.Badge
+	if err := ar.db.Order("id ASC").Find(&badges).Error; err != nil {
+		ar.logger.Error("Failed to get badges for admin", "err", err)
+		return nil, err
+	}
+	return badges, nil
+}
+
+func (ar *AdminRepository) GetBadgeByID(badgeID uint) (*models.Badge, error) {
+	var badge models.Badge
+	if err := ar.db.First(&badge, badgeID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("badge not found")
+		}
+		return nil, err
+	}
+	return &badge, nil
+}
+
+func (ar *AdminRepository) CreateBadge(badge *models.Badge) error {
+	if err := ar.db.Create(badge).Error; err != nil {
+		ar.logger.Error("Failed to create badge", "err", err)
+		return err
+	}
+	return nil
+}
+
+// BadgeSlugTaken reports whether a badge other than excludeID already uses the slug.
+func (ar *AdminRepository) BadgeSlugTaken(slug string, excludeID uint) (bool, error) {
+	var count int64
+	if err := ar.db.Model(&models.Badge{}).Where("slug = ? AND id <> ?", slug, excludeID).Count(&count).Error; err != nil {
+		ar.logger.Error("Failed to check badge slug", "err", err, "slug", slug)
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (ar *AdminRepository) UpdateBadge(badge *models.Badge) error {
+	result := ar.db.Model(&models.Badge{}).Where("id = ?", badge.ID).Updates(map[string]interface{}{
+		"slug":        badge.Slug,
+		"name":        badge.Name,
+		"description": badge.Description,
+		"icon_url":    badge.IconURL,
+		"requirement": badge.Requirement,
+	})
+	if result.Error != nil {
+		ar.logger.Error("Failed to update badge", "err", result.Error, "badgeID", badge.ID)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("badge not found")
+	}
+	return nil
+}
+
+func (ar *AdminRepository) DeleteBadge(badgeID uint) error {
+	return ar.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("badge_id = ?", badgeID).Delete(&models.UserBadge{}).Error; err != nil {
+			return err
+		}
+
+		result := tx.Delete(&models.Badge{}, badgeID)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("badge not found")
+		}
+		return nil
+	})
+}
+
 func (ar *AdminRepository) GetSystemStats() (*SystemStats, error) {
 	var stats SystemStats
 
@@ -0,0 +1,116 @@
+package repositories
+
+import (
+	"log/slog"
+
+	"github.com/Suplice/CodeQuest/internal/badges"
+	"github.com/Suplice/CodeQuest/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type BadgeRepository struct {
+	db     *gorm.DB
+	logger *slog.Logger
+}
+
+func NewBadgeRepository(_db *gorm.DB, _logger *slog.Logger) *BadgeRepository {
+	return &BadgeRepository{db: _db, logger: _logger}
+}
+
+// GetUserStats collects everything badge rules can refer to for a single user.
+func (br *BadgeRepository) GetUserStats(userID uint) (*badges.Stats, error) {
+	var user models.User
+	if err := br.db.Select("id", "level", "xp", "streak_count").First(&user, userID).Error; err != nil {
+		return nil, err
+	}
+
+	var counts struct {
+		Completed int
+		Perfect   int
+	}
+	if err := br.db.Model(&models.UserTaskProgress{}).
+		Select("COUNT(*) AS completed, COUNT(*) FILTER (WHERE mistakes = 0) AS perfect").
+		Where("user_id = ? AND is_completed = ?", userID, true).
+		Scan(&counts).Error; err != nil {
+		br.logger.Error("Failed to count completed tasks", "err", err, "userID", userID)
+		return nil, err
+	}
+
+	var languages []struct {
+		Language string
+		Count    int
+	}
+	if err := br.db.Model(&models.UserTaskProgress{}).
+		Select("LOWER(tasks.language) AS language, COUNT(*) AS count").
+		Joins("JOIN tasks ON tasks.id = user_task_progresses.task_id").
+		Where("user_task_progresses.user_id = ? AND user_task_progresses.is_completed = ?", userID, true).
+		Group("LOWER(tasks.language)").
+		Scan(&languages).Error; err != nil {
+		br.logger.Error("Failed to count completed tasks per language", "err", err, "userID", userID)
+		return nil, err
+	}
+
+	stats := &badges.Stats{
+		CompletedTasks:    counts.Completed,
+		PerfectTasks:      counts.Perfect,
+		Streak:            user.StreakCount,
+		Level:             user.Level,
+		XP:                user.XP,
+		LanguageCompleted: make(map[string]int, len(languages)),
+	}
+	for _, l := range languages {
+		stats.LanguageCompleted[l.Language] = l.Count
+	}
+	return stats, nil
+}
+
+func (br *BadgeRepository) GetUnearnedBadges(userID uint) ([]models.Badge, error) {
+	var result []models.Badge
+	err := br.db.
+		Where("id NOT IN (?)", br.db.Model(&models.UserBadge{}).Select("badge_id").Where("user_id = ?", userID)).
+		Order("id ASC").
+		Find(&result).Error
+	if err != nil {
+		br.logger.Error("Failed to get unearned badges", "err", err, "userID", userID)
+		return nil, err
+	}
+	return result, nil
+}
+
+// AwardBadge grants the badge unless the user already has it and reports whether it was new.
+func (br *BadgeRepository) AwardBadge(userID, badgeID uint) (bool, error) {
+	result := br.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "badge_id"}},
+		DoNothing: true,
+	}).Create(&models.UserBadge{UserID: userID, BadgeID: badgeID})
+	if result.Error != nil {
+		br.logger.Error("Failed to award badge", "err", result.Error, "userID", userID, "badgeID", badgeID)
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (br *BadgeRepository) GetAllBadges() ([]models.Badge, error) {
+	var result []models.Badge
+	if err := br.db.Order("id ASC").Find(&result).Error; err != nil {
+		br.logger.Error("Failed to get badges", "err", err)
+		return nil, err
+	}
+	return result, nil
+}
+
+// GetUserBadges returns the user's badges with their Badge preloaded, oldest first.
+func (br *BadgeRepository) GetUserBadges(userID uint) ([]models.UserBadge, error) {
+	var result []models.UserBadge
+	err := br.db.
+		InnerJoins("Badge").
+		Where("user_badges.user_id = ?", userID).
+		Order("user_badges.achieved_at ASC").
+		Find(&result).Error
+	if err != nil {
+		br.logger.Error("Failed to get user badges", "err", err, "userID", userID)
+		return nil, err
+	}
+	return result, nil
+}
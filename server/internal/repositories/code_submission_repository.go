@@ -0,0 +1,38 @@
+package repositories
+
+import (
+	"log/slog"
+
+	"github.com/Suplice/CodeQuest/internal/models"
+	"gorm.io/gorm"
+)
+
+type CodeSubmissionRepository struct {
+	db     *gorm.DB
+	logger *slog.Logger
+}
+
+func NewCodeSubmissionRepository(_db *gorm.DB, _logger *slog.Logger) *CodeSubmissionRepository {
+	return &CodeSubmissionRepository{db: _db, logger: _logger}
+}
+
+func (csr *CodeSubmissionRepository) Create(submission *models.CodeSubmission) error {
+	if err := csr.db.Create(submission).Error; err != nil {
+		csr.logger.Error("Failed to create code submission", "err", err, "userID", submission.UserID, "taskID", submission.TaskID)
+		return err
+	}
+	return nil
+}
+
+func (csr *CodeSubmissionRepository) UpdateResult(submission *models.CodeSubmission) error {
+	err := csr.db.Model(submission).Updates(map[string]interface{}{
+		"status":    submission.Status,
+		"output":    submission.Output,
+		"error_msg": submission.ErrorMsg,
+	}).Error
+	if err != nil {
+		csr.logger.Error("Failed to update code submission result", "err", err, "submissionID", submission.ID)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+package repositories
+
+import (
+	"log/slog"
+
+	"github.com/Suplice/CodeQuest/internal/models"
+	"gorm.io/gorm"
+)
+
+type ContentRepository struct {
+	db     *gorm.DB
+	logger *slog.Logger
+}
+
+func NewContentRepository(db *gorm.DB, logger *slog.Logger) *ContentRepository {
+	return &ContentRepository{db: db, logger: logger}
+}
+
+// ContentChanges is the write set produced by a bundle import. Questions in CreateTasks are
+// created together with their task; CreateQuestions only holds questions of existing tasks.
+type ContentChanges struct {
+	CreateTasks       []*models.Task
+	UpdateTasks       []*models.Task
+	CreateQuestions   []*models.TaskQuestion
+	UpdateQuestions   []*models.TaskQuestion
+	DeleteQuestionIDs []uint
+	CreateBadges      []*models.Badge
+	UpdateBadges      []*models.Badge
+}
+
+func (cr *ContentRepository) GetTasksBySlugs(slugs []string) ([]models.Task, error) {
+	var tasks []models.Task
+	if len(slugs) == 0 {
+		return tasks, nil
+	}
+	if err := cr.db.Preload("TaskQuestions", orderQuestions).Where("slug IN ?", slugs).Find(&tasks).Error; err != nil {
+		cr.logger.Error("Failed to get tasks by slug", "err", err)
+		return nil, err
+	}
+	return tasks, nil
+}
+
+func (cr *ContentRepository) GetBadgesBySlugs(slugs []string) ([]models.Badge, error) {
+	var badges []models.Badge
+	if len(slugs) == 0 {
+		return badges, nil
+	}
+	if err := cr.db.Where("slug IN ?", slugs).Find(&badges).Error; err != nil {
+		cr.logger.Error("Failed to get badges by slug", "err", err)
+		return nil, err
+	}
+	return badges, nil
+}
+
+func (cr *ContentRepository) GetAllContent() ([]models.Task, []models.Badge, error) {
+	var tasks []models.Task
+	if err := cr.db.Preload("TaskQuestions", orderQuestions).Order("id ASC").Find(&tasks).Error; err != nil {
+		cr.logger.Error("Failed to get tasks for export", "err", err)
+		return nil, nil, err
+	}
+
+	var badges []models.Badge
+	if err := cr.db.Order("id ASC").Find(&badges).Error; err != nil {
+		cr.logger.Error("Failed to get badges for export", "err", err)
+		return nil, nil, err
+	}
+	return tasks, badges, nil
+}
+
+// ApplyImport writes every change in a single transaction, so a failing bundle leaves the database untouched.
+func (cr *ContentRepository) ApplyImport(changes *ContentChanges) error {
+	return cr.db.Transaction(func(tx *gorm.DB) error {
+		if len(changes.DeleteQuestionIDs) > 0 {
+			if err := tx.Delete(&models.TaskQuestion{}, changes.DeleteQuestionIDs).Error; err != nil {
+				cr.logger.Error("Failed to delete imported questions", "err", err)
+				return err
+			}
+		}
+
+		for _, task := range changes.CreateTasks {
+			if err := tx.Create(task).Error; err != nil {
+				cr.logger.Error("Failed to create imported task", "err", err, "slug", task.Slug)
+				return err
+			}
+		}
+
+		for _, task := range changes.UpdateTasks {
+			if err := tx.Model(&models.Task{}).Where("id = ?", task.ID).Updates(map[string]interface{}{
+				"title":       task.Title,
+				"description": task.Description,
+				"type":        task.Type,
+				"language":    task.Language,
+				"difficulty":  task.Difficulty,
+				"points":      task.Points,
+				"xp":          task.XP,
+				"is_active":   task.IsActive,
+			}).Error; err != nil {
+				cr.logger.Error("Failed to update imported task", "err", err, "slug", task.Slug)
+				return err
+			}
+		}
+
+		for _, question := range changes.UpdateQuestions {
+			if err := tx.Model(&models.TaskQuestion{}).Where("id = ?", question.ID).Updates(map[string]interface{}{
+				"slug":            question.Slug,
+				"question_text":   question.QuestionText,
+				"type":            question.Type,
+				"options":         question.Options,
+				"grading_config":  question.GradingConfig,
+				"correct_answer":  question.CorrectAnswer,
+				"test_input":      question.TestInput,
+				"expected_output": question.ExpectedOutput,
+				"explanation":     question.Explanation,
+				"position":        question.Position,
+			}).Error; err != nil {
+				cr.logger.Error("Failed to update imported question", "err", err, "questionID", question.ID)
+				return err
+			}
+		}
+
+		for _, question := range changes.CreateQuestions {
+			if err := tx.Create(question).Error; err != nil {
+				cr.logger.Error("Failed to create imported question", "err", err, "taskID", question.TaskID)
+				return err
+			}
+		}
+
+		for _, badge := range changes.CreateBadges {
+			if err := tx.Create(badge).Error; err != nil {
+				cr.logger.Error("Failed to create imported badge", "err", err, "slug", badge.Slug)
+				return err
+			}
+		}
+
+		for _, badge := range changes.UpdateBadges {
+			if err := tx.Model(&models.Badge{}).Where("id = ?", badge.ID).Updates(map[string]interface{}{
+				"name":        badge.Name,
+				"description": badge.Description,
+				"icon_url":    badge.IconURL,
+				"requirement": badge.Requirement,
+			}).Error; err != nil {
+				cr.logger.Error("Failed to update imported badge", "err", err, "slug", badge.Slug)
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,460 @@
+package repositories
+
+import (
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/Suplice/CodeQuest/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrDuelNotFound         = errors.New("duel not found")
+	ErrTaskNotDuelable      = errors.New("task not available for duels")
+	ErrDuelTaskStarted      = errors.New("task already started by a player")
+	ErrDuelNotPending       = errors.New("duel is not pending")
+	ErrDuelNotActive        = errors.New("duel is not active")
+	ErrDuelExpired          = errors.New("duel has expired")
+	ErrDuelNotStarted       = errors.New("duel has not been started")
+	ErrDuelFinishedByPlayer = errors.New("duel already finished by player")
+	ErrDuelQuestionAnswered = errors.New("question already answered")
+)
+
+type DuelRepository struct {
+	db     *gorm.DB
+	logger *slog.Logger
+}
+
+func NewDuelRepository(_db *gorm.DB, _logger *slog.Logger) *DuelRepository {
+	return &DuelRepository{db: _db, logger: _logger}
+}
+
+func (dr *DuelRepository) AreFriends(userID, otherID uint) (bool, error) {
+	var count int64
+	err := dr.db.Model(&models.Friendship{}).
+		Where("status = ?", "accepted").
+		Where("(user_id = ? AND friend_id = ?) OR (user_id = ? AND friend_id = ?)", userID, otherID, otherID, userID).
+		Count(&count).Error
+	if err != nil {
+		dr.logger.Error("Failed to check friendship", "err", err, "userID", userID, "otherID", otherID)
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// duelTasks selects active tasks that can be dueled on: they have questions and none of them
+// needs the code sandbox.
+func (dr *DuelRepository) duelTasks() *gorm.DB {
+	return dr.db.Model(&models.Task{}).
+		Where("is_active = ?", true).
+		Where("EXISTS (SELECT 1 FROM task_questions q WHERE q.task_id = tasks.id AND q.deleted_at IS NULL)").
+		Where("NOT EXISTS (SELECT 1 FROM task_questions q WHERE q.task_id = tasks.id AND q.deleted_at IS NULL AND q.type = ?)", models.TaskTypeCode)
+}
+
+// untouchedBy keeps the tasks none of the players has answered, so nobody enters a duel
+// already knowing the answers.
+func untouchedBy(query *gorm.DB, playerIDs []uint) *gorm.DB {
+	return query.Where("NOT EXISTS (SELECT 1 FROM user_task_progresses p WHERE p.task_id = tasks.id AND p.user_id IN ? AND p.deleted_at IS NULL)", playerIDs)
+}
+
+// GetDuelTask returns the task if it can be dueled on and none of the players has started it.
+func (dr *DuelRepository) GetDuelTask(taskID uint, playerIDs ...uint) (*models.Task, error) {
+	var task models.Task
+	if err := dr.duelTasks().Where("tasks.id = ?", taskID).First(&task).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTaskNotDuelable
+		}
+		dr.logger.Error("Failed to get duel task", "err", err, "taskID", taskID)
+		return nil, err
+	}
+
+	var untouched int64
+	if err := untouchedBy(dr.duelTasks().Where("tasks.id = ?", taskID), playerIDs).Count(&untouched).Error; err != nil {
+		dr.logger.Error("Failed to check duel task progress", "err", err, "taskID", taskID)
+		return nil, err
+	}
+	if untouched == 0 {
+		return nil, ErrDuelTaskStarted
+	}
+	return &task, nil
+}
+
+// GetRandomDuelTask picks a task of the difficulty that can be dueled on and none of the
+// players has started.
+func (dr *DuelRepository) GetRandomDuelTask(difficulty string, playerIDs ...uint) (*models.Task, error) {
+	var task models.Task
+	if err := untouchedBy(dr.duelTasks(), playerIDs).Where("tasks.difficulty = ?", difficulty).Order("RANDOM()").First(&task).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTaskNotDuelable
+		}
+		dr.logger.Error("Failed to pick duel task", "err", err, "difficulty", difficulty)
+		return nil, err
+	}
+	return &task, nil
+}
+
+func (dr *DuelRepository) GetDuelQuestions(taskID uint) ([]models.TaskQuestion, error) {
+	var questions []models.TaskQuestion
+	if err := orderQuestions(dr.db.Where("task_id = ?", taskID)).Find(&questions).Error; err != nil {
+		dr.logger.Error("Failed to get duel questions", "err", err, "taskID", taskID)
+		return nil, err
+	}
+	return questions, nil
+}
+
+// GetSolvedQuestionIDs returns the questions of the task the user answered correctly while
+// practising it, or all of them once the user completed the task.
+func (dr *DuelRepository) GetSolvedQuestionIDs(userID, taskID uint) (map[uint]bool, error) {
+	solved := make(map[uint]bool)
+
+	var progress models.UserTaskProgress
+	err := dr.db.Where("user_id = ? AND task_id = ?", userID, taskID).Take(&progress).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return solved, nil
+	}
+	if err != nil {
+		dr.logger.Error("Failed to get task progress for duel", "err", err, "userID", userID, "taskID", taskID)
+		return nil, err
+	}
+
+	query := dr.db.Model(&models.TaskQuestion{}).Where("task_id = ?", taskID)
+	if !progress.IsCompleted {
+		query = query.Where("id IN (?)", dr.db.Model(&models.UserAnswer{}).
+			Select("task_question_id").
+			Where("user_task_progress_id = ? AND is_correct = ?", progress.ID, true))
+	}
+	var questionIDs []uint
+	if err := query.Pluck("id", &questionIDs).Error; err != nil {
+		dr.logger.Error("Failed to get solved questions for duel", "err", err, "userID", userID, "taskID", taskID)
+		return nil, err
+	}
+	for _, id := range questionIDs {
+		solved[id] = true
+	}
+	return solved, nil
+}
+
+// escrow moves amount points out of the user's balance into the duel, failing when the user
+// cannot afford it.
+func escrow(tx *gorm.DB, userID, duelID uint, amount int) error {
+	if amount == 0 {
+		return nil
+	}
+	var user models.User
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id", "points").First(&user, userID).Error; err != nil {
+		return err
+	}
+	if user.Points < amount {
+		return ErrInsufficientPoints
+	}
+	if err := tx.Model(&user).UpdateColumn("points", gorm.Expr("points - ?", amount)).Error; err != nil {
+		return err
+	}
+	return recordPointTransaction(tx, userID, models.PointTransactionDuelWager, -amount, 0, &duelID)
+}
+
+// release pays amount points out of the duel to the user.
+func release(tx *gorm.DB, userID, duelID uint, amount int, kind string) error {
+	if amount == 0 {
+		return nil
+	}
+	if err := tx.Model(&models.User{}).Where("id = ?", userID).UpdateColumn("points", gorm.Expr("points + ?", amount)).Error; err != nil {
+		return err
+	}
+	return recordPointTransaction(tx, userID, kind, amount, 0, &duelID)
+}
+
+// CreateDuel stores a pending duel and escrows the challenger's wager.
+func (dr *DuelRepository) CreateDuel(duel *models.Duel) error {
+	err := dr.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(duel).Error; err != nil {
+			return err
+		}
+		return escrow(tx, duel.ChallengerID, duel.ID, duel.Wager)
+	})
+	if err != nil && !errors.Is(err, ErrInsufficientPoints) {
+		dr.logger.Error("Failed to create duel", "err", err, "challengerID", duel.ChallengerID)
+	}
+	return err
+}
+
+// lockDuel loads the duel FOR UPDATE, so state changes of one duel run one after another.
+func lockDuel(tx *gorm.DB, duelID uint) (*models.Duel, error) {
+	var duel models.Duel
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&duel, duelID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrDuelNotFound
+		}
+		return nil, err
+	}
+	return &duel, nil
+}
+
+// AcceptDuel escrows the opponent's wager and starts the duel, which must then be finished
+// within playFor.
+func (dr *DuelRepository) AcceptDuel(duelID, opponentID uint, now time.Time, playFor time.Duration) error {
+	err := dr.db.Transaction(func(tx *gorm.DB) error {
+		duel, err := lockDuel(tx, duelID)
+		if err != nil {
+			return err
+		}
+		if duel.OpponentID != opponentID {
+			return ErrDuelNotFound
+		}
+		if duel.Status != models.DuelStatusPending {
+			return ErrDuelNotPending
+		}
+		if !now.Before(duel.ExpiresAt) {
+			return ErrDuelExpired
+		}
+
+		if err := escrow(tx, opponentID, duel.ID, duel.Wager); err != nil {
+			return err
+		}
+		return tx.Model(duel).UpdateColumns(map[string]any{
+			"status":      models.DuelStatusActive,
+			"accepted_at": now,
+			"expires_at":  now.Add(playFor),
+			"updated_at":  now,
+		}).Error
+	})
+	if err != nil {
+		dr.logger.Warn("Could not accept duel", "err", err, "duelID", duelID, "userID", opponentID)
+	}
+	return err
+}
+
+// DeclineDuel closes a pending duel on the opponent's behalf and refunds the challenger.
+func (dr *DuelRepository) DeclineDuel(duelID, opponentID uint, now time.Time) error {
+	return dr.db.Transaction(func(tx *gorm.DB) error {
+		duel, err := lockDuel(tx, duelID)
+		if err != nil {
+			return err
+		}
+		if duel.OpponentID != opponentID {
+			return ErrDuelNotFound
+		}
+		if duel.Status != models.DuelStatusPending {
+			return ErrDuelNotPending
+		}
+
+		if err := release(tx, duel.ChallengerID, duel.ID, duel.Wager, models.PointTransactionDuelRefund); err != nil {
+			return err
+		}
+		return tx.Model(duel).UpdateColumns(map[string]any{
+			"status":      models.DuelStatusDeclined,
+			"finished_at": now,
+			"updated_at":  now,
+		}).Error
+	})
+}
+
+func (dr *DuelRepository) GetDuel(duelID uint) (*models.Duel, error) {
+	var duel models.Duel
+	err := dr.db.Preload("Challenger").Preload("Opponent").Preload("Task").Preload("Answers").
+		First(&duel, duelID).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrDuelNotFound
+		}
+		dr.logger.Error("Failed to get duel", "err", err, "duelID", duelID)
+		return nil, err
+	}
+	return &duel, nil
+}
+
+// playerColumns returns the started, finished and correct columns of the player.
+func playerColumns(duel *models.Duel, userID uint) (started, finished, correct string) {
+	if userID == duel.ChallengerID {
+		return "challenger_started_at", "challenger_finished_at", "challenger_correct"
+	}
+	return "opponent_started_at", "opponent_finished_at", "opponent_correct"
+}
+
+// StartDuel starts the player's clock unless it is already running.
+func (dr *DuelRepository) StartDuel(duel *models.Duel, userID uint, now time.Time) error {
+	started, _, _ := playerColumns(duel, userID)
+	err := dr.db.Model(&models.Duel{}).
+		Where("id = ? AND status = ? AND "+started+" IS NULL", duel.ID, models.DuelStatusActive).
+		UpdateColumn(started, now).Error
+	if err != nil {
+		dr.logger.Error("Failed to start duel", "err", err, "duelID", duel.ID, "userID", userID)
+	}
+	return err
+}
+
+// SaveDuelAnswer records the player's answer. With the last of questionCount answers it stops
+// the player's clock and stores their score. It returns the duel as updated.
+func (dr *DuelRepository) SaveDuelAnswer(duelID, userID, questionID uint, answer string, isCorrect bool, questionCount int, now time.Time) (*models.Duel, error) {
+	var duel *models.Duel
+	err := dr.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		duel, err = lockDuel(tx, duelID)
+		if err != nil {
+			return err
+		}
+		if duel.ChallengerID != userID && duel.OpponentID != userID {
+			return ErrDuelNotFound
+		}
+		if duel.Status != models.DuelStatusActive || !now.Before(duel.ExpiresAt) {
+			return ErrDuelNotActive
+		}
+		_, finishedColumn, correctColumn := playerColumns(duel, userID)
+		startedAt, finishedAt := duel.OpponentStartedAt, duel.OpponentFinishedAt
+		if userID == duel.ChallengerID {
+			startedAt, finishedAt = duel.ChallengerStartedAt, duel.ChallengerFinishedAt
+		}
+		if startedAt == nil {
+			return ErrDuelNotStarted
+		}
+		if finishedAt != nil {
+			return ErrDuelFinishedByPlayer
+		}
+
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&models.DuelAnswer{
+			DuelID:         duelID,
+			UserID:         userID,
+			TaskQuestionID: questionID,
+			Answer:         answer,
+			IsCorrect:      isCorrect,
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrDuelQuestionAnswered
+		}
+
+		var answered, correct int64
+		if err := tx.Model(&models.DuelAnswer{}).Where("duel_id = ? AND user_id = ?", duelID, userID).Count(&answered).Error; err != nil {
+			return err
+		}
+		if int(answered) < questionCount {
+			return nil
+		}
+		if err := tx.Model(&models.DuelAnswer{}).Where("duel_id = ? AND user_id = ? AND is_correct", duelID, userID).Count(&correct).Error; err != nil {
+			return err
+		}
+		return tx.Model(duel).UpdateColumns(map[string]any{
+			finishedColumn: now,
+			correctColumn:  int(correct),
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return dr.GetDuel(duelID)
+}
+
+// SettleDuel closes a pending or active duel with status. The winner takes both wagers; without
+// a winner every wager held is refunded. It reports false when the duel was no longer open.
+func (dr *DuelRepository) SettleDuel(duelID uint, status string, winnerID *uint, now time.Time) (bool, error) {
+	settled := false
+	err := dr.db.Transaction(func(tx *gorm.DB) error {
+		duel, err := lockDuel(tx, duelID)
+		if err != nil {
+			return err
+		}
+		if duel.Status != models.DuelStatusPending && duel.Status != models.DuelStatusActive {
+			return nil
+		}
+
+		players := []uint{duel.ChallengerID}
+		if duel.Status == models.DuelStatusActive {
+			players = append(players, duel.OpponentID)
+		}
+		if winnerID != nil {
+			if err := release(tx, *winnerID, duel.ID, duel.Wager*len(players), models.PointTransactionDuelPayout); err != nil {
+				return err
+			}
+		} else {
+			for _, userID := range players {
+				if err := release(tx, userID, duel.ID, duel.Wager, models.PointTransactionDuelRefund); err != nil {
+					return err
+				}
+			}
+		}
+
+		settled = true
+		return tx.Model(duel).UpdateColumns(map[string]any{
+			"status":      status,
+			"winner_id":   winnerID,
+			"finished_at": now,
+			"updated_at":  now,
+		}).Error
+	})
+	if err != nil {
+		dr.logger.Error("Failed to settle duel", "err", err, "duelID", duelID)
+		return false, err
+	}
+	return settled, nil
+}
+
+// GetDuelsToExpire returns open duels whose deadline has passed.
+func (dr *DuelRepository) GetDuelsToExpire(now time.Time) ([]models.Duel, error) {
+	var duels []models.Duel
+	err := dr.db.Where("status IN ? AND expires_at <= ?", []string{models.DuelStatusPending, models.DuelStatusActive}, now).
+		Order("expires_at ASC").
+		Find(&duels).Error
+	if err != nil {
+		dr.logger.Error("Failed to get duels to expire", "err", err)
+		return nil, err
+	}
+	return duels, nil
+}
+
+// GetUserDuels returns the user's duels in the given statuses, newest first.
+func (dr *DuelRepository) GetUserDuels(userID uint, statuses []string) ([]models.Duel, error) {
+	var duels []models.Duel
+	err := dr.db.Preload("Challenger").Preload("Opponent").Preload("Task").
+		Where("(challenger_id = ? OR opponent_id = ?) AND status IN ?", userID, userID, statuses).
+		Order("created_at DESC, id DESC").
+		Find(&duels).Error
+	if err != nil {
+		dr.logger.Error("Failed to get duels", "err", err, "userID", userID)
+		return nil, err
+	}
+	return duels, nil
+}
+
+// GetDuelHistory returns the closed duels between two users, newest first.
+func (dr *DuelRepository) GetDuelHistory(userID, friendID uint, limit int) ([]models.Duel, error) {
+	var duels []models.Duel
+	err := dr.db.Preload("Challenger").Preload("Opponent").Preload("Task").
+		Where("(challenger_id = ? AND opponent_id = ?) OR (challenger_id = ? AND opponent_id = ?)", userID, friendID, friendID, userID).
+		Where("status IN ?", []string{models.DuelStatusFinished, models.DuelStatusExpired, models.DuelStatusDeclined}).
+		Order("created_at DESC, id DESC").
+		Limit(limit).
+		Find(&duels).Error
+	if err != nil {
+		dr.logger.Error("Failed to get duel history", "err", err, "userID", userID, "friendID", friendID)
+		return nil, err
+	}
+	return duels, nil
+}
+
+// DuelRecord is the outcome count of the finished duels between two users, from the first
+// user's side.
+type DuelRecord struct {
+	Wins   int `json:"wins"`
+	Losses int `json:"losses"`
+	Draws  int `json:"draws"`
+}
+
+func (dr *DuelRepository) GetDuelRecord(userID, friendID uint) (*DuelRecord, error) {
+	var record DuelRecord
+	err := dr.db.Model(&models.Duel{}).
+		Select(`COUNT(*) FILTER (WHERE winner_id = ?) AS wins,
+			COUNT(*) FILTER (WHERE winner_id = ?) AS losses,
+			COUNT(*) FILTER (WHERE winner_id IS NULL) AS draws`, userID, friendID).
+		Where("(challenger_id = ? AND opponent_id = ?) OR (challenger_id = ? AND opponent_id = ?)", userID, friendID, friendID, userID).
+		Where("status = ?", models.DuelStatusFinished).
+		Scan(&record).Error
+	if err != nil {
+		dr.logger.Error("Failed to get duel record", "err", err, "userID", userID, "friendID", friendID)
+		return nil, err
+	}
+	return &record, nil
+}
@@ -0,0 +1,66 @@
+package repositories
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/Suplice/CodeQuest/internal/models"
+	"github.com/Suplice/CodeQuest/internal/testdb"
+)
+
+func TestDuelTasksExcludeTasksPlayersStarted(t *testing.T) {
+	db := testdb.Open(t)
+	repo := NewDuelRepository(db, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	newTask := func(title string) *models.Task {
+		task := &models.Task{
+			Title:      title,
+			Type:       models.TaskTypeQuiz,
+			Language:   "go",
+			Difficulty: models.DifficultyEasy,
+			IsActive:   true,
+			TaskQuestions: []models.TaskQuestion{
+				{QuestionText: title + "?", Type: models.TaskTypeQuiz, CorrectAnswer: "a", Position: 1},
+			},
+		}
+		if err := db.Create(task).Error; err != nil {
+			t.Fatalf("create task %s: %v", title, err)
+		}
+		return task
+	}
+	started := newTask("started")
+	completed := newTask("completed")
+	fresh := newTask("fresh")
+
+	challenger := testdb.CreateUser(t, db, "challenger")
+	opponent := testdb.CreateUser(t, db, "opponent")
+	progress := []models.UserTaskProgress{
+		{UserID: challenger.ID, TaskID: started.ID},
+		{UserID: opponent.ID, TaskID: completed.ID, IsCompleted: true},
+	}
+	if err := db.Create(&progress).Error; err != nil {
+		t.Fatalf("create progress: %v", err)
+	}
+
+	for _, task := range []*models.Task{started, completed} {
+		if _, err := repo.GetDuelTask(task.ID, challenger.ID, opponent.ID); !errors.Is(err, ErrDuelTaskStarted) {
+			t.Errorf("GetDuelTask(%s): err = %v, want ErrDuelTaskStarted", task.Title, err)
+		}
+	}
+	if task, err := repo.GetDuelTask(fresh.ID, challenger.ID, opponent.ID); err != nil || task.ID != fresh.ID {
+		t.Errorf("GetDuelTask(fresh) = %v, %v, want the fresh task", task, err)
+	}
+
+	// Only one task is left that neither player has touched.
+	for range 10 {
+		task, err := repo.GetRandomDuelTask(models.DifficultyEasy, challenger.ID, opponent.ID)
+		if err != nil {
+			t.Fatalf("GetRandomDuelTask: %v", err)
+		}
+		if task.ID != fresh.ID {
+			t.Fatalf("GetRandomDuelTask picked %s, want fresh", task.Title)
+		}
+	}
+}
@@ -4,10 +4,22 @@ import (
 	"errors"
 	"log/slog"
 	"strings"
+	"time"
 
 	"github.com/Suplice/CodeQuest/internal/dto"
 	"github.com/Suplice/CodeQuest/internal/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrFriendRequestBlocked  = errors.New("friend requests between these users are blocked")
+	ErrFriendRequestCooldown = errors.New("friend request was declined recently, try again later")
+	ErrBlockSelf             = errors.New("cannot block yourself")
+	ErrMuteSelf              = errors.New("cannot mute yourself")
+	ErrUserBlocked           = errors.New("user is blocked")
+	ErrUserNotBlocked        = errors.New("user is not blocked")
+	ErrUserNotMuted          = errors.New("user is not muted")
 )
 
 type FriendshipRepository struct {
@@ -128,7 +140,7 @@ func (fr *FriendshipRepository) SearchUsersByUsername(query string, currentUserI
 	}
 	var results []struct{ OtherUserID uint }
 	err := fr.db.Model(&models.Friendship{}).
-		Where("user_id = ? OR friend_id = ?", currentUserID, currentUserID).
+		Where("(user_id = ? OR friend_id = ?) AND status IN ?", currentUserID, currentUserID, []string{"pending", "accepted"}).
 		Select("CASE WHEN user_id = ? THEN friend_id ELSE user_id END AS other_user_id", currentUserID).
 		Scan(&results).Error
     if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) { return nil, err }
@@ -139,7 +151,7 @@ func (fr *FriendshipRepository) SearchUsersByUsername(query string, currentUserI
 
 	searchPattern := "%" + trimmedQuery + "%"
 	err = fr.db.Model(&models.User{}). 
-		Where("username ILIKE ? AND id NOT IN ?", searchPattern, relatedUserIDs).
+		Where("username ILIKE ? AND id NOT IN ? AND id NOT IN (?)", searchPattern, relatedUserIDs, blockedUserIDs(fr.db, currentUserID)).
 		Select("id as ID, username, avatar_url as AvatarURL, level, points"). 
 		Limit(10).
 		Find(&users).Error 
@@ -150,33 +162,53 @@ func (fr *FriendshipRepository) SearchUsersByUsername(query string, currentUserI
 	}
 	return users, nil
 }
-func (fr *FriendshipRepository) CreateFriendRequest(userID, friendID uint) error {
+// CreateFriendRequest sends a request from userID to friendID. A request friendID declined
+// less than cooldown ago can't be sent again yet; an older declined request is replaced.
+func (fr *FriendshipRepository) CreateFriendRequest(userID, friendID uint, cooldown time.Duration) error {
 	if userID == friendID {
 		return errors.New("cannot add yourself as a friend")
 	}
 
-	var existing int64
-	fr.db.Model(&models.Friendship{}).
-		Where("(user_id = ? AND friend_id = ?) OR (user_id = ? AND friend_id = ?)",
-			userID, friendID, friendID, userID).
-		Count(&existing)
-
-	if existing > 0 {
-		return errors.New("friendship already exists or request is pending")
-	}
-
-	request := models.Friendship{
-		UserID:   userID, 
-		FriendID: friendID, 
-		Status:   "pending",
-	}
-
-	err := fr.db.Create(&request).Error
+	blocked, err := isBlocked(fr.db, userID, friendID)
 	if err != nil {
-		fr.logger.Error("Failed to create friend request", "err", err, "userID", userID, "friendID", friendID)
 		return err
 	}
-	return nil
+	if blocked {
+		return ErrFriendRequestBlocked
+	}
+
+	return fr.db.Transaction(func(tx *gorm.DB) error {
+		var existing models.Friendship
+		err := tx.Where("(user_id = ? AND friend_id = ?) OR (user_id = ? AND friend_id = ?)",
+			userID, friendID, friendID, userID).
+			First(&existing).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		if err == nil {
+			if existing.Status != "declined" {
+				return errors.New("friendship already exists or request is pending")
+			}
+			if existing.UserID == userID && existing.DeclinedAt != nil && time.Since(*existing.DeclinedAt) < cooldown {
+				return ErrFriendRequestCooldown
+			}
+			if err := tx.Delete(&existing).Error; err != nil {
+				return err
+			}
+		}
+
+		request := models.Friendship{
+			UserID:   userID,
+			FriendID: friendID,
+			Status:   "pending",
+		}
+		if err := tx.Create(&request).Error; err != nil {
+			fr.logger.Error("Failed to create friend request", "err", err, "userID", userID, "friendID", friendID)
+			return err
+		}
+		return nil
+	})
 }
 
 func (fr *FriendshipRepository) CancelFriendRequest(friendshipID uint, userID uint) error {
@@ -201,14 +233,16 @@ func (fr *FriendshipRepository) CancelFriendRequest(friendshipID uint, userID ui
 	return nil
 }
 
-func (fr *FriendshipRepository) UpdateFriendshipStatus(friendshipID uint, currentUserID uint, newStatus string) error {
+// UpdateFriendshipStatus accepts or declines an incoming request and returns it with both
+// users loaded.
+func (fr *FriendshipRepository) UpdateFriendshipStatus(friendshipID uint, currentUserID uint, newStatus string) (*models.Friendship, error) {
 	if newStatus != "accepted" && newStatus != "declined" { 
-		return errors.New("invalid status provided to repository")
+		return nil, errors.New("invalid status provided to repository")
 	}
 
-	return fr.db.Transaction(func(tx *gorm.DB) error {
-		var request models.Friendship
-		result := tx.Where("id = ? AND friend_id = ? AND status = ?", friendshipID, currentUserID, "pending").First(&request)
+	var request models.Friendship
+	err := fr.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Preload("User").Preload("Friend").Where("id = ? AND friend_id = ? AND status = ?", friendshipID, currentUserID, "pending").First(&request)
 
 		if result.Error != nil {
 			if errors.Is(result.Error, gorm.ErrRecordNotFound) {
@@ -220,13 +254,13 @@ func (fr *FriendshipRepository) UpdateFriendshipStatus(friendshipID uint, curren
 
 		var actionErr error
 		if newStatus == "declined" {
-			actionResult := tx.Delete(&request)
+			actionResult := tx.Model(&request).Updates(map[string]any{"status": "declined", "declined_at": time.Now()})
 			actionErr = actionResult.Error
 			if actionErr == nil && actionResult.RowsAffected == 0 {
 				actionErr = errors.New("failed to decline request, record might have changed unexpectedly")
 			}
 			if actionErr == nil {
-				fr.logger.Info("Friend request declined (deleted)", "friendshipID", friendshipID, "userID", currentUserID)
+				fr.logger.Info("Friend request declined", "friendshipID", friendshipID, "userID", currentUserID)
 			}
 		} else { 
 			actionResult := tx.Model(&request).Update("status", "accepted")
@@ -246,6 +280,10 @@ func (fr *FriendshipRepository) UpdateFriendshipStatus(friendshipID uint, curren
 
 		return nil
 	})
+	if err != nil {
+		return nil, err
+	}
+	return &request, nil
 }
 
 func (fr *FriendshipRepository) DeleteFriendship(friendshipID uint, currentUserID uint) error {
@@ -273,4 +311,142 @@ func (fr *FriendshipRepository) DeleteFriendship(friendshipID uint, currentUserI
 
 	fr.logger.Info("Friendship deleted", "friendshipID", friendshipID, "userID", currentUserID)
 	return nil
-}
\ No newline at end of file
+}
+// blockedUserIDs selects the users userID has blocked or been blocked by.
+func blockedUserIDs(db *gorm.DB, userID uint) *gorm.DB {
+	return db.Model(&models.UserBlock{}).
+		Select("CASE WHEN user_id = ? THEN target_id ELSE user_id END", userID).
+		Where("(user_id = ? OR target_id = ?) AND kind = ?", userID, userID, models.UserBlockKindBlock)
+}
+
+// isBlocked reports whether either user has blocked the other.
+func isBlocked(db *gorm.DB, userID, otherID uint) (bool, error) {
+	var count int64
+	err := db.Model(&models.UserBlock{}).
+		Where("((user_id = ? AND target_id = ?) OR (user_id = ? AND target_id = ?)) AND kind = ?",
+			userID, otherID, otherID, userID, models.UserBlockKindBlock).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func userExists(db *gorm.DB, userID uint) error {
+	var count int64
+	if err := db.Model(&models.User{}).Where("id = ?", userID).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return ErrUserNotFound
+	}
+	return nil
+}
+
+// BlockUser blocks targetID for userID, replacing a mute, and drops any friendship or pending
+// request between them. Declined requests are kept so unblocking doesn't skip the cooldown.
+func (fr *FriendshipRepository) BlockUser(userID, targetID uint) error {
+	if userID == targetID {
+		return ErrBlockSelf
+	}
+
+	err := fr.db.Transaction(func(tx *gorm.DB) error {
+		if err := userExists(tx, targetID); err != nil {
+			return err
+		}
+
+		block := models.UserBlock{UserID: userID, TargetID: targetID, Kind: models.UserBlockKindBlock}
+		err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}, {Name: "target_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"kind", "created_at"}),
+		}).Create(&block).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Where("((user_id = ? AND friend_id = ?) OR (user_id = ? AND friend_id = ?)) AND status IN ?",
+			userID, targetID, targetID, userID, []string{"pending", "accepted"}).
+			Delete(&models.Friendship{}).Error
+	})
+	if err != nil {
+		if !errors.Is(err, ErrUserNotFound) {
+			fr.logger.Error("Failed to block user", "err", err, "userID", userID, "targetID", targetID)
+		}
+		return err
+	}
+
+	fr.logger.Info("User blocked", "userID", userID, "targetID", targetID)
+	return nil
+}
+
+// MuteUser hides targetID's activity from userID's feed. Muting an already muted user is a
+// no-op.
+func (fr *FriendshipRepository) MuteUser(userID, targetID uint) error {
+	if userID == targetID {
+		return ErrMuteSelf
+	}
+	if err := userExists(fr.db, targetID); err != nil {
+		return err
+	}
+
+	var existing models.UserBlock
+	err := fr.db.Where("user_id = ? AND target_id = ?", userID, targetID).First(&existing).Error
+	if err == nil {
+		if existing.Kind == models.UserBlockKindBlock {
+			return ErrUserBlocked
+		}
+		return nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	mute := models.UserBlock{UserID: userID, TargetID: targetID, Kind: models.UserBlockKindMute}
+	if err := fr.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&mute).Error; err != nil {
+		fr.logger.Error("Failed to mute user", "err", err, "userID", userID, "targetID", targetID)
+		return err
+	}
+	return nil
+}
+
+// RemoveRestriction lifts userID's block or mute of targetID.
+func (fr *FriendshipRepository) RemoveRestriction(userID, targetID uint, kind string) error {
+	result := fr.db.Where("user_id = ? AND target_id = ? AND kind = ?", userID, targetID, kind).
+		Delete(&models.UserBlock{})
+	if result.Error != nil {
+		fr.logger.Error("Failed to remove user restriction", "err", result.Error, "userID", userID, "targetID", targetID, "kind", kind)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		if kind == models.UserBlockKindBlock {
+			return ErrUserNotBlocked
+		}
+		return ErrUserNotMuted
+	}
+	return nil
+}
+
+// GetRestrictedUsers returns the users userID has blocked or muted, newest first.
+func (fr *FriendshipRepository) GetRestrictedUsers(userID uint, kind string) ([]dto.RestrictedUserDTO, error) {
+	var blocks []models.UserBlock
+	err := fr.db.Preload("Target").
+		Where("user_id = ? AND kind = ?", userID, kind).
+		Order("created_at DESC").
+		Find(&blocks).Error
+	if err != nil {
+		fr.logger.Error("Failed to get restricted users", "err", err, "userID", userID, "kind", kind)
+		return nil, err
+	}
+
+	results := make([]dto.RestrictedUserDTO, 0, len(blocks))
+	for _, b := range blocks {
+		results = append(results, dto.RestrictedUserDTO{
+			User: dto.UserShortInfo{
+				ID:        b.Target.ID,
+				Username:  b.Target.Username,
+				AvatarURL: b.Target.AvatarURL,
+				Level:     b.Target.Level,
+				Points:    b.Target.Points,
+			},
+			Since: b.CreatedAt,
+		})
+	}
+	return results, nil
+}
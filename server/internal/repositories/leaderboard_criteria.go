@@ -0,0 +1,137 @@
+package repositories
+
+import (
+	"github.com/Suplice/CodeQuest/internal/models"
+	"gorm.io/gorm"
+)
+
+// Criteria is one metric users can be ranked by. Its sources select user_id, value and
+// tiebreak (ordering users that share a value) for every ranked user; the repository adds
+// the user columns, the friends filter and the ranking itself.
+type Criteria struct {
+	// AllTime ranks all-time totals. language is empty unless Languages is set.
+	AllTime func(db *gorm.DB, language string) *gorm.DB
+	// Windowed ranks what was earned in a window, nil when the criteria has no periods.
+	Windowed func(db *gorm.DB, window RankingWindow, language string) *gorm.DB
+	// Languages reports whether the criteria can be scoped to tasks of one language.
+	Languages bool
+}
+
+// rewardKinds are the ledger entries that grant XP and points for doing something, as opposed
+// to spending or wagering them.
+var rewardKinds = []string{models.PointTransactionTaskReward, models.PointTransactionQuestReward}
+
+// minAccuracyAttempts keeps users with a handful of lucky answers off the accuracy board.
+const minAccuracyAttempts = 10
+
+// registerDefaultCriteria registers the criteria shipped with CodeQuest.
+func (lr *LeaderboardRepository) registerDefaultCriteria() {
+	lr.RegisterCriteria("level", Criteria{
+		AllTime: func(db *gorm.DB, _ string) *gorm.DB {
+			return userColumnSource(db, "level", "xp")
+		},
+		// Levels are not earned per window, so a windowed level board ranks the XP earned.
+		Windowed: ledgerSource("SUM(pt.xp)", rewardKinds...),
+	})
+	lr.RegisterCriteria("xp", Criteria{
+		AllTime:   userTotalSource("xp", "t.xp"),
+		Windowed:  ledgerSource("SUM(pt.xp)", rewardKinds...),
+		Languages: true,
+	})
+	lr.RegisterCriteria("points", Criteria{
+		AllTime:   userTotalSource("points", "t.points"),
+		Windowed:  ledgerSource("SUM(pt.points)", rewardKinds...),
+		Languages: true,
+	})
+	lr.RegisterCriteria("completed", Criteria{
+		AllTime: func(db *gorm.DB, language string) *gorm.DB {
+			return completedProgress(db, language).
+				Select("utp.user_id, COUNT(*) AS value, 0 AS tiebreak").
+				Group("utp.user_id")
+		},
+		Windowed:  ledgerSource("COUNT(*)", models.PointTransactionTaskReward),
+		Languages: true,
+	})
+	lr.RegisterCriteria("streak", Criteria{
+		AllTime: func(db *gorm.DB, _ string) *gorm.DB {
+			return userColumnSource(db, "streak_count", "longest_streak").Where("streak_count > 0")
+		},
+	})
+	lr.RegisterCriteria("accuracy", Criteria{
+		AllTime: func(db *gorm.DB, language string) *gorm.DB {
+			query := db.Table("user_task_progresses utp").
+				Select(`utp.user_id,
+					ROUND(100.0 * (SUM(utp.attempts) - SUM(utp.mistakes)) / SUM(utp.attempts)) AS value,
+					SUM(utp.attempts) AS tiebreak`).
+				Where("utp.deleted_at IS NULL").
+				Group("utp.user_id").
+				Having("SUM(utp.attempts) >= ?", minAccuracyAttempts)
+			return withTaskLanguage(query, "utp.task_id", language)
+		},
+		Windowed: func(db *gorm.DB, window RankingWindow, language string) *gorm.DB {
+			query := db.Table("user_answers ua").
+				Select(`utp.user_id,
+					ROUND(100.0 * COUNT(*) FILTER (WHERE ua.is_correct) / COUNT(*)) AS value,
+					COUNT(*) AS tiebreak`).
+				Joins("JOIN user_task_progresses utp ON utp.id = ua.user_task_progress_id").
+				Where("ua.deleted_at IS NULL AND ua.submitted_at >= ? AND ua.submitted_at < ?", window.From, window.To).
+				Group("utp.user_id").
+				Having("COUNT(*) >= ?", minAccuracyAttempts)
+			return withTaskLanguage(query, "utp.task_id", language)
+		},
+		Languages: true,
+	})
+}
+
+// userColumnSource ranks users by a column of their own row.
+func userColumnSource(db *gorm.DB, column, tiebreak string) *gorm.DB {
+	return db.Table("users").
+		Select("id AS user_id, " + column + " AS value, " + tiebreak + " AS tiebreak").
+		Where("deleted_at IS NULL")
+}
+
+// userTotalSource ranks by a running total kept on the user row, or, scoped to a language, by
+// summing that reward over the user's completed tasks of the language.
+func userTotalSource(column, taskReward string) func(db *gorm.DB, language string) *gorm.DB {
+	return func(db *gorm.DB, language string) *gorm.DB {
+		if language == "" {
+			return userColumnSource(db, column, "level")
+		}
+		return completedProgress(db, language).
+			Select("utp.user_id, SUM(" + taskReward + ") AS value, COUNT(*) AS tiebreak").
+			Group("utp.user_id")
+	}
+}
+
+// completedProgress selects completed task progress, joined with tasks as t when scoped to a
+// language.
+func completedProgress(db *gorm.DB, language string) *gorm.DB {
+	query := db.Table("user_task_progresses utp").
+		Where("utp.is_completed = ? AND utp.deleted_at IS NULL", true)
+	return withTaskLanguage(query, "utp.task_id", language)
+}
+
+// ledgerSource ranks by an aggregate over the ledger entries of the given kinds created in the
+// window. Only task rewards belong to a language, so a language scope counts nothing else.
+func ledgerSource(value string, kinds ...string) func(db *gorm.DB, window RankingWindow, language string) *gorm.DB {
+	return func(db *gorm.DB, window RankingWindow, language string) *gorm.DB {
+		scoped := kinds
+		if language != "" {
+			scoped = []string{models.PointTransactionTaskReward}
+		}
+		query := db.Table("point_transactions pt").
+			Select("pt.user_id, "+value+" AS value, COUNT(*) AS tiebreak").
+			Where("pt.kind IN ? AND pt.created_at >= ? AND pt.created_at < ?", scoped, window.From, window.To).
+			Group("pt.user_id").
+			Having(value + " > 0")
+		return withTaskLanguage(query, "pt.reference_id", language)
+	}
+}
+
+// withTaskLanguage keeps only rows about tasks of the language, joining tasks as t.
+func withTaskLanguage(query *gorm.DB, taskColumn, language string) *gorm.DB {
+	if language == "" {
+		return query
+	}
+	return query.Joins("JOIN tasks t ON t.id = "+taskColumn).Where("LOWER(t.language) = LOWER(?)", language)
+}
@@ -3,19 +3,34 @@ package repositories
 import (
 	"errors"
 	"log/slog"
+	"slices"
+	"sync"
+	"time"
 
-	"github.com/Suplice/CodeQuest/internal/dto"
 	"github.com/Suplice/CodeQuest/internal/models"
 	"gorm.io/gorm"
 )
 
+var (
+	ErrCriteriaWithoutPeriods   = errors.New("criteria does not support periods")
+	ErrCriteriaWithoutLanguages = errors.New("criteria does not support a language scope")
+)
+
+// LeaderboardRepository ranks users by the criteria registered on it. New leaderboards are
+// added with RegisterCriteria instead of new query methods.
 type LeaderboardRepository struct {
 	db     *gorm.DB
 	logger *slog.Logger
+
+	mu            sync.RWMutex
+	criteria      map[string]Criteria
+	criteriaNames []string
 }
 
 func NewLeaderboardRepository(_db *gorm.DB, _logger *slog.Logger) *LeaderboardRepository {
-	return &LeaderboardRepository{db: _db, logger: _logger}
+	lr := &LeaderboardRepository{db: _db, logger: _logger, criteria: make(map[string]Criteria)}
+	lr.registerDefaultCriteria()
+	return lr
 }
 
 func (lr *LeaderboardRepository) GetFriendIDs(currentUserID uint) ([]uint, error) {
@@ -28,6 +43,7 @@ func (lr *LeaderboardRepository) GetFriendIDs(currentUserID uint) ([]uint, error
 	err := lr.db.Model(&models.Friendship{}).
 		Where("status = ?", "accepted"). 
 		Where("user_id = ? OR friend_id = ?", currentUserID, currentUserID). 
+		Where("CASE WHEN user_id = ? THEN friend_id ELSE user_id END NOT IN (?)", currentUserID, blockedUserIDs(lr.db, currentUserID)).
 		Select("CASE WHEN user_id = ? THEN friend_id ELSE user_id END AS other_user_id", currentUserID).
 		Scan(&results).Error
 	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
@@ -45,73 +61,156 @@ func (lr *LeaderboardRepository) GetFriendIDs(currentUserID uint) ([]uint, error
 	return friendIDs, nil
 }
 
-func (lr *LeaderboardRepository) GetUsersByLevel(limit int, friendIDs []uint) ([]dto.UserShortInfo, error) {
-	var users []dto.UserShortInfo
-	query := lr.db.Model(&models.User{}).
-		Select("id as ID, username, avatar_url as AvatarURL, level, points").
-		Order("level DESC, xp DESC") 
+// RankingWindow limits a ranking to what was earned in [From, To).
+type RankingWindow struct {
+	From time.Time
+	To   time.Time
+}
 
-	if len(friendIDs) > 0 {
-		query = query.Where("id IN ?", friendIDs)
-	}
+// RankingScope narrows a ranking. A nil Window ranks all-time totals, Language restricts it
+// to tasks of one language and FriendIDs to those users.
+type RankingScope struct {
+	Window    *RankingWindow
+	Language  string
+	FriendIDs []uint
+}
 
-	err := query.Limit(limit).Find(&users).Error
-	if err != nil {
-		lr.logger.Error("Failed to get users by level", "err", err)
-		return nil, err
+// RankingRow is one user's place on a leaderboard. Rank follows competition ranking (equal
+// values share a rank, the next rank skips), Position is the 1-based row number and Total is
+// the number of ranked users.
+type RankingRow struct {
+	UserID    uint `gorm:"column:user_id"`
+	Value     int  `gorm:"column:value"`
+	Rank      int  `gorm:"column:rank"`
+	Position  int  `gorm:"column:position"`
+	Total     int  `gorm:"column:total"`
+	Username  string
+	AvatarURL string `gorm:"column:avatar_url"`
+	Level     int
+	Points    int
+	// AvatarFrame is the user's equipped avatar frame, empty when none is equipped.
+	AvatarFrame string `gorm:"column:avatar_frame"`
+}
+
+func (lr *LeaderboardRepository) RegisterCriteria(name string, criteria Criteria) {
+	lr.mu.Lock()
+	defer lr.mu.Unlock()
+	if _, exists := lr.criteria[name]; !exists {
+		lr.criteriaNames = append(lr.criteriaNames, name)
 	}
-	return users, nil
+	lr.criteria[name] = criteria
+}
+
+func (lr *LeaderboardRepository) GetCriteria(name string) (Criteria, bool) {
+	lr.mu.RLock()
+	defer lr.mu.RUnlock()
+	criteria, ok := lr.criteria[name]
+	return criteria, ok
+}
+
+// CriteriaNames lists the registered criteria in registration order.
+func (lr *LeaderboardRepository) CriteriaNames() []string {
+	lr.mu.RLock()
+	defer lr.mu.RUnlock()
+	return slices.Clone(lr.criteriaNames)
 }
 
-func (lr *LeaderboardRepository) GetUsersByPoints(limit int, friendIDs []uint) ([]dto.UserShortInfo, error) {
-	var users []dto.UserShortInfo
-	query := lr.db.Model(&models.User{}).
-		Select("id as ID, username, avatar_url as AvatarURL, level, points").
-		Order("points DESC, level DESC") 
+// rankingSource selects one row per ranked user with user_id, value, tiebreak and the user
+// columns shown on the leaderboard.
+func (lr *LeaderboardRepository) rankingSource(name string, scope RankingScope) (*gorm.DB, error) {
+	criteria, ok := lr.GetCriteria(name)
+	if !ok {
+		return nil, errors.New("invalid leaderboard criteria")
+	}
+	if scope.Language != "" && !criteria.Languages {
+		return nil, ErrCriteriaWithoutLanguages
+	}
 
-	if len(friendIDs) > 0 {
-		query = query.Where("id IN ?", friendIDs)
+	var metric *gorm.DB
+	if scope.Window != nil {
+		if criteria.Windowed == nil {
+			return nil, ErrCriteriaWithoutPeriods
+		}
+		metric = criteria.Windowed(lr.db, *scope.Window, scope.Language)
+	} else {
+		metric = criteria.AllTime(lr.db, scope.Language)
 	}
 
-	err := query.Limit(limit).Find(&users).Error
+	query := lr.db.Table("(?) AS m", metric).
+		Select("m.user_id, m.value, m.tiebreak, u.username, u.avatar_url, u.avatar_frame, u.level, u.points").
+		Joins("JOIN users u ON u.id = m.user_id AND u.deleted_at IS NULL")
+	if len(scope.FriendIDs) > 0 {
+		query = query.Where("m.user_id IN ?", scope.FriendIDs)
+	}
+	return query, nil
+}
+
+// ranked numbers the rows of a ranking source with window functions.
+func (lr *LeaderboardRepository) ranked(source *gorm.DB) *gorm.DB {
+	return lr.db.Table("(?) AS s", source).
+		Select(`s.*,
+			RANK() OVER (ORDER BY s.value DESC) AS rank,
+			ROW_NUMBER() OVER (ORDER BY s.value DESC, s.tiebreak DESC, s.level DESC, s.user_id ASC) AS position,
+			COUNT(*) OVER () AS total`)
+}
+
+// GetRanking returns a page of the ranking, best first. A limit of 0 returns every row.
+func (lr *LeaderboardRepository) GetRanking(criteria string, scope RankingScope, limit, offset int) ([]RankingRow, error) {
+	source, err := lr.rankingSource(criteria, scope)
 	if err != nil {
-		lr.logger.Error("Failed to get users by points", "err", err)
 		return nil, err
 	}
-	return users, nil
-}
 
-type CompletedCoursesResult struct {
-	UserID          uint `gorm:"column:user_id"`
-	CompletedCount  int  `gorm:"column:completed_count"`
-	Username        string
-	AvatarURL       string `gorm:"column:avatar_url"`
-	Level           int
-	Points          int
+	query := lr.db.Table("(?) AS r", lr.ranked(source)).
+		Where("r.position > ?", offset).
+		Order("r.position ASC")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	var results []RankingRow
+	if err := query.Scan(&results).Error; err != nil {
+		lr.logger.Error("Failed to get ranking", "err", err, "criteria", criteria)
+		return nil, err
+	}
+	return results, nil
 }
 
-func (lr *LeaderboardRepository) GetUsersByCompletedCourses(limit int, friendIDs []uint) ([]CompletedCoursesResult, error) {
-	var results []CompletedCoursesResult
-	query := lr.db.Table("user_task_progresses utp").
-		Select(`utp.user_id,
-                 COUNT(utp.task_id) as completed_count,
-                 u.username,
-                 u.avatar_url,
-                 u.level,
-                 u.points`).
-		Joins("JOIN users u ON u.id = utp.user_id"). 
-		Where("utp.is_completed = ?", true).
-		Group("utp.user_id, u.username, u.avatar_url, u.level, u.points"). 
-		Order("completed_count DESC, u.level DESC") 
-
-	if len(friendIDs) > 0 {
-		query = query.Where("utp.user_id IN ?", friendIDs)
+// GetRankingAround returns the user's row with up to n rows above and below it. It is empty
+// when the user is not ranked.
+func (lr *LeaderboardRepository) GetRankingAround(criteria string, scope RankingScope, userID uint, n int) ([]RankingRow, error) {
+	source, err := lr.rankingSource(criteria, scope)
+	if err != nil {
+		return nil, err
 	}
 
-	err := query.Limit(limit).Scan(&results).Error 
+	var results []RankingRow
+	err = lr.db.Raw(`WITH ranked AS (?),
+		me AS (SELECT ranked.position FROM ranked WHERE ranked.user_id = ?)
+		SELECT ranked.* FROM ranked, me
+		WHERE ranked.position BETWEEN me.position - ? AND me.position + ?
+		ORDER BY ranked.position ASC`, lr.ranked(source), userID, n, n).
+		Scan(&results).Error
 	if err != nil {
-		lr.logger.Error("Failed to get users by completed courses", "err", err)
+		lr.logger.Error("Failed to get ranking around user", "err", err, "criteria", criteria, "userID", userID)
 		return nil, err
 	}
 	return results, nil
-}
\ No newline at end of file
+}
+
+// GetUsersByIDs loads the leaderboard columns of the given users, skipping deleted ones.
+func (lr *LeaderboardRepository) GetUsersByIDs(userIDs []uint) ([]models.User, error) {
+	var users []models.User
+	if len(userIDs) == 0 {
+		return users, nil
+	}
+	err := lr.db.Model(&models.User{}).
+		Select("id, username, avatar_url, avatar_frame, level, xp, points").
+		Where("id IN ?", userIDs).
+		Find(&users).Error
+	if err != nil {
+		lr.logger.Error("Failed to get leaderboard users", "err", err, "count", len(userIDs))
+		return nil, err
+	}
+	return users, nil
+}
@@ -0,0 +1,195 @@
+package repositories
+
+import (
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/Suplice/CodeQuest/internal/models"
+	"gorm.io/gorm"
+)
+
+// ErrNoLeagueMembership means the user has not been placed in a cohort for the week yet.
+var ErrNoLeagueMembership = errors.New("league membership not found")
+
+type LeagueRepository struct {
+	db     *gorm.DB
+	logger *slog.Logger
+}
+
+func NewLeagueRepository(_db *gorm.DB, _logger *slog.Logger) *LeagueRepository {
+	return &LeagueRepository{db: _db, logger: _logger}
+}
+
+func (lr *LeagueRepository) GetUserTier(userID uint) (string, error) {
+	var user models.User
+	if err := lr.db.Select("id, league_tier").First(&user, userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", ErrUserNotFound
+		}
+		lr.logger.Error("Failed to get league tier", "err", err, "userID", userID)
+		return "", err
+	}
+	return user.LeagueTier, nil
+}
+
+// GetMembership returns the user's cohort placement for the week starting at weekStart.
+func (lr *LeagueRepository) GetMembership(userID uint, weekStart time.Time) (*models.LeagueMember, error) {
+	var member models.LeagueMember
+	err := lr.db.Preload("Cohort").
+		Where("user_id = ? AND week_start = ?", userID, weekStart).
+		First(&member).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNoLeagueMembership
+		}
+		lr.logger.Error("Failed to get league membership", "err", err, "userID", userID)
+		return nil, err
+	}
+	return &member, nil
+}
+
+// GetPendingMembership returns the user's latest placement from before weekStart whose cohort
+// has not been finalized yet, or nil when there is none.
+func (lr *LeagueRepository) GetPendingMembership(userID uint, weekStart time.Time) (*models.LeagueMember, error) {
+	var members []models.LeagueMember
+	err := lr.db.Preload("Cohort").
+		Joins("JOIN league_cohorts lc ON lc.id = league_members.cohort_id").
+		Where("league_members.user_id = ? AND league_members.week_start < ? AND lc.finalized_at IS NULL", userID, weekStart).
+		Order("league_members.week_start DESC").
+		Limit(1).
+		Find(&members).Error
+	if err != nil {
+		lr.logger.Error("Failed to get pending league membership", "err", err, "userID", userID)
+		return nil, err
+	}
+	if len(members) == 0 {
+		return nil, nil
+	}
+	return &members[0], nil
+}
+
+// PlaceUser puts the user into the first open cohort of the tier for the week, opening a new
+// cohort when every one already has cohortSize members. Placing a user twice in a week returns
+// the existing placement.
+func (lr *LeagueRepository) PlaceUser(userID uint, tier string, weekStart time.Time, cohortSize int) (*models.LeagueMember, error) {
+	err := lr.db.Transaction(func(tx *gorm.DB) error {
+		var existing int64
+		if err := tx.Model(&models.LeagueMember{}).Where("user_id = ? AND week_start = ?", userID, weekStart).Count(&existing).Error; err != nil {
+			return err
+		}
+		if existing > 0 {
+			return nil
+		}
+
+		// Claiming a seat and counting it happen in one statement, so concurrent placements
+		// never push a cohort past its size.
+		var cohortIDs []uint
+		err := tx.Raw(`UPDATE league_cohorts SET member_count = member_count + 1
+			WHERE id = (
+				SELECT id FROM league_cohorts
+				WHERE week_start = ? AND tier = ? AND finalized_at IS NULL AND member_count < ?
+				ORDER BY id LIMIT 1
+				FOR UPDATE SKIP LOCKED
+			)
+			RETURNING id`, weekStart, tier, cohortSize).
+			Scan(&cohortIDs).Error
+		if err != nil {
+			return err
+		}
+
+		var cohortID uint
+		if len(cohortIDs) > 0 {
+			cohortID = cohortIDs[0]
+		} else {
+			cohort := models.LeagueCohort{WeekStart: weekStart, Tier: tier, MemberCount: 1}
+			if err := tx.Create(&cohort).Error; err != nil {
+				return err
+			}
+			cohortID = cohort.ID
+		}
+
+		return tx.Create(&models.LeagueMember{CohortID: cohortID, UserID: userID, WeekStart: weekStart, Tier: tier}).Error
+	})
+	if err != nil {
+		// A concurrent placement of the same user wins the unique index; use its result.
+		if member, getErr := lr.GetMembership(userID, weekStart); getErr == nil {
+			return member, nil
+		}
+		lr.logger.Error("Failed to place user in league", "err", err, "userID", userID, "tier", tier)
+		return nil, err
+	}
+	return lr.GetMembership(userID, weekStart)
+}
+
+func (lr *LeagueRepository) GetCohortMembers(cohortID uint) ([]models.LeagueMember, error) {
+	var members []models.LeagueMember
+	if err := lr.db.Where("cohort_id = ?", cohortID).Order("id ASC").Find(&members).Error; err != nil {
+		lr.logger.Error("Failed to get league cohort members", "err", err, "cohortID", cohortID)
+		return nil, err
+	}
+	return members, nil
+}
+
+// GetCohortsToFinalize returns the cohorts of weeks that started before weekStart and have not
+// been finalized.
+func (lr *LeagueRepository) GetCohortsToFinalize(weekStart time.Time) ([]models.LeagueCohort, error) {
+	var cohorts []models.LeagueCohort
+	if err := lr.db.Where("week_start < ? AND finalized_at IS NULL", weekStart).Order("week_start ASC, id ASC").Find(&cohorts).Error; err != nil {
+		lr.logger.Error("Failed to get league cohorts to finalize", "err", err)
+		return nil, err
+	}
+	return cohorts, nil
+}
+
+// FinalizeCohort stores the members' final XP, rank and result, moves promoted and demoted
+// users to their new tier and marks the cohort finalized. It reports false when another run
+// finalized the cohort first.
+func (lr *LeagueRepository) FinalizeCohort(cohortID uint, members []models.LeagueMember, newTiers map[uint]string) (bool, error) {
+	finalized := false
+	err := lr.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.LeagueCohort{}).
+			Where("id = ? AND finalized_at IS NULL", cohortID).
+			UpdateColumn("finalized_at", time.Now())
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		finalized = true
+
+		for _, m := range members {
+			err := tx.Model(&models.LeagueMember{}).Where("id = ?", m.ID).
+				UpdateColumns(map[string]any{"xp": m.XP, "rank": m.Rank, "result": m.Result}).Error
+			if err != nil {
+				return err
+			}
+		}
+		for userID, tier := range newTiers {
+			if err := tx.Model(&models.User{}).Where("id = ?", userID).UpdateColumn("league_tier", tier).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		lr.logger.Error("Failed to finalize league cohort", "err", err, "cohortID", cohortID)
+		return false, err
+	}
+	return finalized, nil
+}
+
+// GetHistory returns the user's finalized weeks, most recent first.
+func (lr *LeagueRepository) GetHistory(userID uint, limit int) ([]models.LeagueMember, error) {
+	var members []models.LeagueMember
+	err := lr.db.Where("user_id = ? AND result <> ''", userID).
+		Order("week_start DESC").
+		Limit(limit).
+		Find(&members).Error
+	if err != nil {
+		lr.logger.Error("Failed to get league history", "err", err, "userID", userID)
+		return nil, err
+	}
+	return members, nil
+}
@@ -0,0 +1,100 @@
+package repositories
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/Suplice/CodeQuest/internal/leveling"
+	"github.com/Suplice/CodeQuest/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type LevelingRepository struct {
+	db     *gorm.DB
+	logger *slog.Logger
+}
+
+func NewLevelingRepository(_db *gorm.DB, _logger *slog.Logger) *LevelingRepository {
+	return &LevelingRepository{db: _db, logger: _logger}
+}
+
+// loadCurve reads the configured leveling curve, falling back to the default when none has
+// been saved. It takes a *gorm.DB so rewards can read the curve inside their transaction.
+func loadCurve(db *gorm.DB) (leveling.Curve, error) {
+	var config models.GameConfig
+	err := db.Where("key = ?", models.GameConfigLevelingCurve).Take(&config).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return leveling.Default(), nil
+	}
+	if err != nil {
+		return leveling.Curve{}, err
+	}
+
+	var curve leveling.Curve
+	if err := json.Unmarshal(config.Value, &curve); err != nil {
+		return leveling.Curve{}, err
+	}
+	if err := curve.Validate(); err != nil {
+		return leveling.Curve{}, err
+	}
+	return curve, nil
+}
+
+func (lr *LevelingRepository) GetCurve() (leveling.Curve, error) {
+	curve, err := loadCurve(lr.db)
+	if err != nil {
+		lr.logger.Error("Failed to load leveling curve", "err", err)
+	}
+	return curve, err
+}
+
+// SaveCurve stores the curve and recomputes every user's level with it in one transaction,
+// returning how many users changed level. Like the migration to the first curve, it only
+// raises levels.
+func (lr *LevelingRepository) SaveCurve(curve leveling.Curve) (int, error) {
+	value, err := json.Marshal(curve)
+	if err != nil {
+		return 0, err
+	}
+
+	changed := 0
+	err = lr.db.Transaction(func(tx *gorm.DB) error {
+		config := models.GameConfig{Key: models.GameConfigLevelingCurve, Value: value, UpdatedAt: time.Now()}
+		if err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "key"}},
+			DoUpdates: clause.AssignmentColumns([]string{"value", "updated_at"}),
+		}).Create(&config).Error; err != nil {
+			return err
+		}
+
+		var users []models.User
+		byLevel := make(map[int][]uint)
+		if err := tx.Model(&models.User{}).Select("id", "xp", "level").
+			FindInBatches(&users, 500, func(batch *gorm.DB, _ int) error {
+				for _, u := range users {
+					if level := curve.Raise(u.Level, u.XP); level != u.Level {
+						byLevel[level] = append(byLevel[level], u.ID)
+					}
+				}
+				return nil
+			}).Error; err != nil {
+			return err
+		}
+
+		for level, ids := range byLevel {
+			if err := tx.Model(&models.User{}).Where("id IN ?", ids).UpdateColumn("level", level).Error; err != nil {
+				return err
+			}
+			changed += len(ids)
+		}
+		return nil
+	})
+	if err != nil {
+		lr.logger.Error("Failed to save leveling curve", "err", err)
+		return 0, err
+	}
+	return changed, nil
+}
@@ -0,0 +1,112 @@
+package repositories
+
+import (
+	"errors"
+	"log/slog"
+	"math"
+	"time"
+
+	"github.com/Suplice/CodeQuest/internal/models"
+	"gorm.io/gorm"
+)
+
+// ErrMultiplierEventNotFound is returned for an event ID that does not exist.
+var ErrMultiplierEventNotFound = errors.New("multiplier event not found")
+
+type MultiplierRepository struct {
+	db     *gorm.DB
+	logger *slog.Logger
+}
+
+func NewMultiplierRepository(_db *gorm.DB, _logger *slog.Logger) *MultiplierRepository {
+	return &MultiplierRepository{db: _db, logger: _logger}
+}
+
+// activeMultipliers returns the combined XP and points factors of the events running at t
+// that cover the task. It takes a *gorm.DB so rewards can apply it inside their transaction.
+func activeMultipliers(db *gorm.DB, task models.Task, t time.Time) (xp, points float64, err error) {
+	var running []models.MultiplierEvent
+	if err := db.Where("starts_at <= ? AND ends_at > ?", t, t).Find(&running).Error; err != nil {
+		return 1, 1, err
+	}
+
+	xp, points = 1, 1
+	for _, e := range running {
+		if e.Matches(task) {
+			xp *= e.XPFactor
+			points *= e.PointsFactor
+		}
+	}
+	return xp, points, nil
+}
+
+// applyMultiplier scales a reward, rounding to the nearest whole amount.
+func applyMultiplier(amount int, factor float64) int {
+	return int(math.Round(float64(amount) * factor))
+}
+
+// GetEvents returns every event, the latest first.
+func (mr *MultiplierRepository) GetEvents() ([]models.MultiplierEvent, error) {
+	var events []models.MultiplierEvent
+	if err := mr.db.Order("starts_at DESC, id DESC").Find(&events).Error; err != nil {
+		mr.logger.Error("Failed to get multiplier events", "err", err)
+		return nil, err
+	}
+	return events, nil
+}
+
+// GetCurrentEvents returns the events running at t or starting before until, soonest first.
+func (mr *MultiplierRepository) GetCurrentEvents(t, until time.Time) ([]models.MultiplierEvent, error) {
+	var events []models.MultiplierEvent
+	err := mr.db.Where("ends_at > ? AND starts_at < ?", t, until).
+		Order("starts_at ASC, id ASC").
+		Find(&events).Error
+	if err != nil {
+		mr.logger.Error("Failed to get current multiplier events", "err", err)
+		return nil, err
+	}
+	return events, nil
+}
+
+func (mr *MultiplierRepository) GetEventByID(eventID uint) (*models.MultiplierEvent, error) {
+	var event models.MultiplierEvent
+	if err := mr.db.First(&event, eventID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrMultiplierEventNotFound
+		}
+		mr.logger.Error("Failed to get multiplier event", "err", err, "eventID", eventID)
+		return nil, err
+	}
+	return &event, nil
+}
+
+func (mr *MultiplierRepository) CreateEvent(event *models.MultiplierEvent) error {
+	if err := mr.db.Create(event).Error; err != nil {
+		mr.logger.Error("Failed to create multiplier event", "err", err)
+		return err
+	}
+	return nil
+}
+
+func (mr *MultiplierRepository) UpdateEvent(event *models.MultiplierEvent) error {
+	err := mr.db.Model(event).
+		Select("name", "starts_at", "ends_at", "language", "difficulty", "task_type", "xp_factor", "points_factor").
+		Updates(event).Error
+	if err != nil {
+		mr.logger.Error("Failed to update multiplier event", "err", err, "eventID", event.ID)
+		return err
+	}
+	return nil
+}
+
+func (mr *MultiplierRepository) DeleteEvent(eventID uint) error {
+	result := mr.db.Delete(&models.MultiplierEvent{}, eventID)
+	if result.Error != nil {
+		mr.logger.Error("Failed to delete multiplier event", "err", result.Error, "eventID", eventID)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrMultiplierEventNotFound
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package repositories
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Suplice/CodeQuest/internal/models"
+	"github.com/Suplice/CodeQuest/internal/testdb"
+)
+
+func TestApplyMultiplier(t *testing.T) {
+	tests := []struct {
+		amount int
+		factor float64
+		want   int
+	}{
+		{10, 1, 10},
+		{10, 2, 20},
+		{15, 1.5, 23},
+		{7, 0.5, 4},
+		{3, 1.1, 3},
+		{1, 0.4, 0},
+		{0, 3, 0},
+		{100, 2 * 1.5, 300},
+	}
+	for _, tt := range tests {
+		if got := applyMultiplier(tt.amount, tt.factor); got != tt.want {
+			t.Errorf("applyMultiplier(%d, %v) = %d, want %d", tt.amount, tt.factor, got, tt.want)
+		}
+	}
+}
+
+func TestActiveMultipliersStackMatchingRunningEvents(t *testing.T) {
+	db := testdb.Open(t)
+
+	now := time.Now()
+	events := []models.MultiplierEvent{
+		{Name: "double xp", StartsAt: now.Add(-time.Hour), EndsAt: now.Add(time.Hour), XPFactor: 2, PointsFactor: 1},
+		{Name: "go week", StartsAt: now.Add(-time.Hour), EndsAt: now.Add(time.Hour), Language: "go", XPFactor: 1.5, PointsFactor: 3},
+		{Name: "python week", StartsAt: now.Add(-time.Hour), EndsAt: now.Add(time.Hour), Language: "python", XPFactor: 10, PointsFactor: 10},
+		{Name: "ended", StartsAt: now.Add(-2 * time.Hour), EndsAt: now, XPFactor: 10, PointsFactor: 10},
+		{Name: "upcoming", StartsAt: now.Add(time.Minute), EndsAt: now.Add(time.Hour), XPFactor: 10, PointsFactor: 10},
+	}
+	if err := db.Create(&events).Error; err != nil {
+		t.Fatalf("create events: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		task       models.Task
+		xp, points float64
+	}{
+		{"stacked", models.Task{Language: "go"}, 3, 3},
+		{"unscoped only", models.Task{Language: "javascript"}, 2, 1},
+	}
+	for _, tt := range tests {
+		xp, points, err := activeMultipliers(db, tt.task, now)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if xp != tt.xp || points != tt.points {
+			t.Errorf("%s: factors %v and %v, want %v and %v", tt.name, xp, points, tt.xp, tt.points)
+		}
+	}
+}
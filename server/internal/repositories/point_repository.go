@@ -0,0 +1,73 @@
+package repositories
+
+import (
+	"errors"
+	"log/slog"
+
+	"github.com/Suplice/CodeQuest/internal/models"
+	"gorm.io/gorm"
+)
+
+// ErrInsufficientPoints is returned when a user cannot afford a purchase or stake.
+var ErrInsufficientPoints = errors.New("not enough points")
+
+type PointRepository struct {
+	db     *gorm.DB
+	logger *slog.Logger
+}
+
+func NewPointRepository(_db *gorm.DB, _logger *slog.Logger) *PointRepository {
+	return &PointRepository{db: _db, logger: _logger}
+}
+
+// recordPointTransaction appends a ledger entry. It must run in the same transaction as the
+// balance change it describes.
+func recordPointTransaction(tx *gorm.DB, userID uint, kind string, points, freezes int, referenceID *uint) error {
+	return tx.Create(&models.PointTransaction{
+		UserID:      userID,
+		Kind:        kind,
+		Points:      points,
+		Freezes:     freezes,
+		ReferenceID: referenceID,
+	}).Error
+}
+
+// recordTaskReward appends the ledger entry for completing a task, with the multipliers that
+// were applied to it. Time-windowed leaderboards sum these entries, so it is written even when
+// the task grants no points.
+func recordTaskReward(tx *gorm.DB, userID, taskID uint, xp, points int, xpMultiplier, pointsMultiplier float64) error {
+	return tx.Create(&models.PointTransaction{
+		UserID:           userID,
+		Kind:             models.PointTransactionTaskReward,
+		Points:           points,
+		XP:               xp,
+		ReferenceID:      &taskID,
+		XPMultiplier:     xpMultiplier,
+		PointsMultiplier: pointsMultiplier,
+	}).Error
+}
+
+// recordQuestReward appends the ledger entry for completing a quest.
+func recordQuestReward(tx *gorm.DB, userID, questID uint, xp, points int) error {
+	return tx.Create(&models.PointTransaction{
+		UserID:      userID,
+		Kind:        models.PointTransactionQuestReward,
+		Points:      points,
+		XP:          xp,
+		ReferenceID: &questID,
+	}).Error
+}
+
+func (pr *PointRepository) GetTransactions(userID uint, limit, offset int) ([]models.PointTransaction, error) {
+	var transactions []models.PointTransaction
+	err := pr.db.Where("user_id = ?", userID).
+		Order("created_at DESC, id DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&transactions).Error
+	if err != nil {
+		pr.logger.Error("Failed to get point transactions", "err", err, "userID", userID)
+		return nil, err
+	}
+	return transactions, nil
+}
@@ -1,6 +1,7 @@
 package repositories
 
 import (
+	"errors"
 	"log/slog"
 
 	"github.com/Suplice/CodeQuest/internal/dto"
@@ -18,9 +19,28 @@ func NewProfileRepository(_db *gorm.DB, _logger *slog.Logger) *ProfileRepository
 }
 
 func (pr *ProfileRepository) GetProfileData(profileUserID, currentUserID uint) (*dto.ProfileDTO, error) {
+	// Users who blocked the viewer look like they don't exist.
+	var restrictions []models.UserBlock
+	if profileUserID != currentUserID {
+		err := pr.db.Where("(user_id = ? AND target_id = ?) OR (user_id = ? AND target_id = ?)",
+			currentUserID, profileUserID, profileUserID, currentUserID).
+			Find(&restrictions).Error
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, r := range restrictions {
+		if r.UserID == profileUserID && r.Kind == models.UserBlockKindBlock {
+			return nil, ErrUserNotFound
+		}
+	}
+
 	var profileUser models.User
 	if err := pr.db.First(&profileUser, profileUserID).Error; err != nil {
 		pr.logger.Error("User not found for profile", "err", err, "userID", profileUserID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
 		return nil, err 
 	}
 
@@ -29,7 +49,7 @@ func (pr *ProfileRepository) GetProfileData(profileUserID, currentUserID uint) (
 	pr.db.Model(&models.UserTaskProgress{}).Where("user_id = ?", profileUserID).Select("COALESCE(SUM(mistakes), 0)").Scan(&totalMistakes)
 
 	var tasks []models.Task
-	if err := pr.db.Preload("UserProgress", "user_id = ?", profileUserID).Find(&tasks).Error; err != nil {
+	if err := pr.db.Preload("UserProgress", "user_id = ?", profileUserID).Where("is_active = ?", true).Find(&tasks).Error; err != nil {
 		return nil, err
 	}
 	tasksDTO := make([]dto.TaskForUserDTO, len(tasks))
@@ -61,7 +81,7 @@ func (pr *ProfileRepository) GetProfileData(profileUserID, currentUserID uint) (
 			return nil, err 
 		}
 
-		if err == gorm.ErrRecordNotFound {
+		if err == gorm.ErrRecordNotFound || friendship.Status == "declined" {
 			friendshipStatus = &dto.FriendshipStatusDTO{Status: "not_friends"}
 		} else {
 			status := ""
@@ -79,6 +99,17 @@ func (pr *ProfileRepository) GetProfileData(profileUserID, currentUserID uint) (
 				FriendshipID: friendship.ID,
 			}
 		}
+
+		for _, r := range restrictions {
+			if r.UserID != currentUserID {
+				continue
+			}
+			if r.Kind == models.UserBlockKindBlock {
+				friendshipStatus = &dto.FriendshipStatusDTO{Status: "blocked"}
+			} else {
+				friendshipStatus.Muted = true
+			}
+		}
 	}
 
 	profileDTO := &dto.ProfileDTO{
@@ -0,0 +1,159 @@
+package repositories
+
+import (
+	"errors"
+	"log/slog"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/Suplice/CodeQuest/internal/models"
+	"github.com/Suplice/CodeQuest/internal/quests"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type QuestRepository struct {
+	db     *gorm.DB
+	logger *slog.Logger
+}
+
+func NewQuestRepository(_db *gorm.DB, _logger *slog.Logger) *QuestRepository {
+	return &QuestRepository{db: _db, logger: _logger}
+}
+
+func (qr *QuestRepository) GetUserTimezone(userID uint) (string, error) {
+	var user models.User
+	if err := qr.db.Select("id, timezone").First(&user, userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", ErrUserNotFound
+		}
+		qr.logger.Error("Failed to get user timezone", "err", err, "userID", userID)
+		return "", err
+	}
+	return user.Timezone, nil
+}
+
+// AssignQuests stores the quests, skipping any the user already has for the period.
+func (qr *QuestRepository) AssignQuests(quests []models.UserQuest) error {
+	if len(quests) == 0 {
+		return nil
+	}
+	if err := qr.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&quests).Error; err != nil {
+		qr.logger.Error("Failed to assign quests", "err", err, "userID", quests[0].UserID)
+		return err
+	}
+	return nil
+}
+
+// GetQuests returns the user's quests for the day and the week starting at the given dates.
+func (qr *QuestRepository) GetQuests(userID uint, dayStart, weekStart time.Time) ([]models.UserQuest, error) {
+	var userQuests []models.UserQuest
+	err := qr.db.Where("user_id = ? AND ((period = ? AND period_start = ?) OR (period = ? AND period_start = ?))",
+		userID, quests.PeriodDaily, dayStart, quests.PeriodWeekly, weekStart).
+		Order("id ASC").
+		Find(&userQuests).Error
+	if err != nil {
+		qr.logger.Error("Failed to get quests", "err", err, "userID", userID)
+		return nil, err
+	}
+	return userQuests, nil
+}
+
+// QuestTaskFacts describes a completed task for quest progress.
+type QuestTaskFacts struct {
+	Difficulty string
+	Language   string
+	Mistakes   int
+}
+
+func (qr *QuestRepository) GetTaskFacts(userID, taskID uint) (*QuestTaskFacts, error) {
+	var facts []QuestTaskFacts
+	err := qr.db.Table("user_task_progresses utp").
+		Select("t.difficulty, t.language, utp.mistakes").
+		Joins("JOIN tasks t ON t.id = utp.task_id").
+		Where("utp.user_id = ? AND utp.task_id = ? AND utp.deleted_at IS NULL", userID, taskID).
+		Limit(1).
+		Scan(&facts).Error
+	if err != nil {
+		qr.logger.Error("Failed to get quest task facts", "err", err, "userID", userID, "taskID", taskID)
+		return nil, err
+	}
+	if len(facts) == 0 {
+		return nil, errors.New("task progress not found")
+	}
+	return &facts[0], nil
+}
+
+type QuestAdvanceResult struct {
+	// Completed are the quests this advance completed, with their rewards granted.
+	Completed     []models.UserQuest
+	PreviousLevel int
+	// User is the rewarded user, nil when nothing was completed.
+	User *models.User
+}
+
+// AdvanceQuests adds the increments, keyed by quest ID, to the user's unfinished quests.
+// Quests reaching their target are completed and their rewards granted in the same
+// transaction, so a quest pays out once however many events race to finish it.
+func (qr *QuestRepository) AdvanceQuests(userID uint, increments map[uint]int, now time.Time) (*QuestAdvanceResult, error) {
+	result := &QuestAdvanceResult{}
+	err := qr.db.Transaction(func(tx *gorm.DB) error {
+		result.Completed = nil
+		// Quests are updated in ID order so concurrent advances lock them in the same order.
+		for _, questID := range slices.Sorted(maps.Keys(increments)) {
+			by := increments[questID]
+			var advanced []models.UserQuest
+			err := tx.Raw(`UPDATE user_quests
+				SET progress = LEAST(target, progress + ?),
+					completed_at = CASE WHEN progress + ? >= target THEN ?::timestamptz END
+				WHERE id = ? AND user_id = ? AND completed_at IS NULL
+				RETURNING *`, by, by, now, questID, userID).
+				Scan(&advanced).Error
+			if err != nil {
+				return err
+			}
+			for _, q := range advanced {
+				if q.CompletedAt != nil {
+					result.Completed = append(result.Completed, q)
+				}
+			}
+		}
+		if len(result.Completed) == 0 {
+			return nil
+		}
+
+		var user models.User
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&user, userID).Error; err != nil {
+			return err
+		}
+		for _, q := range result.Completed {
+			user.XP += q.RewardXP
+			user.Points += q.RewardPoints
+			if err := recordQuestReward(tx, userID, q.ID, q.RewardXP, q.RewardPoints); err != nil {
+				return err
+			}
+		}
+
+		curve, err := loadCurve(tx)
+		if err != nil {
+			return err
+		}
+		result.PreviousLevel = user.Level
+		user.Level = curve.Raise(user.Level, user.XP)
+
+		err = tx.Model(&models.User{}).Where("id = ?", userID).
+			UpdateColumns(map[string]any{"xp": user.XP, "points": user.Points, "level": user.Level}).Error
+		if err != nil {
+			return err
+		}
+		curve.Annotate(&user)
+		result.User = &user
+		return nil
+	})
+	if err != nil {
+		qr.logger.Error("Failed to advance quests", "err", err, "userID", userID)
+		return nil, err
+	}
+	return result, nil
+}
@@ -27,7 +27,7 @@ func (sr *SearchRepository) SearchUsers(query string, currentUserID uint, limit
 
 	searchPattern := "%" + trimmedQuery + "%"
 	err := sr.db.Model(&models.User{}).
-		Where("username ILIKE ? AND id != ?", searchPattern, currentUserID). 
+		Where("username ILIKE ? AND id != ? AND id NOT IN (?)", searchPattern, currentUserID, blockedUserIDs(sr.db, currentUserID)).
 		Select("id as ID, username, avatar_url as AvatarURL").            
 		Limit(limit).
 		Find(&users).Error
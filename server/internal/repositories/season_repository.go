@@ -0,0 +1,164 @@
+package repositories
+
+import (
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/Suplice/CodeQuest/internal/models"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrSeasonNotFound = errors.New("season not found")
+	ErrNoActiveSeason = errors.New("no active season")
+)
+
+type SeasonRepository struct {
+	db     *gorm.DB
+	logger *slog.Logger
+}
+
+func NewSeasonRepository(_db *gorm.DB, _logger *slog.Logger) *SeasonRepository {
+	return &SeasonRepository{db: _db, logger: _logger}
+}
+
+func (sr *SeasonRepository) GetSeasons() ([]models.Season, error) {
+	var seasons []models.Season
+	if err := sr.db.Order("starts_at DESC").Find(&seasons).Error; err != nil {
+		sr.logger.Error("Failed to get seasons", "err", err)
+		return nil, err
+	}
+	return seasons, nil
+}
+
+func (sr *SeasonRepository) GetSeasonByID(seasonID uint) (*models.Season, error) {
+	var season models.Season
+	if err := sr.db.First(&season, seasonID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSeasonNotFound
+		}
+		sr.logger.Error("Failed to get season", "err", err, "seasonID", seasonID)
+		return nil, err
+	}
+	return &season, nil
+}
+
+// GetSeasonAt returns the season running at t.
+func (sr *SeasonRepository) GetSeasonAt(t time.Time) (*models.Season, error) {
+	var season models.Season
+	if err := sr.db.Where("starts_at <= ? AND ends_at > ?", t, t).First(&season).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNoActiveSeason
+		}
+		sr.logger.Error("Failed to get current season", "err", err)
+		return nil, err
+	}
+	return &season, nil
+}
+
+// SeasonOverlaps reports whether [startsAt, endsAt) intersects any season other than seasonID.
+func (sr *SeasonRepository) SeasonOverlaps(startsAt, endsAt time.Time, seasonID uint) (bool, error) {
+	var count int64
+	err := sr.db.Model(&models.Season{}).
+		Where("starts_at < ? AND ends_at > ? AND id <> ?", endsAt, startsAt, seasonID).
+		Count(&count).Error
+	if err != nil {
+		sr.logger.Error("Failed to check season overlap", "err", err)
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (sr *SeasonRepository) CreateSeason(season *models.Season) error {
+	if err := sr.db.Create(season).Error; err != nil {
+		sr.logger.Error("Failed to create season", "err", err)
+		return err
+	}
+	return nil
+}
+
+func (sr *SeasonRepository) UpdateSeason(season *models.Season) error {
+	if err := sr.db.Model(season).Select("name", "starts_at", "ends_at").Updates(season).Error; err != nil {
+		sr.logger.Error("Failed to update season", "err", err, "seasonID", season.ID)
+		return err
+	}
+	return nil
+}
+
+func (sr *SeasonRepository) DeleteSeason(seasonID uint) error {
+	return sr.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("season_id = ?", seasonID).Delete(&models.SeasonStanding{}).Error; err != nil {
+			return err
+		}
+		result := tx.Delete(&models.Season{}, seasonID)
+		if result.Error != nil {
+			sr.logger.Error("Failed to delete season", "err", result.Error, "seasonID", seasonID)
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrSeasonNotFound
+		}
+		return nil
+	})
+}
+
+// GetSeasonsToArchive returns the seasons that ended before now and have no archived standings.
+func (sr *SeasonRepository) GetSeasonsToArchive(now time.Time) ([]models.Season, error) {
+	var seasons []models.Season
+	if err := sr.db.Where("ends_at <= ? AND archived_at IS NULL", now).Order("ends_at ASC").Find(&seasons).Error; err != nil {
+		sr.logger.Error("Failed to get seasons to archive", "err", err)
+		return nil, err
+	}
+	return seasons, nil
+}
+
+// ArchiveSeason stores the final standings and marks the season archived. It reports false
+// when another run archived the season first.
+func (sr *SeasonRepository) ArchiveSeason(seasonID uint, standings []models.SeasonStanding) (bool, error) {
+	archived := false
+	err := sr.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Season{}).
+			Where("id = ? AND archived_at IS NULL", seasonID).
+			UpdateColumn("archived_at", time.Now())
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		archived = true
+		if len(standings) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(standings, 500).Error
+	})
+	if err != nil {
+		sr.logger.Error("Failed to archive season", "err", err, "seasonID", seasonID)
+		return false, err
+	}
+	return archived, nil
+}
+
+// GetStandings returns the archived standings of a season for one criteria, best first.
+func (sr *SeasonRepository) GetStandings(seasonID uint, criteria string, limit int, friendIDs []uint) ([]RankingRow, error) {
+	var results []RankingRow
+	query := sr.db.Table("season_standings ss").
+		Select("ss.user_id, ss.value, ss.rank, u.username, u.avatar_url, u.avatar_frame, u.level, u.points").
+		Joins("JOIN users u ON u.id = ss.user_id").
+		Where("ss.season_id = ? AND ss.criteria = ?", seasonID, criteria).
+		Order("ss.rank ASC, ss.user_id ASC")
+
+	if len(friendIDs) > 0 {
+		query = query.Where("ss.user_id IN ?", friendIDs)
+	}
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	if err := query.Scan(&results).Error; err != nil {
+		sr.logger.Error("Failed to get season standings", "err", err, "seasonID", seasonID, "criteria", criteria)
+		return nil, err
+	}
+	return results, nil
+}
@@ -0,0 +1,365 @@
+package repositories
+
+import (
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/Suplice/CodeQuest/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// equipColumns maps each cosmetic kind to the user column holding the equipped item's asset.
+var equipColumns = map[string]string{
+	models.ShopItemAvatarFrame:  "avatar_frame",
+	models.ShopItemProfileTheme: "profile_theme",
+}
+
+var (
+	ErrShopItemNotFound   = errors.New("shop item not found")
+	ErrShopItemNotForSale = errors.New("shop item is not for sale")
+	ErrShopItemPurchased  = errors.New("shop item has been purchased")
+	ErrCosmeticQuantity   = errors.New("cosmetic items can only be bought once")
+	ErrItemAlreadyOwned   = errors.New("item already owned")
+	ErrItemNotOwned       = errors.New("item not owned")
+	ErrItemNotEquippable  = errors.New("item cannot be equipped")
+	ErrNoHintTokens       = errors.New("no hint tokens left")
+)
+
+type ShopRepository struct {
+	db     *gorm.DB
+	logger *slog.Logger
+}
+
+func NewShopRepository(_db *gorm.DB, _logger *slog.Logger) *ShopRepository {
+	return &ShopRepository{db: _db, logger: _logger}
+}
+
+// GetItems returns the shop catalog, cheapest first. Inactive items are left out unless
+// includeInactive is set.
+func (sr *ShopRepository) GetItems(includeInactive bool) ([]models.ShopItem, error) {
+	query := sr.db.Order("price ASC, id ASC")
+	if !includeInactive {
+		query = query.Where("is_active = ?", true)
+	}
+
+	var items []models.ShopItem
+	if err := query.Find(&items).Error; err != nil {
+		sr.logger.Error("Failed to get shop items", "err", err)
+		return nil, err
+	}
+	return items, nil
+}
+
+func (sr *ShopRepository) GetItemByID(itemID uint) (*models.ShopItem, error) {
+	var item models.ShopItem
+	if err := sr.db.First(&item, itemID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrShopItemNotFound
+		}
+		sr.logger.Error("Failed to get shop item", "err", err, "itemID", itemID)
+		return nil, err
+	}
+	return &item, nil
+}
+
+func (sr *ShopRepository) CreateItem(item *models.ShopItem) error {
+	if err := sr.db.Create(item).Error; err != nil {
+		sr.logger.Error("Failed to create shop item", "err", err)
+		return err
+	}
+	return nil
+}
+
+// GetItemForSale returns the cheapest active item of the kind.
+func (sr *ShopRepository) GetItemForSale(kind string) (*models.ShopItem, error) {
+	var item models.ShopItem
+	if err := sr.db.Where("kind = ? AND is_active = ?", kind, true).Order("price ASC, id ASC").First(&item).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrShopItemNotFound
+		}
+		sr.logger.Error("Failed to get shop item for sale", "err", err, "kind", kind)
+		return nil, err
+	}
+	return &item, nil
+}
+
+// ItemSlugTaken reports whether an item other than excludeID already uses the slug.
+func (sr *ShopRepository) ItemSlugTaken(slug string, excludeID uint) (bool, error) {
+	var count int64
+	if err := sr.db.Model(&models.ShopItem{}).Where("slug = ? AND id <> ?", slug, excludeID).Count(&count).Error; err != nil {
+		sr.logger.Error("Failed to check shop item slug", "err", err, "slug", slug)
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// ItemPurchased reports whether anyone has bought the item, going by the ledger so that
+// streak freezes, which never reach an inventory, count too.
+func (sr *ShopRepository) ItemPurchased(itemID uint) (bool, error) {
+	var count int64
+	err := sr.db.Model(&models.PointTransaction{}).
+		Where("kind = ? AND reference_id = ?", models.PointTransactionShopPurchase, itemID).
+		Count(&count).Error
+	if err != nil {
+		sr.logger.Error("Failed to check shop item purchases", "err", err, "itemID", itemID)
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// UpdateItem saves the item and refreshes the asset shown for users who have it equipped.
+func (sr *ShopRepository) UpdateItem(item *models.ShopItem) error {
+	err := sr.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.ShopItem{}).Where("id = ?", item.ID).Updates(map[string]any{
+			"slug":        item.Slug,
+			"name":        item.Name,
+			"description": item.Description,
+			"kind":        item.Kind,
+			"price":       item.Price,
+			"asset_url":   item.AssetURL,
+			"is_active":   item.IsActive,
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrShopItemNotFound
+		}
+
+		column, ok := equipColumns[item.Kind]
+		if !ok {
+			return nil
+		}
+		return tx.Model(&models.User{}).
+			Where("id IN (?)", tx.Model(&models.UserItem{}).Select("user_id").Where("item_id = ? AND equipped", item.ID)).
+			UpdateColumn(column, item.AssetURL).Error
+	})
+	if err != nil && !errors.Is(err, ErrShopItemNotFound) {
+		sr.logger.Error("Failed to update shop item", "err", err, "itemID", item.ID)
+	}
+	return err
+}
+
+// DeleteItem removes an item nobody has bought. Sold items can only be deactivated.
+func (sr *ShopRepository) DeleteItem(itemID uint) error {
+	purchased, err := sr.ItemPurchased(itemID)
+	if err != nil {
+		return err
+	}
+	if purchased {
+		return ErrShopItemPurchased
+	}
+
+	result := sr.db.Delete(&models.ShopItem{}, itemID)
+	if result.Error != nil {
+		sr.logger.Error("Failed to delete shop item", "err", result.Error, "itemID", itemID)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrShopItemNotFound
+	}
+	return nil
+}
+
+// GetWallet returns the user's balance, freezes and equipped cosmetics.
+func (sr *ShopRepository) GetWallet(userID uint) (*models.User, error) {
+	var user models.User
+	err := sr.db.Select("id", "points", "streak_freezes", "avatar_frame", "profile_theme").First(&user, userID).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		sr.logger.Error("Failed to get wallet", "err", err, "userID", userID)
+		return nil, err
+	}
+	return &user, nil
+}
+
+// GetInventory returns the items the user holds, in the order they were first bought.
+func (sr *ShopRepository) GetInventory(userID uint) ([]models.UserItem, error) {
+	var items []models.UserItem
+	err := sr.db.Preload("Item").
+		Where("user_id = ? AND quantity > 0", userID).
+		Order("id ASC").
+		Find(&items).Error
+	if err != nil {
+		sr.logger.Error("Failed to get inventory", "err", err, "userID", userID)
+		return nil, err
+	}
+	return items, nil
+}
+
+// Purchase trades points for quantity of the item. The user row is locked for the whole
+// purchase, so concurrent purchases are checked against the balance one after another.
+// Streak freezes go to User.StreakFreezes, never beyond maxFreezes; everything else goes to
+// the inventory.
+func (sr *ShopRepository) Purchase(userID, itemID uint, quantity, maxFreezes int) (*models.User, error) {
+	var user models.User
+	err := sr.db.Transaction(func(tx *gorm.DB) error {
+		var item models.ShopItem
+		if err := tx.First(&item, itemID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrShopItemNotFound
+			}
+			return err
+		}
+		if !item.IsActive {
+			return ErrShopItemNotForSale
+		}
+
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id", "points", "streak_freezes", "avatar_frame", "profile_theme").
+			First(&user, userID).Error; err != nil {
+			return err
+		}
+
+		freezes := 0
+		switch {
+		case models.IsCosmeticItem(item.Kind):
+			if quantity != 1 {
+				return ErrCosmeticQuantity
+			}
+			var owned int64
+			if err := tx.Model(&models.UserItem{}).Where("user_id = ? AND item_id = ? AND quantity > 0", userID, itemID).Count(&owned).Error; err != nil {
+				return err
+			}
+			if owned > 0 {
+				return ErrItemAlreadyOwned
+			}
+		case item.Kind == models.ShopItemStreakFreeze:
+			if user.StreakFreezes+quantity > maxFreezes {
+				return ErrFreezeLimitReached
+			}
+			freezes = quantity
+		}
+
+		cost := item.Price * quantity
+		if user.Points < cost {
+			return ErrInsufficientPoints
+		}
+		user.Points -= cost
+		user.StreakFreezes += freezes
+		if err := tx.Model(&user).UpdateColumns(map[string]any{
+			"points":         user.Points,
+			"streak_freezes": user.StreakFreezes,
+		}).Error; err != nil {
+			return err
+		}
+
+		if freezes == 0 {
+			err := tx.Clauses(clause.OnConflict{
+				Columns: []clause.Column{{Name: "user_id"}, {Name: "item_id"}},
+				DoUpdates: clause.Assignments(map[string]any{
+					"quantity":   gorm.Expr("user_items.quantity + excluded.quantity"),
+					"updated_at": time.Now(),
+				}),
+			}).Create(&models.UserItem{UserID: userID, ItemID: itemID, Quantity: quantity}).Error
+			if err != nil {
+				return err
+			}
+		}
+		return recordPointTransaction(tx, userID, models.PointTransactionShopPurchase, -cost, freezes, &itemID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// SetEquipped equips or unequips one of the user's cosmetics. Equipping replaces the item of
+// the same kind that was equipped before.
+func (sr *ShopRepository) SetEquipped(userID, itemID uint, equipped bool) error {
+	err := sr.db.Transaction(func(tx *gorm.DB) error {
+		var owned models.UserItem
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Preload("Item").
+			Where("user_id = ? AND item_id = ? AND quantity > 0", userID, itemID).
+			First(&owned).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrItemNotOwned
+			}
+			return err
+		}
+		column, ok := equipColumns[owned.Item.Kind]
+		if !ok {
+			return ErrItemNotEquippable
+		}
+
+		if !equipped {
+			if !owned.Equipped {
+				return nil
+			}
+			if err := tx.Model(&owned).UpdateColumn("equipped", false).Error; err != nil {
+				return err
+			}
+			return tx.Model(&models.User{}).Where("id = ?", userID).UpdateColumn(column, "").Error
+		}
+
+		err = tx.Model(&models.UserItem{}).
+			Where("user_id = ? AND equipped AND item_id IN (?)", userID, tx.Model(&models.ShopItem{}).Select("id").Where("kind = ?", owned.Item.Kind)).
+			UpdateColumn("equipped", false).Error
+		if err != nil {
+			return err
+		}
+		if err := tx.Model(&owned).UpdateColumn("equipped", true).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.User{}).Where("id = ?", userID).UpdateColumn(column, owned.Item.AssetURL).Error
+	})
+	if err != nil {
+		if !errors.Is(err, ErrItemNotOwned) && !errors.Is(err, ErrItemNotEquippable) {
+			sr.logger.Error("Failed to change equipped item", "err", err, "userID", userID, "itemID", itemID)
+		}
+		return err
+	}
+	return nil
+}
+
+// GetHintQuestion returns the question a hint is asked for.
+func (sr *ShopRepository) GetHintQuestion(questionID uint) (*models.TaskQuestion, error) {
+	var question models.TaskQuestion
+	if err := sr.db.Where("task_id IN (?)", activeTaskIDs(sr.db)).First(&question, questionID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrQuestionNotFound
+		}
+		return nil, err
+	}
+	return &question, nil
+}
+
+// UnlockHint spends one of the user's hint tokens on the question. A question whose hint the
+// user already unlocked costs nothing.
+func (sr *ShopRepository) UnlockHint(userID, questionID uint) error {
+	err := sr.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&models.HintUnlock{UserID: userID, TaskQuestionID: questionID})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		var tokens []models.UserItem
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Table: clause.Table{Name: "user_items"}}).
+			Joins("JOIN shop_items ON shop_items.id = user_items.item_id").
+			Where("user_items.user_id = ? AND user_items.quantity > 0 AND shop_items.kind = ?", userID, models.ShopItemHintToken).
+			Order("user_items.id ASC").
+			Limit(1).
+			Find(&tokens).Error
+		if err != nil {
+			return err
+		}
+		if len(tokens) == 0 {
+			return ErrNoHintTokens
+		}
+		return tx.Model(&tokens[0]).UpdateColumn("quantity", gorm.Expr("quantity - 1")).Error
+	})
+	if err != nil && !errors.Is(err, ErrNoHintTokens) {
+		sr.logger.Error("Failed to unlock hint", "err", err, "userID", userID, "questionID", questionID)
+	}
+	return err
+}
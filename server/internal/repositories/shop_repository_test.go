@@ -0,0 +1,192 @@
+package repositories
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+
+	"github.com/Suplice/CodeQuest/internal/models"
+	"github.com/Suplice/CodeQuest/internal/testdb"
+	"gorm.io/gorm"
+)
+
+// concurrently runs fn from workers goroutines at once and returns their errors.
+func concurrently(workers int, fn func(i int) error) []error {
+	var (
+		wg    sync.WaitGroup
+		start = make(chan struct{})
+		errs  = make([]error, workers)
+	)
+	for i := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			errs[i] = fn(i)
+		}()
+	}
+	close(start)
+	wg.Wait()
+	return errs
+}
+
+// countErrors returns how many errs are nil and fails the test on errors other than allowed.
+func countErrors(t *testing.T, errs []error, allowed error) int {
+	t.Helper()
+	succeeded := 0
+	for _, err := range errs {
+		switch {
+		case err == nil:
+			succeeded++
+		case !errors.Is(err, allowed):
+			t.Errorf("unexpected error %v", err)
+		}
+	}
+	return succeeded
+}
+
+func createShopItem(t *testing.T, db *gorm.DB, kind string, price int) *models.ShopItem {
+	t.Helper()
+	item := &models.ShopItem{Slug: kind, Name: kind, Kind: kind, Price: price, IsActive: true}
+	if err := db.Create(item).Error; err != nil {
+		t.Fatalf("create %s item: %v", kind, err)
+	}
+	return item
+}
+
+func TestConcurrentPurchasesDoNotOverdraw(t *testing.T) {
+	db := testdb.Open(t)
+	repo := NewShopRepository(db, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	item := createShopItem(t, db, models.ShopItemHintToken, 30)
+	user := testdb.CreateUser(t, db, "shopper")
+	if err := db.Model(user).UpdateColumn("points", 100).Error; err != nil {
+		t.Fatalf("give points: %v", err)
+	}
+
+	errs := concurrently(8, func(int) error {
+		_, err := repo.Purchase(user.ID, item.ID, 1, 0)
+		return err
+	})
+	if bought := countErrors(t, errs, ErrInsufficientPoints); bought != 3 {
+		t.Errorf("%d purchases succeeded, want 3", bought)
+	}
+
+	var got models.User
+	if err := db.First(&got, user.ID).Error; err != nil {
+		t.Fatalf("reload user: %v", err)
+	}
+	if got.Points != 10 {
+		t.Errorf("user has %d points, want 10", got.Points)
+	}
+	var owned models.UserItem
+	if err := db.Where("user_id = ? AND item_id = ?", user.ID, item.ID).First(&owned).Error; err != nil {
+		t.Fatalf("load inventory: %v", err)
+	}
+	if owned.Quantity != 3 {
+		t.Errorf("user owns %d tokens, want 3", owned.Quantity)
+	}
+	var purchases int64
+	if err := db.Model(&models.PointTransaction{}).
+		Where("user_id = ? AND kind = ?", user.ID, models.PointTransactionShopPurchase).
+		Count(&purchases).Error; err != nil {
+		t.Fatalf("count purchases: %v", err)
+	}
+	if purchases != 3 {
+		t.Errorf("%d purchase ledger rows, want 3", purchases)
+	}
+}
+
+func TestConcurrentFreezePurchasesStayWithinLimit(t *testing.T) {
+	db := testdb.Open(t)
+	repo := NewShopRepository(db, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	item := createShopItem(t, db, models.ShopItemStreakFreeze, 10)
+	user := testdb.CreateUser(t, db, "freezer")
+	if err := db.Model(user).UpdateColumn("points", 1000).Error; err != nil {
+		t.Fatalf("give points: %v", err)
+	}
+
+	errs := concurrently(6, func(int) error {
+		_, err := repo.Purchase(user.ID, item.ID, 1, 2)
+		return err
+	})
+	if bought := countErrors(t, errs, ErrFreezeLimitReached); bought != 2 {
+		t.Errorf("%d purchases succeeded, want 2", bought)
+	}
+
+	var got models.User
+	if err := db.First(&got, user.ID).Error; err != nil {
+		t.Fatalf("reload user: %v", err)
+	}
+	if got.StreakFreezes != 2 || got.Points != 980 {
+		t.Errorf("user has %d freezes and %d points, want 2 and 980", got.StreakFreezes, got.Points)
+	}
+}
+
+func TestConcurrentHintUnlocksSpendOneTokenPerQuestion(t *testing.T) {
+	db := testdb.Open(t)
+	repo := NewShopRepository(db, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	task := &models.Task{
+		Title:      "Hints",
+		Type:       models.TaskTypeFillBlank,
+		Language:   "go",
+		Difficulty: models.DifficultyEasy,
+		IsActive:   true,
+		TaskQuestions: []models.TaskQuestion{
+			{QuestionText: "q1 ___", Type: models.TaskTypeFillBlank, CorrectAnswer: "a", Position: 1},
+			{QuestionText: "q2 ___", Type: models.TaskTypeFillBlank, CorrectAnswer: "b", Position: 2},
+			{QuestionText: "q3 ___", Type: models.TaskTypeFillBlank, CorrectAnswer: "c", Position: 3},
+		},
+	}
+	if err := db.Create(task).Error; err != nil {
+		t.Fatalf("create task: %v", err)
+	}
+	item := createShopItem(t, db, models.ShopItemHintToken, 30)
+	user := testdb.CreateUser(t, db, "hinter")
+	if err := db.Create(&models.UserItem{UserID: user.ID, ItemID: item.ID, Quantity: 2}).Error; err != nil {
+		t.Fatalf("give tokens: %v", err)
+	}
+	tokensLeft := func() int {
+		t.Helper()
+		var owned models.UserItem
+		if err := db.Where("user_id = ? AND item_id = ?", user.ID, item.ID).First(&owned).Error; err != nil {
+			t.Fatalf("load tokens: %v", err)
+		}
+		return owned.Quantity
+	}
+
+	first := task.TaskQuestions[0].ID
+	errs := concurrently(8, func(int) error {
+		return repo.UnlockHint(user.ID, first)
+	})
+	if unlocked := countErrors(t, errs, nil); unlocked != 8 {
+		t.Errorf("%d of 8 unlocks of one question succeeded", unlocked)
+	}
+	if left := tokensLeft(); left != 1 {
+		t.Errorf("%d tokens left after unlocking one question, want 1", left)
+	}
+
+	// One token is left for two questions.
+	others := []uint{task.TaskQuestions[1].ID, task.TaskQuestions[2].ID}
+	errs = concurrently(2, func(i int) error {
+		return repo.UnlockHint(user.ID, others[i])
+	})
+	if unlocked := countErrors(t, errs, ErrNoHintTokens); unlocked != 1 {
+		t.Errorf("%d of 2 unlocks succeeded with one token, want 1", unlocked)
+	}
+	if left := tokensLeft(); left != 0 {
+		t.Errorf("%d tokens left, want 0", left)
+	}
+
+	var unlocks int64
+	if err := db.Model(&models.HintUnlock{}).Where("user_id = ?", user.ID).Count(&unlocks).Error; err != nil {
+		t.Fatalf("count unlocks: %v", err)
+	}
+	if unlocks != 2 {
+		t.Errorf("%d hints unlocked, want 2", unlocks)
+	}
+}
@@ -0,0 +1,225 @@
+package repositories
+
+import (
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/Suplice/CodeQuest/internal/models"
+	"github.com/Suplice/CodeQuest/internal/streak"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrFreezeLimitReached = errors.New("streak freeze limit reached")
+	ErrNoBrokenStreak     = errors.New("no broken streak to repair")
+	ErrRepairWindowPassed = errors.New("streak repair window has passed")
+)
+
+type StreakRepository struct {
+	db     *gorm.DB
+	logger *slog.Logger
+}
+
+func NewStreakRepository(_db *gorm.DB, _logger *slog.Logger) *StreakRepository {
+	return &StreakRepository{db: _db, logger: _logger}
+}
+
+// recordStreakEnd stores the user's current streak in the history before it is reset.
+func recordStreakEnd(tx *gorm.DB, user *models.User, loc *time.Location) error {
+	if user.StreakCount <= 0 || user.LastActiveDate.IsZero() {
+		return nil
+	}
+	endedOn := streak.Day(user.LastActiveDate, loc)
+	return tx.Create(&models.StreakHistory{
+		UserID:    user.ID,
+		Length:    user.StreakCount,
+		StartedOn: endedOn.AddDate(0, 0, -(user.StreakCount - 1)),
+		EndedOn:   endedOn,
+		BrokenAt:  endedOn.AddDate(0, 0, 2),
+	}).Error
+}
+
+// coverMissedDays spends one freeze per day the user missed before now to keep a lapsed
+// streak alive. The missed days are covered by moving LastActiveDate to yesterday, so activity
+// today continues the streak. It changes nothing and returns false when the freezes do not
+// cover every missed day or the user changed since being loaded.
+func coverMissedDays(tx *gorm.DB, user *models.User, now time.Time, loc *time.Location) (bool, error) {
+	missed := streak.MissedDays(user.LastActiveDate, now, loc)
+	if missed == 0 || user.StreakFreezes < missed {
+		return false, nil
+	}
+
+	coveredUntil := streak.Day(now, loc).AddDate(0, 0, -1)
+	result := tx.Model(&models.User{}).
+		Where("id = ? AND streak_count = ? AND last_active_date = ? AND streak_freezes >= ?", user.ID, user.StreakCount, user.LastActiveDate, missed).
+		UpdateColumns(map[string]interface{}{
+			"streak_freezes":   gorm.Expr("streak_freezes - ?", missed),
+			"last_active_date": coveredUntil,
+		})
+	if result.Error != nil || result.RowsAffected == 0 {
+		return false, result.Error
+	}
+
+	user.StreakFreezes -= missed
+	user.LastActiveDate = coveredUntil
+	return true, recordPointTransaction(tx, user.ID, models.PointTransactionFreezeUsed, 0, -missed, nil)
+}
+
+// GetStreakCandidates returns users with a running streak who have not been active since
+// before. Whether the streak actually lapsed depends on each user's timezone.
+func (sr *StreakRepository) GetStreakCandidates(before time.Time) ([]models.User, error) {
+	var users []models.User
+	err := sr.db.
+		Select("id", "timezone", "streak_count", "longest_streak", "streak_freezes", "last_active_date").
+		Where("streak_count > 0 AND last_active_date < ?", before).
+		Order("id ASC").
+		Find(&users).Error
+	if err != nil {
+		sr.logger.Error("Failed to get streak candidates", "err", err)
+		return nil, err
+	}
+	return users, nil
+}
+
+// ResetStreak ends the user's streak and records it in the history. It does nothing and
+// returns false when the streak changed since user was loaded, so concurrent runs and a task
+// completed in the meantime are safe.
+func (sr *StreakRepository) ResetStreak(user models.User, loc *time.Location) (bool, error) {
+	reset := false
+	err := sr.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.User{}).
+			Where("id = ? AND streak_count = ? AND last_active_date = ?", user.ID, user.StreakCount, user.LastActiveDate).
+			UpdateColumn("streak_count", 0)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		reset = true
+		return recordStreakEnd(tx, &user, loc)
+	})
+	if err != nil {
+		sr.logger.Error("Failed to reset streak", "err", err, "userID", user.ID)
+		return false, err
+	}
+	return reset, nil
+}
+
+// FreezeStreak spends the user's freezes on the days missed before now to keep the streak
+// alive. Like ResetStreak it returns false when the user changed since being loaded, and also
+// when the freezes do not cover every missed day.
+func (sr *StreakRepository) FreezeStreak(user models.User, now time.Time, loc *time.Location) (bool, error) {
+	var frozen bool
+	err := sr.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		frozen, err = coverMissedDays(tx, &user, now, loc)
+		return err
+	})
+	if err != nil {
+		sr.logger.Error("Failed to freeze streak", "err", err, "userID", user.ID)
+		return false, err
+	}
+	return frozen, nil
+}
+
+// GetRepairableStreak returns the user's most recent broken streak if it has not been repaired
+// and broke after since, or nil.
+func (sr *StreakRepository) GetRepairableStreak(userID uint, since time.Time) (*models.StreakHistory, error) {
+	var history models.StreakHistory
+	err := sr.db.Where("user_id = ?", userID).Order("id DESC").Take(&history).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if history.RepairedAt != nil || history.BrokenAt.Before(since) {
+		return nil, nil
+	}
+	return &history, nil
+}
+
+// RepairStreak restores the most recent broken streak for price points, provided it broke
+// after since. Days of a streak restarted in the meantime are added on top.
+func (sr *StreakRepository) RepairStreak(userID uint, price int, since, now time.Time) (*models.User, error) {
+	var user models.User
+	err := sr.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id", "points", "timezone", "streak_count", "longest_streak", "streak_freezes", "last_active_date").
+			First(&user, userID).Error; err != nil {
+			return err
+		}
+
+		var history models.StreakHistory
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("user_id = ?", userID).Order("id DESC").Take(&history).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && history.RepairedAt != nil) {
+			return ErrNoBrokenStreak
+		}
+		if err != nil {
+			return err
+		}
+		if history.BrokenAt.Before(since) {
+			return ErrRepairWindowPassed
+		}
+		if user.Points < price {
+			return ErrInsufficientPoints
+		}
+
+		if user.StreakCount == 0 {
+			// Nothing was done since the streak broke: continue it as if the user was
+			// active yesterday.
+			user.LastActiveDate = streak.Day(now, streak.Location(user.Timezone)).AddDate(0, 0, -1)
+		}
+		user.StreakCount += history.Length
+		user.LongestStreak = max(user.LongestStreak, user.StreakCount)
+		user.Points -= price
+
+		if err := tx.Model(&user).UpdateColumns(map[string]interface{}{
+			"points":           user.Points,
+			"streak_count":     user.StreakCount,
+			"longest_streak":   user.LongestStreak,
+			"last_active_date": user.LastActiveDate,
+		}).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&history).UpdateColumn("repaired_at", now).Error; err != nil {
+			return err
+		}
+		return recordPointTransaction(tx, user.ID, models.PointTransactionStreakRepair, -price, 0, &history.ID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (sr *StreakRepository) GetUser(userID uint) (*models.User, error) {
+	var user models.User
+	if err := sr.db.Select("id", "points", "timezone", "streak_count", "longest_streak", "streak_freezes", "last_active_date").First(&user, userID).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (sr *StreakRepository) GetHistory(userID uint, limit int) ([]models.StreakHistory, error) {
+	var history []models.StreakHistory
+	err := sr.db.Where("user_id = ?", userID).Order("ended_on DESC, id DESC").Limit(limit).Find(&history).Error
+	if err != nil {
+		sr.logger.Error("Failed to get streak history", "err", err, "userID", userID)
+		return nil, err
+	}
+	return history, nil
+}
+
+func (sr *StreakRepository) SetTimezone(userID uint, timezone string) error {
+	result := sr.db.Model(&models.User{}).Where("id = ?", userID).UpdateColumn("timezone", timezone)
+	if result.Error != nil {
+		sr.logger.Error("Failed to update timezone", "err", result.Error, "userID", userID)
+		return result.Error
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+package repositories
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/Suplice/CodeQuest/internal/models"
+	"github.com/Suplice/CodeQuest/internal/streak"
+	"github.com/Suplice/CodeQuest/internal/testdb"
+)
+
+func TestFreezeStreakCoversEveryMissedDay(t *testing.T) {
+	db := testdb.Open(t)
+	repo := NewStreakRepository(db, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	loc := streak.Location("America/New_York")
+	now := time.Date(2026, 3, 10, 9, 0, 0, 0, loc)
+	yesterday := time.Date(2026, 3, 9, 0, 0, 0, 0, loc)
+
+	tests := []struct {
+		name       string
+		lastActive time.Time
+		freezes    int
+		frozen     bool
+		left       int
+	}{
+		{"active yesterday", time.Date(2026, 3, 9, 20, 0, 0, 0, loc), 2, false, 2},
+		{"one day missed", time.Date(2026, 3, 8, 20, 0, 0, 0, loc), 1, true, 0},
+		{"two days missed over spring forward", time.Date(2026, 3, 7, 20, 0, 0, 0, loc), 2, true, 0},
+		{"more days missed than freezes", time.Date(2026, 3, 7, 20, 0, 0, 0, loc), 1, false, 1},
+	}
+	for i, tt := range tests {
+		user := testdb.CreateUser(t, db, "freezer"+string(rune('a'+i)))
+		user.Timezone = loc.String()
+		user.StreakCount = 5
+		user.StreakFreezes = tt.freezes
+		user.LastActiveDate = tt.lastActive
+		if err := db.Save(user).Error; err != nil {
+			t.Fatalf("%s: save user: %v", tt.name, err)
+		}
+
+		frozen, err := repo.FreezeStreak(*user, now, loc)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if frozen != tt.frozen {
+			t.Errorf("%s: frozen %v, want %v", tt.name, frozen, tt.frozen)
+		}
+
+		got, err := repo.GetUser(user.ID)
+		if err != nil {
+			t.Fatalf("%s: reload user: %v", tt.name, err)
+		}
+		if got.StreakFreezes != tt.left || got.StreakCount != 5 {
+			t.Errorf("%s: %d freezes and a %d day streak left, want %d and 5", tt.name, got.StreakFreezes, got.StreakCount, tt.left)
+		}
+		if tt.frozen && !got.LastActiveDate.Equal(yesterday) {
+			t.Errorf("%s: last active %v, want %v", tt.name, got.LastActiveDate, yesterday)
+		}
+		if !tt.frozen && !got.LastActiveDate.Equal(tt.lastActive) {
+			t.Errorf("%s: last active moved to %v", tt.name, got.LastActiveDate)
+		}
+	}
+}
+
+func TestResetStreakRecordsWhenTheStreakBroke(t *testing.T) {
+	db := testdb.Open(t)
+	repo := NewStreakRepository(db, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	// The streak ends on Friday evening in New York, which is already Saturday in UTC. It
+	// breaks at the end of Saturday, local midnight on the Sunday clocks spring forward.
+	loc := streak.Location("America/New_York")
+	user := testdb.CreateUser(t, db, "breaker")
+	user.Timezone = loc.String()
+	user.StreakCount = 3
+	user.LastActiveDate = time.Date(2026, 3, 6, 22, 0, 0, 0, loc)
+	if err := db.Save(user).Error; err != nil {
+		t.Fatalf("save user: %v", err)
+	}
+
+	reset, err := repo.ResetStreak(*user, loc)
+	if err != nil || !reset {
+		t.Fatalf("ResetStreak = %v, %v", reset, err)
+	}
+
+	var history models.StreakHistory
+	if err := db.Where("user_id = ?", user.ID).Take(&history).Error; err != nil {
+		t.Fatalf("load history: %v", err)
+	}
+	if history.Length != 3 {
+		t.Errorf("length %d, want 3", history.Length)
+	}
+	if got := history.StartedOn.Format(time.DateOnly); got != "2026-03-04" {
+		t.Errorf("started on %s, want 2026-03-04", got)
+	}
+	if got := history.EndedOn.Format(time.DateOnly); got != "2026-03-06" {
+		t.Errorf("ended on %s, want 2026-03-06", got)
+	}
+	if want := time.Date(2026, 3, 8, 0, 0, 0, 0, loc); !history.BrokenAt.Equal(want) {
+		t.Errorf("broke at %v, want %v", history.BrokenAt, want)
+	}
+
+	if reset, err := repo.ResetStreak(*user, loc); err != nil || reset {
+		t.Errorf("second ResetStreak = %v, %v, want nothing to reset", reset, err)
+	}
+}
+
+func TestRepairStreakWindow(t *testing.T) {
+	db := testdb.Open(t)
+	repo := NewStreakRepository(db, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	now := time.Date(2026, 3, 12, 15, 0, 0, 0, time.UTC)
+	since := now.Add(-streak.RepairWindow)
+	repairedAt := now.Add(-time.Hour)
+
+	tests := []struct {
+		name       string
+		brokenAt   time.Time
+		repairedAt *time.Time
+		points     int
+		current    int
+		wantErr    error
+		wantStreak int
+	}{
+		{"broke within the window", now.Add(-23 * time.Hour), nil, streak.RepairPrice, 0, nil, 4},
+		{"broke as the window opened", since, nil, streak.RepairPrice, 0, nil, 4},
+		{"streak restarted since", now.Add(-12 * time.Hour), nil, streak.RepairPrice, 1, nil, 5},
+		{"window passed", since.Add(-time.Second), nil, streak.RepairPrice, 0, ErrRepairWindowPassed, 0},
+		{"already repaired", now.Add(-2 * time.Hour), &repairedAt, streak.RepairPrice, 0, ErrNoBrokenStreak, 0},
+		{"not enough points", now.Add(-2 * time.Hour), nil, streak.RepairPrice - 1, 0, ErrInsufficientPoints, 0},
+	}
+	for i, tt := range tests {
+		user := testdb.CreateUser(t, db, "repairer"+string(rune('a'+i)))
+		user.Points = tt.points
+		user.StreakCount = tt.current
+		user.LastActiveDate = now.Add(-time.Hour)
+		if err := db.Save(user).Error; err != nil {
+			t.Fatalf("%s: save user: %v", tt.name, err)
+		}
+		endedOn := streak.Day(tt.brokenAt, time.UTC).AddDate(0, 0, -2)
+		if err := db.Create(&models.StreakHistory{
+			UserID:     user.ID,
+			Length:     4,
+			StartedOn:  endedOn.AddDate(0, 0, -3),
+			EndedOn:    endedOn,
+			BrokenAt:   tt.brokenAt,
+			RepairedAt: tt.repairedAt,
+		}).Error; err != nil {
+			t.Fatalf("%s: create history: %v", tt.name, err)
+		}
+
+		repaired, err := repo.RepairStreak(user.ID, streak.RepairPrice, since, now)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("%s: error %v, want %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if repaired.StreakCount != tt.wantStreak || repaired.Points != tt.points-streak.RepairPrice {
+			t.Errorf("%s: %d day streak and %d points, want %d and %d", tt.name, repaired.StreakCount, repaired.Points, tt.wantStreak, tt.points-streak.RepairPrice)
+		}
+		if _, err := repo.RepairStreak(user.ID, streak.RepairPrice, since, now); !errors.Is(err, ErrNoBrokenStreak) {
+			t.Errorf("%s: second repair error %v, want %v", tt.name, err, ErrNoBrokenStreak)
+		}
+	}
+}
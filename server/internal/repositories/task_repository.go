@@ -5,8 +5,17 @@ import (
 	"log/slog"
 	"time"
 
+	"github.com/Suplice/CodeQuest/internal/dto"
 	"github.com/Suplice/CodeQuest/internal/models"
+	"github.com/Suplice/CodeQuest/internal/streak"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrQuestionNotFound     = errors.New("question not found")
+	ErrTaskAlreadyCompleted = errors.New("task already completed")
+	ErrTaskInDuel           = errors.New("task is part of an open duel")
 )
 
 type TaskForUser struct {
@@ -21,7 +30,7 @@ type TaskForUser struct {
 	IsActive      bool                  `json:"is_active"`
 	CreatedAt     time.Time             `json:"created_at"`
 	UpdatedAt     time.Time             `json:"updated_at"`
-	TaskQuestions []models.TaskQuestion        `json:"task_questions"`
+	TaskQuestions []dto.TaskQuestionDTO        `json:"task_questions"`
 	UserProgress  *models.UserTaskProgress     `json:"user_progress"` 
 }
 
@@ -36,13 +45,19 @@ func NewTaskRepository(_db *gorm.DB, _logger *slog.Logger) *TaskRepository {
 
 func (tr *TaskRepository) GetAllTasksForUserDTO(userID uint64) ([]TaskForUser, error) {
 	var tasks []models.Task
-	err := tr.db.Preload("TaskQuestions").
+	err := tr.db.Preload("TaskQuestions", orderQuestions).
 		Preload("UserProgress", "user_id = ?", userID).
+		Where("is_active = ?", true).
 		Find(&tasks).Error
 	if err != nil {
 		return nil, err
 	}
 
+	answered, err := tr.getCorrectlyAnsweredQuestionIDs(uint(userID))
+	if err != nil {
+		return nil, err
+	}
+
 	result := make([]TaskForUser, len(tasks))
 	for i, t := range tasks {
 		var progress *models.UserTaskProgress
@@ -61,7 +76,7 @@ func (tr *TaskRepository) GetAllTasksForUserDTO(userID uint64) ([]TaskForUser, e
 			IsActive:      t.IsActive,
 			CreatedAt:     t.CreatedAt,
 			UpdatedAt:     t.UpdatedAt,
-			TaskQuestions: t.TaskQuestions,
+			TaskQuestions: toPublicQuestions(t.TaskQuestions, progress, answered),
 			UserProgress:  progress,
 		}
 	}
@@ -72,9 +87,10 @@ func (tr *TaskRepository) GetTaskForUserDTO(taskID uint, userID uint) (*TaskForU
 	var task models.Task
 
 	err := tr.db.
-		Preload("TaskQuestions").
+		Preload("TaskQuestions", orderQuestions).
 		Preload("UserProgress", "user_id = ?", userID).
 		Preload("UserProgress.Answers"). 
+		Where("is_active = ?", true).
 		First(&task, taskID).Error
 
 	if err != nil {
@@ -85,8 +101,14 @@ func (tr *TaskRepository) GetTaskForUserDTO(taskID uint, userID uint) (*TaskForU
 	}
 
 	var progress *models.UserTaskProgress
+	answered := make(map[uint]bool)
 	if len(task.UserProgress) > 0 {
 		progress = &task.UserProgress[0]
+		for _, a := range progress.Answers {
+			if a.IsCorrect {
+				answered[a.TaskQuestionID] = true
+			}
+		}
 	}
 
 	result := &TaskForUser{
@@ -101,39 +123,153 @@ func (tr *TaskRepository) GetTaskForUserDTO(taskID uint, userID uint) (*TaskForU
 		IsActive:      task.IsActive,
 		CreatedAt:     task.CreatedAt,
 		UpdatedAt:     task.UpdatedAt,
-		TaskQuestions: task.TaskQuestions,
+		TaskQuestions: toPublicQuestions(task.TaskQuestions, progress, answered),
 		UserProgress:  progress, 
 	}
 
 	return result, nil
 }
 
-func (tr *TaskRepository) GetCorrectAnswer(questionID uint) (string, error) {
+func (tr *TaskRepository) getCorrectlyAnsweredQuestionIDs(userID uint) (map[uint]bool, error) {
+	var questionIDs []uint
+	err := tr.db.Model(&models.UserAnswer{}).
+		Joins("JOIN user_task_progresses utp ON utp.id = user_answers.user_task_progress_id").
+		Where("utp.user_id = ? AND user_answers.is_correct = ?", userID, true).
+		Distinct().
+		Pluck("user_answers.task_question_id", &questionIDs).Error
+	if err != nil {
+		tr.logger.Error("Failed to get correctly answered questions", "err", err, "userID", userID)
+		return nil, err
+	}
+
+	answered := make(map[uint]bool, len(questionIDs))
+	for _, id := range questionIDs {
+		answered[id] = true
+	}
+	return answered, nil
+}
+
+func orderQuestions(db *gorm.DB) *gorm.DB {
+	return db.Order("position ASC, id ASC")
+}
+
+// activeTaskIDs selects the tasks students can see and answer. Deactivated tasks stay in the
+// database for the progress already made on them.
+func activeTaskIDs(db *gorm.DB) *gorm.DB {
+	return db.Model(&models.Task{}).Select("id").Where("is_active = ?", true)
+}
+
+func toPublicQuestions(questions []models.TaskQuestion, progress *models.UserTaskProgress, answered map[uint]bool) []dto.TaskQuestionDTO {
+	taskCompleted := progress != nil && progress.IsCompleted
+
+	result := make([]dto.TaskQuestionDTO, len(questions))
+	for i, q := range questions {
+		result[i] = dto.TaskQuestionDTO{
+			ID:           q.ID,
+			TaskID:       q.TaskID,
+			QuestionText: q.QuestionText,
+			Type:         q.Type,
+			Options:      q.Options,
+		}
+
+		if taskCompleted || answered[q.ID] {
+			correctAnswer, testInput, expectedOutput, explanation := q.CorrectAnswer, q.TestInput, q.ExpectedOutput, q.Explanation
+			result[i].IsRevealed = true
+			result[i].CorrectAnswer = &correctAnswer
+			result[i].TestInput = &testInput
+			result[i].ExpectedOutput = &expectedOutput
+			result[i].Explanation = &explanation
+		}
+	}
+	return result
+}
+
+func (tr *TaskRepository) GetQuestionForTask(taskID, questionID uint) (*models.TaskQuestion, error) {
 	var question models.TaskQuestion
-	err := tr.db.Select("correct_answer").First(&question, questionID).Error
+	err := tr.db.Preload("Task").
+		Where("id = ? AND task_id = ?", questionID, taskID).
+		Where("task_id IN (?)", activeTaskIDs(tr.db)).
+		First(&question).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return "", errors.New("question not found")
+			return nil, ErrQuestionNotFound
 		}
-		return "", err
+		return nil, err
+	}
+	return &question, nil
+}
+
+// lockProgress returns the user's progress row for the task, creating it if needed, locked
+// FOR UPDATE. Parallel answers for the same task therefore run one after another, which keeps
+// completion and reward granting exactly-once.
+func (tr *TaskRepository) lockProgress(tx *gorm.DB, userID, taskID uint) (*models.UserTaskProgress, error) {
+	if err := tx.Clauses(clause.OnConflict{
+		Columns:     []clause.Column{{Name: "user_id"}, {Name: "task_id"}},
+		TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "deleted_at IS NULL"}}},
+		DoNothing:   true,
+	}).Create(&models.UserTaskProgress{UserID: userID, TaskID: taskID}).Error; err != nil {
+		return nil, err
+	}
+
+	var progress models.UserTaskProgress
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("user_id = ? AND task_id = ?", userID, taskID).
+		First(&progress).Error; err != nil {
+		return nil, err
 	}
-	return question.CorrectAnswer, nil
+	return &progress, nil
 }
 
-func (tr *TaskRepository) SaveAnswerAttempt(userID, taskID, questionID uint, answerGiven string, isCorrect bool) (bool, *models.User, error) {
-	var isCompleted bool = false
-	var updatedUser *models.User = nil
+// AnswerAttemptResult reports what an answer changed. User is only set when the answer
+// completed the task, with PreviousLevel and PreviousStreak holding the values before the
+// rewards.
+type AnswerAttemptResult struct {
+	// FirstCorrect is set when the answer is the first correct one to its question.
+	FirstCorrect   bool
+	IsCompleted    bool
+	User           *models.User
+	PreviousLevel  int
+	PreviousStreak int
+	// XP and Points are the rewards granted on completion, after multipliers.
+	XP     int
+	Points int
+}
+
+func (tr *TaskRepository) SaveAnswerAttempt(userID, taskID, questionID uint, answerGiven string, isCorrect bool) (*AnswerAttemptResult, error) {
+	result := &AnswerAttemptResult{}
 
 	err := tr.db.Transaction(func(tx *gorm.DB) error {
-		var progress models.UserTaskProgress
-		if err := tx.FirstOrCreate(&progress, models.UserTaskProgress{UserID: userID, TaskID: taskID}).Error; err != nil {
+		// Practising a task would give away the answers of a duel on it.
+		var openDuels int64
+		if err := tx.Model(&models.Duel{}).
+			Where("task_id = ? AND (challenger_id = ? OR opponent_id = ?)", taskID, userID, userID).
+			Where("status IN ? AND expires_at > ?", []string{models.DuelStatusPending, models.DuelStatusActive}, time.Now()).
+			Count(&openDuels).Error; err != nil {
+			return err
+		}
+		if openDuels > 0 {
+			return ErrTaskInDuel
+		}
+
+		progress, err := tr.lockProgress(tx, userID, taskID)
+		if err != nil {
 			tr.logger.Error("Failed to find or create user task progress", "err", err, "userID", userID, "taskID", taskID)
 			return err
 		}
 
 		if progress.IsCompleted {
 			tr.logger.Warn("Attempt to answer already completed task", "userID", userID, "taskID", taskID)
-			return errors.New("task already completed")
+			return ErrTaskAlreadyCompleted
+		}
+
+		if isCorrect {
+			var solved int64
+			if err := tx.Model(&models.UserAnswer{}).
+				Where("user_task_progress_id = ? AND task_question_id = ? AND is_correct = ?", progress.ID, questionID, true).
+				Count(&solved).Error; err != nil {
+				return err
+			}
+			result.FirstCorrect = solved == 0
 		}
 
 		answer := models.UserAnswer{
@@ -154,55 +290,53 @@ func (tr *TaskRepository) SaveAnswerAttempt(userID, taskID, questionID uint, ans
 		if !isCorrect {
 			updates["mistakes"] = gorm.Expr("mistakes + 1")
 		}
-		if err := tx.Model(&progress).Updates(updates).Error; err != nil {
+		if err := tx.Model(progress).Updates(updates).Error; err != nil {
 			tr.logger.Error("Failed to update user task progress stats", "err", err, "progressID", progress.ID)
 			return err
 		}
 
 		if isCorrect {
-			isNowComplete, userAfterRewards, err := tr.recalculateProgressAndGrantRewards(tx, &progress)
-			if err != nil {
+			if err := tr.recalculateProgressAndGrantRewards(tx, progress, result); err != nil {
 				tr.logger.Error("Failed to recalculate progress or grant rewards", "err", err, "progressID", progress.ID)
 				return err
 			}
-			isCompleted = isNowComplete
-			updatedUser = userAfterRewards 
 		}
 
 		return nil 
 	})
+	if err != nil {
+		return nil, err
+	}
 
-	return isCompleted, updatedUser, err
-}
-
-var xpThresholds = map[int]int{
-	1: 0,
-	2: 100,
-	3: 250,
-	4: 500,
-	5: 1000,
+	return result, nil
 }
 
-func (tr *TaskRepository) recalculateProgressAndGrantRewards(tx *gorm.DB, progress *models.UserTaskProgress) (bool, *models.User, error) {
+func (tr *TaskRepository) recalculateProgressAndGrantRewards(tx *gorm.DB, progress *models.UserTaskProgress, result *AnswerAttemptResult) error {
 	var totalQuestions int64
 	if err := tx.Model(&models.TaskQuestion{}).Where("task_id = ?", progress.TaskID).Count(&totalQuestions).Error; err != nil {
-		return false, nil, err
+		return err
 	}
 
+	// Count questions answered correctly at least once, ignoring repeated answers and
+	// questions that have since been deleted from the task.
 	var correctAnswers int64
-	if err := tx.Model(&models.UserAnswer{}).Where("user_task_progress_id = ? AND is_correct = ?", progress.ID, true).Count(&correctAnswers).Error; err != nil {
-		return false, nil, err
+	if err := tx.Model(&models.UserAnswer{}).
+		Joins("JOIN task_questions ON task_questions.id = user_answers.task_question_id AND task_questions.deleted_at IS NULL").
+		Where("user_answers.user_task_progress_id = ? AND user_answers.is_correct = ?", progress.ID, true).
+		Distinct("user_answers.task_question_id").
+		Count(&correctAnswers).Error; err != nil {
+		return err
 	}
 
 	newProgressPercent := 0.0
 	if totalQuestions > 0 {
 		newProgressPercent = (float64(correctAnswers) / float64(totalQuestions)) * 100.0
 	}
-	isNowComplete := (totalQuestions > 0 && correctAnswers == totalQuestions)
+	isNowComplete := (totalQuestions > 0 && correctAnswers >= totalQuestions)
 
 	if !isNowComplete {
 		err := tx.Model(progress).Update("progress", newProgressPercent).Error
-		return false, nil, err
+		return err
 	}
 
 	tr.logger.Info("Task completed!", "userID", progress.UserID, "taskID", progress.TaskID)
@@ -212,102 +346,79 @@ func (tr *TaskRepository) recalculateProgressAndGrantRewards(tx *gorm.DB, progre
 		"completed_at": &now,
 		"progress":     100.0,
 	}).Error; err != nil {
-		return false, nil, err
+		return err
 	}
 
 	var task models.Task
-	if err := tx.Select("xp", "points").First(&task, progress.TaskID).Error; err != nil {
-		return true, nil, err
+	if err := tx.Select("id", "xp", "points", "language", "difficulty", "type").First(&task, progress.TaskID).Error; err != nil {
+		return err
+	}
+	xpMultiplier, pointsMultiplier, err := activeMultipliers(tx, task, now)
+	if err != nil {
+		return err
 	}
+	xp := applyMultiplier(task.XP, xpMultiplier)
+	points := applyMultiplier(task.Points, pointsMultiplier)
 	var user models.User
-	if err := tx.First(&user, progress.UserID).Error; err != nil {
-		return true, nil, err
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&user, progress.UserID).Error; err != nil {
+		return err
 	}
 
-	todayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
-	if user.LastActiveDate.Before(todayStart) {
-		yesterdayStart := todayStart.AddDate(0, 0, -1)
-		if user.LastActiveDate.After(yesterdayStart) || user.LastActiveDate.Equal(yesterdayStart) {
+	// Streak days are counted in the user's timezone. A lapsed streak is normally frozen or
+	// reset by the streak job, but a completion can get there first.
+	result.PreviousStreak = user.StreakCount
+	loc := streak.Location(user.Timezone)
+	switch days := streak.DaysBetween(user.LastActiveDate, now, loc); {
+	case user.StreakCount == 0 || user.LastActiveDate.IsZero():
+		user.StreakCount = 1
+	case days == 1:
+		user.StreakCount++
+	case days > 1:
+		frozen, err := coverMissedDays(tx, &user, now, loc)
+		if err != nil {
+			return err
+		}
+		if frozen {
 			user.StreakCount++
-		} else {
-			user.StreakCount = 1 
+			break
 		}
-		user.LastActiveDate = now
-	}
-
-	user.XP += task.XP
-	user.Points += task.Points
-
-	currentLevel := user.Level
-	for {
-		nextLevel := currentLevel + 1
-		xpNeeded, exists := xpThresholds[nextLevel]
-		if !exists || user.XP < xpNeeded {
-			break 
+		if err := recordStreakEnd(tx, &user, loc); err != nil {
+			return err
 		}
-		currentLevel++ 
-	}
-	user.Level = currentLevel 
-
-	if err := tx.Save(&user).Error; err != nil {
-		return true, nil, err
+		user.StreakCount = 1
 	}
+	user.LastActiveDate = now
+	user.LongestStreak = max(user.LongestStreak, user.StreakCount)
 
-	go func(bgTx *gorm.DB, userID uint) {
-		if err := tr.checkAndAwardBadges(tr.db, userID); err != nil {
-			tr.logger.Error("Failed to check badges in background", "err", err, "userID", userID)
-		}
-	}(tx.Session(&gorm.Session{}), user.ID) 
+	user.XP += xp
+	user.Points += points
 
-	return true, &user, nil
-}
-
-func (tr *TaskRepository) checkAndAwardBadges(db *gorm.DB, userID uint) error {
-	var completedTasksCount int64
-	if err := db.Model(&models.UserTaskProgress{}).Where("user_id = ? AND is_completed = ?", userID, true).Count(&completedTasksCount).Error; err != nil {
+	curve, err := loadCurve(tx)
+	if err != nil {
 		return err
 	}
+	result.PreviousLevel = user.Level
+	user.Level = curve.Raise(user.Level, user.XP)
 
-	var userBadgeIDs []uint
-	if err := db.Model(&models.UserBadge{}).Where("user_id = ?", userID).Pluck("badge_id", &userBadgeIDs).Error; err != nil {
+	if err := tx.Save(&user).Error; err != nil {
 		return err
 	}
-
-	var badgesToCheck []models.Badge
-	if err := db.Not("id", userBadgeIDs).Find(&badgesToCheck).Error; err != nil {
+	if err := recordTaskReward(tx, user.ID, progress.TaskID, xp, points, xpMultiplier, pointsMultiplier); err != nil {
 		return err
 	}
 
-	for _, badge := range badgesToCheck {
-		earnedBadgeID := uint(0)
-		switch badge.Name {
-		case "Nowicjusz":
-			if completedTasksCount >= 1 {
-				earnedBadgeID = badge.ID
-			}
-		case "Uczeń":
-			if completedTasksCount >= 5 {
-				earnedBadgeID = badge.ID
-			}
-		}
-
-		if earnedBadgeID > 0 {
-			tr.logger.Info("Badge earned!", "userID", userID, "badgeName", badge.Name)
-			newUserBadge := models.UserBadge{UserID: userID, BadgeID: earnedBadgeID}
-			if err := db.FirstOrCreate(&newUserBadge, newUserBadge).Error; err != nil {
-				tr.logger.Error("Failed to award badge", "err", err, "userID", userID, "badgeID", earnedBadgeID)
-			}
-		}
-	}
+	curve.Annotate(&user)
+	result.IsCompleted = true
+	result.XP, result.Points = xp, points
+	result.User = &user
 	return nil
 }
 
-
 func (tr *TaskRepository) GetUnfinishedTasks(userID uint64) ([]TaskForUser, error) {
     var tasks []models.Task
     
     err := tr.db.
-        Preload("TaskQuestions").
+        Preload("TaskQuestions", orderQuestions).
         Where("id NOT IN (?)", tr.db.Table("user_task_progresses").Select("task_id").Where("user_id = ? AND is_completed = ?", userID, true)).
         Where("is_active = ?", true).
         Find(&tasks).Error
@@ -0,0 +1,212 @@
+package repositories
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Suplice/CodeQuest/internal/models"
+	"github.com/Suplice/CodeQuest/internal/testdb"
+)
+
+func TestToPublicQuestionsRedactsUnsolvedQuestions(t *testing.T) {
+	questions := []models.TaskQuestion{
+		{TaskID: 1, QuestionText: "first", Type: models.TaskTypeQuiz, CorrectAnswer: "a", Explanation: "because a"},
+		{TaskID: 1, QuestionText: "second", Type: models.TaskTypeCode, CorrectAnswer: "b", TestInput: "in", ExpectedOutput: "out"},
+	}
+	questions[0].ID, questions[1].ID = 10, 11
+
+	tests := []struct {
+		name     string
+		progress *models.UserTaskProgress
+		answered map[uint]bool
+		revealed []bool
+	}{
+		{name: "no progress", revealed: []bool{false, false}},
+		{name: "task started", progress: &models.UserTaskProgress{}, revealed: []bool{false, false}},
+		{name: "one question answered", progress: &models.UserTaskProgress{}, answered: map[uint]bool{10: true}, revealed: []bool{true, false}},
+		{name: "task completed", progress: &models.UserTaskProgress{IsCompleted: true}, revealed: []bool{true, true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			public := toPublicQuestions(questions, tt.progress, tt.answered)
+			if len(public) != len(questions) {
+				t.Fatalf("got %d questions, want %d", len(public), len(questions))
+			}
+			for i, q := range public {
+				if q.QuestionText != questions[i].QuestionText {
+					t.Errorf("question %d: text %q, want %q", i, q.QuestionText, questions[i].QuestionText)
+				}
+				if q.IsRevealed != tt.revealed[i] {
+					t.Errorf("question %d: revealed %v, want %v", i, q.IsRevealed, tt.revealed[i])
+				}
+				hidden := q.CorrectAnswer == nil && q.Explanation == nil && q.TestInput == nil && q.ExpectedOutput == nil
+				if hidden == tt.revealed[i] {
+					t.Errorf("question %d: answer fields hidden %v, want %v", i, hidden, !tt.revealed[i])
+				}
+				if q.IsRevealed && *q.CorrectAnswer != questions[i].CorrectAnswer {
+					t.Errorf("question %d: correct answer %q, want %q", i, *q.CorrectAnswer, questions[i].CorrectAnswer)
+				}
+			}
+		})
+	}
+}
+
+func TestSaveAnswerAttemptRewardsConcurrentCompletionOnce(t *testing.T) {
+	db := testdb.Open(t)
+	repo := NewTaskRepository(db, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	task := &models.Task{
+		Title:      "Race",
+		Type:       models.TaskTypeFillBlank,
+		Language:   "go",
+		Difficulty: models.DifficultyEasy,
+		Points:     10,
+		XP:         20,
+		IsActive:   true,
+		TaskQuestions: []models.TaskQuestion{
+			{QuestionText: "q ___", Type: models.TaskTypeFillBlank, CorrectAnswer: "a", Position: 1},
+		},
+	}
+	if err := db.Create(task).Error; err != nil {
+		t.Fatalf("create task: %v", err)
+	}
+	user := testdb.CreateUser(t, db, "racer")
+
+	const workers = 8
+	var (
+		wg        sync.WaitGroup
+		start     = make(chan struct{})
+		completed = make(chan bool, workers)
+	)
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			result, err := repo.SaveAnswerAttempt(user.ID, task.ID, task.TaskQuestions[0].ID, "a", true)
+			completed <- err == nil && result.IsCompleted
+		}()
+	}
+	close(start)
+	wg.Wait()
+	close(completed)
+
+	completions := 0
+	for ok := range completed {
+		if ok {
+			completions++
+		}
+	}
+	if completions != 1 {
+		t.Errorf("%d attempts completed the task, want 1", completions)
+	}
+
+	var rewards int64
+	if err := db.Model(&models.PointTransaction{}).
+		Where("user_id = ? AND kind = ?", user.ID, models.PointTransactionTaskReward).
+		Count(&rewards).Error; err != nil {
+		t.Fatalf("count rewards: %v", err)
+	}
+	if rewards != 1 {
+		t.Errorf("%d task reward ledger rows, want 1", rewards)
+	}
+
+	var got models.User
+	if err := db.First(&got, user.ID).Error; err != nil {
+		t.Fatalf("reload user: %v", err)
+	}
+	if got.XP != task.XP || got.Points != task.Points {
+		t.Errorf("user has %d XP and %d points, want %d and %d", got.XP, got.Points, task.XP, task.Points)
+	}
+}
+
+func TestSaveAnswerAttemptFlagsFirstCorrectAnswer(t *testing.T) {
+	db := testdb.Open(t)
+	repo := NewTaskRepository(db, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	task := &models.Task{
+		Title:      "Repeat",
+		Type:       models.TaskTypeFillBlank,
+		Language:   "go",
+		Difficulty: models.DifficultyEasy,
+		IsActive:   true,
+		TaskQuestions: []models.TaskQuestion{
+			{QuestionText: "q1 ___", Type: models.TaskTypeFillBlank, CorrectAnswer: "a", Position: 1},
+			{QuestionText: "q2 ___", Type: models.TaskTypeFillBlank, CorrectAnswer: "b", Position: 2},
+		},
+	}
+	if err := db.Create(task).Error; err != nil {
+		t.Fatalf("create task: %v", err)
+	}
+	user := testdb.CreateUser(t, db, "repeater")
+	question := task.TaskQuestions[0].ID
+
+	attempts := []struct {
+		correct bool
+		want    bool
+	}{{false, false}, {true, true}, {true, false}, {false, false}}
+	for i, a := range attempts {
+		result, err := repo.SaveAnswerAttempt(user.ID, task.ID, question, "answer", a.correct)
+		if err != nil {
+			t.Fatalf("attempt %d: %v", i, err)
+		}
+		if result.FirstCorrect != a.want {
+			t.Errorf("attempt %d: FirstCorrect = %v, want %v", i, result.FirstCorrect, a.want)
+		}
+	}
+}
+
+func TestSaveAnswerAttemptRejectsTaskInOpenDuel(t *testing.T) {
+	db := testdb.Open(t)
+	repo := NewTaskRepository(db, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	task := &models.Task{
+		Title:      "Dueled",
+		Type:       models.TaskTypeFillBlank,
+		Language:   "go",
+		Difficulty: models.DifficultyEasy,
+		IsActive:   true,
+		TaskQuestions: []models.TaskQuestion{
+			{QuestionText: "q ___", Type: models.TaskTypeFillBlank, CorrectAnswer: "a", Position: 1},
+		},
+	}
+	if err := db.Create(task).Error; err != nil {
+		t.Fatalf("create task: %v", err)
+	}
+	challenger := testdb.CreateUser(t, db, "challenger")
+	opponent := testdb.CreateUser(t, db, "opponent")
+	bystander := testdb.CreateUser(t, db, "bystander")
+
+	duel := &models.Duel{
+		ChallengerID: challenger.ID,
+		OpponentID:   opponent.ID,
+		TaskID:       task.ID,
+		Status:       models.DuelStatusActive,
+		ExpiresAt:    time.Now().Add(time.Hour),
+	}
+	if err := db.Create(duel).Error; err != nil {
+		t.Fatalf("create duel: %v", err)
+	}
+	question := task.TaskQuestions[0].ID
+
+	for _, user := range []*models.User{challenger, opponent} {
+		if _, err := repo.SaveAnswerAttempt(user.ID, task.ID, question, "a", true); !errors.Is(err, ErrTaskInDuel) {
+			t.Errorf("%s: err = %v, want ErrTaskInDuel", user.Username, err)
+		}
+	}
+	if _, err := repo.SaveAnswerAttempt(bystander.ID, task.ID, question, "a", true); err != nil {
+		t.Errorf("bystander: %v", err)
+	}
+
+	if err := db.Model(duel).Update("status", models.DuelStatusFinished).Error; err != nil {
+		t.Fatalf("finish duel: %v", err)
+	}
+	if _, err := repo.SaveAnswerAttempt(challenger.ID, task.ID, question, "a", true); err != nil {
+		t.Errorf("after the duel: %v", err)
+	}
+}
@@ -1,6 +1,7 @@
 package repositories
 
 import (
+	"errors"
 	"log/slog"
 
 	"github.com/Suplice/CodeQuest/internal/models"
@@ -8,6 +9,9 @@ import (
 	"gorm.io/gorm"
 )
 
+// ErrUserNotFound is returned when a user a request refers to does not exist.
+var ErrUserNotFound = errors.New("user not found")
+
 type UserRepository struct {
 	db *gorm.DB
 	logger *slog.Logger
@@ -0,0 +1,55 @@
+package routes
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/Suplice/CodeQuest/internal/models"
+	"github.com/Suplice/CodeQuest/internal/testdb"
+)
+
+func TestUpdateQuestionKeepsSlugWhenOmitted(t *testing.T) {
+	db := testdb.Open(t)
+	router := newTestRouter(db)
+
+	task := createTask(t, db)
+	question := task.TaskQuestions[0]
+	if err := db.Model(&question).Update("slug", "first-question").Error; err != nil {
+		t.Fatalf("set slug: %v", err)
+	}
+	admin := testdb.CreateUser(t, db, "admin")
+	if err := db.Model(admin).Update("role", "admin").Error; err != nil {
+		t.Fatalf("promote admin: %v", err)
+	}
+	cookie := testdb.AuthCookie(t, admin)
+	path := fmt.Sprintf("/admin/tasks/%d/questions/%d", task.ID, question.ID)
+
+	steps := []struct {
+		slug string
+		want string
+	}{
+		{"", "first-question"},
+		{"renamed-question", "renamed-question"},
+		{"", "renamed-question"},
+	}
+	for _, step := range steps {
+		code, body := doRequest(t, router, http.MethodPut, path, cookie, map[string]any{
+			"slug":           step.slug,
+			"question_text":  "q1 ___",
+			"type":           models.TaskTypeFillBlank,
+			"correct_answer": "secret-answer-one",
+		})
+		if code != http.StatusOK {
+			t.Fatalf("update with slug %q: status %d, body %s", step.slug, code, body)
+		}
+
+		var got models.TaskQuestion
+		if err := db.First(&got, question.ID).Error; err != nil {
+			t.Fatalf("reload question: %v", err)
+		}
+		if got.Slug != step.want {
+			t.Errorf("after update with slug %q: slug %q, want %q", step.slug, got.Slug, step.want)
+		}
+	}
+}
@@ -4,44 +4,104 @@ import (
 	"log/slog"
 
 	"github.com/Suplice/CodeQuest/internal/controllers"
+	"github.com/Suplice/CodeQuest/internal/events"
+	"github.com/Suplice/CodeQuest/internal/grading"
 	"github.com/Suplice/CodeQuest/internal/middleware"
+	"github.com/Suplice/CodeQuest/internal/ranking"
 	"github.com/Suplice/CodeQuest/internal/repositories"
+	"github.com/Suplice/CodeQuest/internal/sandbox"
 	"github.com/Suplice/CodeQuest/internal/services"
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 )
 
-func SetupRoutes(router *gin.Engine, db *gorm.DB, logger *slog.Logger) {
+// SetupRoutes wires the API. rankingStore caches the busiest leaderboards; with a nil store
+// every leaderboard is ranked in SQL.
+func SetupRoutes(router *gin.Engine, db *gorm.DB, logger *slog.Logger, rankingStore ranking.Store) {
 	authRepository := repositories.NewAuthRepository(db, logger)
 	userRepository := repositories.NewUserRepository(db, logger)
 	settingRepository := repositories.NewSettingRepository(db, logger)
 	taskRepository := repositories.NewTaskRepository(db, logger)
 	friendshipRepository := repositories.NewFriendshipRepository(db, logger)
 	leaderboardRepository := repositories.NewLeaderboardRepository(db, logger)
+	seasonRepository := repositories.NewSeasonRepository(db, logger)
+	leagueRepository := repositories.NewLeagueRepository(db, logger)
 	profileRepository := repositories.NewProfileRepository(db, logger)
 	searchRepository := repositories.NewSearchRepository(db, logger)
 	adminRepository := repositories.NewAdminRepository(db, logger);
+	codeSubmissionRepository := repositories.NewCodeSubmissionRepository(db, logger)
+	badgeRepository := repositories.NewBadgeRepository(db, logger)
+	levelingRepository := repositories.NewLevelingRepository(db, logger)
+	streakRepository := repositories.NewStreakRepository(db, logger)
+	pointRepository := repositories.NewPointRepository(db, logger)
+	activityRepository := repositories.NewActivityRepository(db, logger)
+	duelRepository := repositories.NewDuelRepository(db, logger)
+	questRepository := repositories.NewQuestRepository(db, logger)
+	shopRepository := repositories.NewShopRepository(db, logger)
+	multiplierRepository := repositories.NewMultiplierRepository(db, logger)
+
+	sandboxRunner := sandbox.NewRunner(sandbox.DefaultLimits(), logger)
+	graders := grading.NewDefaultRegistry()
+	dispatcher := events.NewDispatcher(logger)
 
 	userService := services.NewUserService(userRepository, logger)
-	authService := services.NewAuthService(logger, userService, authRepository)
+	levelingService := services.NewLevelingService(levelingRepository, logger)
+	streakService := services.NewStreakService(streakRepository, shopRepository, dispatcher, logger)
+	pointService := services.NewPointService(pointRepository, logger)
+	activityService := services.NewActivityService(activityRepository, logger)
+	feedService := services.NewFeedService(activityRepository, logger)
+	authService := services.NewAuthService(logger, userService, authRepository, levelingService)
 	settingService := services.NewSettingService(settingRepository, logger)
-	taskService := services.NewTaskService(taskRepository, logger)
-	friendshipService := services.NewFriendshipService(friendshipRepository, logger)
-	leaderboardService := services.NewLeaderboardService(leaderboardRepository, logger)
-	profileService := services.NewProfileService(logger, profileRepository)
+	taskService := services.NewTaskService(taskRepository, graders, dispatcher, logger)
+	friendshipService := services.NewFriendshipService(friendshipRepository, dispatcher, logger)
+	duelService := services.NewDuelService(duelRepository, graders, dispatcher, logger)
+	var rankingService *services.RankingService
+	if rankingStore != nil {
+		rankingService = services.NewRankingService(rankingStore, leaderboardRepository, logger)
+	}
+	leaderboardService := services.NewLeaderboardService(leaderboardRepository, seasonRepository, rankingService, logger)
+	seasonService := services.NewSeasonService(seasonRepository, leaderboardRepository, logger)
+	leagueService := services.NewLeagueService(leagueRepository, leaderboardRepository, logger)
+	questService := services.NewQuestService(questRepository, logger)
+	shopService := services.NewShopService(shopRepository, dispatcher, logger)
+	multiplierService := services.NewMultiplierService(multiplierRepository, logger)
+	badgeService := services.NewBadgeService(badgeRepository, logger)
+	profileService := services.NewProfileService(logger, profileRepository, badgeService, levelingService)
 	searchService := services.NewSearchService(searchRepository, logger)
-	adminService := services.NewAdminService(adminRepository, logger)
+	adminService := services.NewAdminService(adminRepository, graders, logger)
 	recService := services.NewRecommendationService(taskRepository, userRepository, logger)
+	codeSubmissionService := services.NewCodeSubmissionService(taskRepository, codeSubmissionRepository, sandboxRunner, graders, dispatcher, logger)
 
+	dispatcher.Subscribe(events.TaskCompleted, badgeService.HandleEvent)
+	dispatcher.Subscribe(events.TaskCompleted, leagueService.HandleEvent)
+	dispatcher.Subscribe(events.AnswerSubmitted, questService.HandleEvent)
+	dispatcher.Subscribe(events.TaskCompleted, questService.HandleEvent)
+	activityService.Subscribe(dispatcher)
+	if rankingService != nil {
+		for _, t := range services.RankingEvents {
+			dispatcher.Subscribe(t, rankingService.HandleEvent)
+		}
+	}
 
 	authController := controllers.NewAuthController(logger, authService)
 	settingController := controllers.NewSettingsController(logger, settingService)
 	taskController := controllers.NewTaskController(logger, taskService, recService)
 	friendshipController := controllers.NewFriendshipController(friendshipService, logger)
+	duelController := controllers.NewDuelController(duelService, logger)
 	leaderboardController := controllers.NewLeaderboardController(leaderboardService, logger)
+	leagueController := controllers.NewLeagueController(leagueService, logger)
+	questController := controllers.NewQuestController(questService, logger)
+	shopController := controllers.NewShopController(shopService, logger)
+	multiplierController := controllers.NewMultiplierController(multiplierService, logger)
 	profileController := controllers.NewProfileController(profileService, logger)
+	badgeController := controllers.NewBadgeController(badgeService, logger)
+	streakController := controllers.NewStreakController(streakService, logger)
+	pointController := controllers.NewPointController(pointService, logger)
+	activityController := controllers.NewActivityController(activityService, logger)
+	feedController := controllers.NewFeedController(feedService, logger)
 	searchController := controllers.NewSearchController(searchService, logger)
-	adminController := controllers.NewAdminController(logger, adminService)
+	adminController := controllers.NewAdminController(logger, adminService, levelingService, seasonService, shopService, multiplierService)
+	codeSubmissionController := controllers.NewCodeSubmissionController(codeSubmissionService, logger)
 
 	authRoutes := router.Group("/auth") 
 	{
@@ -58,6 +118,7 @@ func SetupRoutes(router *gin.Engine, db *gorm.DB, logger *slog.Logger) {
 	{
 		settingRoutes.GET("/", middleware.ValidateJWT(), settingController.GetAllUserSettings)
 		settingRoutes.PUT("/update", middleware.ValidateJWT(), settingController.UpdateSettingsForUser)
+		settingRoutes.PUT("/timezone", middleware.ValidateJWT(), streakController.SetTimezone)
 	}
 
 	userRoutes := router.Group("users")
@@ -70,6 +131,7 @@ func SetupRoutes(router *gin.Engine, db *gorm.DB, logger *slog.Logger) {
 	{
 		taskRoutes.GET("/:taskId/tasks/:userId",middleware.ValidateJWT(), taskController.GetTaskForUser)
 		taskRoutes.POST("/submit-answer",middleware.ValidateJWT(), taskController.SubmitAnswer)
+		taskRoutes.POST("/submit-code",middleware.ValidateJWT(), codeSubmissionController.SubmitCode)
 		taskRoutes.GET("/recommended", middleware.ValidateJWT(), taskController.GetRecommendedTasks)
 	}
 
@@ -82,11 +144,64 @@ func SetupRoutes(router *gin.Engine, db *gorm.DB, logger *slog.Logger) {
 		friendshipRoutes.DELETE("/request/:friendshipId",middleware.ValidateJWT(), friendshipController.CancelFriendRequest )
 		friendshipRoutes.PATCH("/request/:friendshipId",middleware.ValidateJWT(), friendshipController.RespondToFriendRequest)
 		friendshipRoutes.DELETE("/:friendshipId",middleware.ValidateJWT(), friendshipController.RemoveFriend)
+		friendshipRoutes.GET("/blocked", middleware.ValidateJWT(), friendshipController.GetBlockedUsers)
+		friendshipRoutes.POST("/block", middleware.ValidateJWT(), friendshipController.BlockUser)
+		friendshipRoutes.DELETE("/block/:userId", middleware.ValidateJWT(), friendshipController.UnblockUser)
+		friendshipRoutes.GET("/muted", middleware.ValidateJWT(), friendshipController.GetMutedUsers)
+		friendshipRoutes.POST("/mute", middleware.ValidateJWT(), friendshipController.MuteUser)
+		friendshipRoutes.DELETE("/mute/:userId", middleware.ValidateJWT(), friendshipController.UnmuteUser)
+	}
+
+	duelRoutes := router.Group("/duels")
+	{
+		duelRoutes.POST("", middleware.ValidateJWT(), duelController.CreateDuel)
+		duelRoutes.GET("", middleware.ValidateJWT(), duelController.GetDuels)
+		duelRoutes.GET("/history/:friendId", middleware.ValidateJWT(), duelController.GetHistory)
+		duelRoutes.GET("/:id", middleware.ValidateJWT(), duelController.GetDuel)
+		duelRoutes.POST("/:id/accept", middleware.ValidateJWT(), duelController.AcceptDuel)
+		duelRoutes.POST("/:id/decline", middleware.ValidateJWT(), duelController.DeclineDuel)
+		duelRoutes.POST("/:id/answers", middleware.ValidateJWT(), duelController.SubmitAnswer)
 	}
 
 	leaderboardRoutes := router.Group("leaderboard")
 	{
 		leaderboardRoutes.GET(":criteria",middleware.ValidateJWT(), leaderboardController.GetLeaderboard)
+		leaderboardRoutes.GET(":criteria/me", middleware.ValidateJWT(), leaderboardController.GetMyRank)
+	}
+
+	seasonRoutes := router.Group("/seasons")
+	{
+		seasonRoutes.GET("", middleware.ValidateJWT(), leaderboardController.GetSeasons)
+		seasonRoutes.GET("/:id/:criteria", middleware.ValidateJWT(), leaderboardController.GetSeasonStandings)
+	}
+
+	leagueRoutes := router.Group("/league")
+	{
+		leagueRoutes.GET("", middleware.ValidateJWT(), leagueController.GetLeague)
+	}
+
+	questRoutes := router.Group("/quests")
+	{
+		questRoutes.GET("", middleware.ValidateJWT(), questController.GetQuests)
+	}
+
+	shopRoutes := router.Group("/shop")
+	{
+		shopRoutes.GET("", middleware.ValidateJWT(), shopController.GetCatalog)
+		shopRoutes.POST("/:id/purchase", middleware.ValidateJWT(), shopController.Purchase)
+	}
+
+	inventoryRoutes := router.Group("/inventory")
+	{
+		inventoryRoutes.GET("", middleware.ValidateJWT(), shopController.GetInventory)
+		inventoryRoutes.POST("/:itemId/equip", middleware.ValidateJWT(), shopController.Equip)
+		inventoryRoutes.POST("/:itemId/unequip", middleware.ValidateJWT(), shopController.Unequip)
+		inventoryRoutes.POST("/hints", middleware.ValidateJWT(), shopController.UseHint)
+	}
+
+	multiplierRoutes := router.Group("/multipliers")
+	{
+		multiplierRoutes.GET("", middleware.ValidateJWT(), multiplierController.GetCurrentEvents)
 	}
 
 	profileRoutes := router.Group("/profile")
@@ -94,6 +209,33 @@ func SetupRoutes(router *gin.Engine, db *gorm.DB, logger *slog.Logger) {
 		profileRoutes.GET("/:id",middleware.ValidateJWT(), profileController.GetProfile )
 	}
 
+	badgeRoutes := router.Group("/badges")
+	{
+		badgeRoutes.GET("", middleware.ValidateJWT(), badgeController.GetCatalog)
+	}
+
+	streakRoutes := router.Group("/streaks")
+	{
+		streakRoutes.GET("/me", middleware.ValidateJWT(), streakController.GetMyStreak)
+		streakRoutes.POST("/freezes", middleware.ValidateJWT(), streakController.BuyFreezes)
+		streakRoutes.POST("/repair", middleware.ValidateJWT(), streakController.RepairStreak)
+	}
+
+	feedRoutes := router.Group("/feed")
+	{
+		feedRoutes.GET("", middleware.ValidateJWT(), feedController.GetFeed)
+	}
+
+	meRoutes := router.Group("/me")
+	{
+		meRoutes.GET("/activity", middleware.ValidateJWT(), activityController.GetMyActivity)
+	}
+
+	pointRoutes := router.Group("/points")
+	{
+		pointRoutes.GET("/transactions", middleware.ValidateJWT(), pointController.GetTransactions)
+	}
+
 	searchRoutes := router.Group("search")
 	{
 		searchRoutes.GET("", middleware.ValidateJWT(), searchController.Search )
@@ -104,7 +246,34 @@ func SetupRoutes(router *gin.Engine, db *gorm.DB, logger *slog.Logger) {
 	{
 		adminRoutes.GET("/stats", adminController.GetStats)       
 		adminRoutes.DELETE("/users/:id", adminController.DeleteUser) 
+		adminRoutes.GET("/tasks", adminController.GetAllTasks)
+		adminRoutes.GET("/tasks/:id", adminController.GetTask)
+		adminRoutes.POST("/tasks", adminController.CreateTask)
+		adminRoutes.PUT("/tasks/:id", adminController.UpdateTask)
+		adminRoutes.PATCH("/tasks/:id/active", adminController.SetTaskActive)
 		adminRoutes.DELETE("/tasks/:id", adminController.DeleteTask) 
+		adminRoutes.POST("/tasks/:id/questions", adminController.CreateQuestion)
+		adminRoutes.PUT("/tasks/:id/questions/:questionId", adminController.UpdateQuestion)
+		adminRoutes.DELETE("/tasks/:id/questions/:questionId", adminController.DeleteQuestion)
+		adminRoutes.PATCH("/tasks/:id/questions/order", adminController.ReorderQuestions)
+		adminRoutes.GET("/badges", adminController.GetAllBadges)
+		adminRoutes.POST("/badges", adminController.CreateBadge)
+		adminRoutes.PUT("/badges/:id", adminController.UpdateBadge)
+		adminRoutes.DELETE("/badges/:id", adminController.DeleteBadge)
+		adminRoutes.GET("/leveling", adminController.GetLevelingCurve)
+		adminRoutes.PUT("/leveling", adminController.UpdateLevelingCurve)
+		adminRoutes.GET("/seasons", adminController.GetAllSeasons)
+		adminRoutes.POST("/seasons", adminController.CreateSeason)
+		adminRoutes.PUT("/seasons/:id", adminController.UpdateSeason)
+		adminRoutes.DELETE("/seasons/:id", adminController.DeleteSeason)
 		adminRoutes.GET("/users", adminController.GetAllUsers)
+		adminRoutes.GET("/shop/items", adminController.GetAllShopItems)
+		adminRoutes.POST("/shop/items", adminController.CreateShopItem)
+		adminRoutes.PUT("/shop/items/:id", adminController.UpdateShopItem)
+		adminRoutes.DELETE("/shop/items/:id", adminController.DeleteShopItem)
+		adminRoutes.GET("/multipliers", adminController.GetAllMultiplierEvents)
+		adminRoutes.POST("/multipliers", adminController.CreateMultiplierEvent)
+		adminRoutes.PUT("/multipliers/:id", adminController.UpdateMultiplierEvent)
+		adminRoutes.DELETE("/multipliers/:id", adminController.DeleteMultiplierEvent)
 	}
 }
\ No newline at end of file
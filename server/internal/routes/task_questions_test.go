@@ -0,0 +1,175 @@
+package routes
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Suplice/CodeQuest/internal/models"
+	"github.com/Suplice/CodeQuest/internal/testdb"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// Answer fields of the test task. They are unique strings, so finding one anywhere in a
+// response body means it leaked.
+var secrets = []string{"secret-answer-one", "secret-explanation-one", "secret-answer-two", "secret-explanation-two"}
+
+func newTestRouter(db *gorm.DB) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	SetupRoutes(router, db, slog.New(slog.NewTextHandler(io.Discard, nil)), nil)
+	return router
+}
+
+func createTask(t *testing.T, db *gorm.DB) *models.Task {
+	t.Helper()
+
+	task := &models.Task{
+		Title:      "Redaction",
+		Type:       models.TaskTypeFillBlank,
+		Language:   "go",
+		Difficulty: models.DifficultyEasy,
+		Points:     10,
+		XP:         20,
+		IsActive:   true,
+		TaskQuestions: []models.TaskQuestion{
+			{QuestionText: "q1 ___", Type: models.TaskTypeFillBlank, CorrectAnswer: "secret-answer-one", Explanation: "secret-explanation-one", Position: 1},
+			{QuestionText: "q2 ___", Type: models.TaskTypeFillBlank, CorrectAnswer: "secret-answer-two", Explanation: "secret-explanation-two", Position: 2},
+		},
+	}
+	if err := db.Create(task).Error; err != nil {
+		t.Fatalf("create task: %v", err)
+	}
+	return task
+}
+
+func doRequest(t *testing.T, router *gin.Engine, method, path string, cookie *http.Cookie, body any) (int, string) {
+	t.Helper()
+
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			t.Fatalf("marshal body: %v", err)
+		}
+		reader = bytes.NewReader(payload)
+	}
+	req := httptest.NewRequest(method, path, reader)
+	req.Header.Set("Content-Type", "application/json")
+	req.AddCookie(cookie)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec.Code, rec.Body.String()
+}
+
+func assertRedacted(t *testing.T, endpoint, body string, visible ...string) {
+	t.Helper()
+
+	if len(visible) == 0 {
+		for _, field := range []string{`"correct_answer"`, `"explanation"`, `"test_input"`, `"expected_output"`} {
+			if strings.Contains(body, field) {
+				t.Errorf("%s: response has a %s field", endpoint, field)
+			}
+		}
+	}
+
+	for _, secret := range secrets {
+		want := false
+		for _, v := range visible {
+			want = want || v == secret
+		}
+		if got := strings.Contains(body, secret); got != want {
+			t.Errorf("%s: contains %q = %v, want %v", endpoint, secret, got, want)
+		}
+	}
+}
+
+func TestQuestionEndpointsRedactAnswers(t *testing.T) {
+	db := testdb.Open(t)
+	router := newTestRouter(db)
+
+	task := createTask(t, db)
+	student := testdb.CreateUser(t, db, "student")
+	viewer := testdb.CreateUser(t, db, "viewer")
+	studentCookie := testdb.AuthCookie(t, student)
+	viewerCookie := testdb.AuthCookie(t, viewer)
+
+	endpoints := []struct {
+		name   string
+		path   string
+		cookie *http.Cookie
+	}{
+		{"task list", fmt.Sprintf("/users/%d/tasks", student.ID), studentCookie},
+		{"task detail", fmt.Sprintf("/tasks/%d/tasks/%d", task.ID, student.ID), studentCookie},
+		{"own profile", fmt.Sprintf("/profile/%d", student.ID), studentCookie},
+		{"other profile", fmt.Sprintf("/profile/%d", student.ID), viewerCookie},
+		{"recommended", "/tasks/recommended", studentCookie},
+	}
+
+	for _, e := range endpoints {
+		code, body := doRequest(t, router, http.MethodGet, e.path, e.cookie, nil)
+		if code != http.StatusOK {
+			t.Fatalf("%s: status %d, body %s", e.name, code, body)
+		}
+		assertRedacted(t, e.name+" before answering", body)
+	}
+
+	code, body := doRequest(t, router, http.MethodPost, "/tasks/submit-answer", studentCookie, map[string]any{
+		"taskId":     task.ID,
+		"questionId": task.TaskQuestions[0].ID,
+		"answer":     "secret-answer-one",
+	})
+	if code != http.StatusOK {
+		t.Fatalf("submit answer: status %d, body %s", code, body)
+	}
+
+	// The solved question is revealed to the student on the task endpoints only.
+	solved := map[string][]string{
+		"task list":   {"secret-answer-one", "secret-explanation-one"},
+		"task detail": {"secret-answer-one", "secret-explanation-one"},
+	}
+	for _, e := range endpoints {
+		code, body := doRequest(t, router, http.MethodGet, e.path, e.cookie, nil)
+		if code != http.StatusOK {
+			t.Fatalf("%s: status %d, body %s", e.name, code, body)
+		}
+		assertRedacted(t, e.name+" after answering", body, solved[e.name]...)
+	}
+}
+
+func TestTaskDetailRevealsEverythingOnceCompleted(t *testing.T) {
+	db := testdb.Open(t)
+	router := newTestRouter(db)
+
+	task := createTask(t, db)
+	student := testdb.CreateUser(t, db, "student")
+	cookie := testdb.AuthCookie(t, student)
+
+	for i, answer := range []string{"secret-answer-one", "secret-answer-two"} {
+		code, body := doRequest(t, router, http.MethodPost, "/tasks/submit-answer", cookie, map[string]any{
+			"taskId":     task.ID,
+			"questionId": task.TaskQuestions[i].ID,
+			"answer":     answer,
+		})
+		if code != http.StatusOK {
+			t.Fatalf("submit answer %d: status %d, body %s", i, code, body)
+		}
+	}
+
+	code, body := doRequest(t, router, http.MethodGet, fmt.Sprintf("/tasks/%d/tasks/%d", task.ID, student.ID), cookie, nil)
+	if code != http.StatusOK {
+		t.Fatalf("task detail: status %d, body %s", code, body)
+	}
+	for _, secret := range secrets {
+		if !strings.Contains(body, secret) {
+			t.Errorf("completed task detail does not reveal %q", secret)
+		}
+	}
+}
@@ -0,0 +1,287 @@
+package sandbox
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	ErrUnsupportedLanguage = errors.New("unsupported language")
+	ErrSandboxUnavailable  = errors.New("code execution is currently unavailable")
+	ErrSandboxUnsupported  = errors.New("code execution is not supported on this platform")
+)
+
+const (
+	// sandboxDir is where the run's working directory is mounted inside the sandbox.
+	sandboxDir = "/work"
+	// sandboxPath is PATH inside the sandbox.
+	sandboxPath = "/usr/local/go/bin:/usr/bin:/bin"
+)
+
+// Limits bound both steps of a run. Compiling runs in the same sandbox as the program but
+// with its own, larger limits, since toolchains need more memory and write a binary.
+type Limits struct {
+	CPUSeconds         int
+	MemoryBytes        int64
+	WallClock          time.Duration
+	CompileTimeout     time.Duration
+	CompileCPUSeconds  int
+	CompileMemoryBytes int64
+	CompileFileBytes   int64
+	MaxOutputBytes     int
+	// Processes caps the processes and threads of a run, which stops fork bombs.
+	Processes        int
+	CompileProcesses int
+}
+
+func DefaultLimits() Limits {
+	return Limits{
+		CPUSeconds:         2,
+		MemoryBytes:        256 << 20,
+		WallClock:          5 * time.Second,
+		CompileTimeout:     60 * time.Second,
+		CompileCPUSeconds:  60,
+		CompileMemoryBytes: 2 << 30,
+		CompileFileBytes:   64 << 20,
+		MaxOutputBytes:     64 << 10,
+		Processes:          64,
+		CompileProcesses:   256,
+	}
+}
+
+type Result struct {
+	Stdout         string
+	Stderr         string
+	ExitCode       int
+	TimedOut       bool
+	MemoryExceeded bool
+	CompileError   bool
+	Duration       time.Duration
+}
+
+// processLimits are the resource limits applied to one sandboxed process.
+type processLimits struct {
+	cpuSeconds  int
+	memoryBytes int64
+	fileBytes   int64
+	processes   int
+}
+
+// exit tells how a sandboxed process ended and which limit, if any, ended it.
+type exit struct {
+	code           int
+	cpuExceeded    bool
+	memoryExceeded bool
+}
+
+func (r *Runner) runLimits() processLimits {
+	return processLimits{
+		cpuSeconds:  r.limits.CPUSeconds,
+		memoryBytes: r.limits.MemoryBytes,
+		fileBytes:   int64(r.limits.MaxOutputBytes),
+		processes:   r.limits.Processes,
+	}
+}
+
+func (r *Runner) compileLimits() processLimits {
+	return processLimits{
+		cpuSeconds:  r.limits.CompileCPUSeconds,
+		memoryBytes: r.limits.CompileMemoryBytes,
+		fileBytes:   r.limits.CompileFileBytes,
+		processes:   r.limits.CompileProcesses,
+	}
+}
+
+type language struct {
+	fileName string
+	compile  []string
+	run      []string
+}
+
+var languages = map[string]language{
+	"go": {
+		fileName: "main.go",
+		compile:  []string{"go", "build", "-o", "prog", "main.go"},
+		run:      []string{"./prog"},
+	},
+	"python": {
+		fileName: "main.py",
+		run:      []string{"python3", "main.py"},
+	},
+	"javascript": {
+		fileName: "main.js",
+		run:      []string{"node", "main.js"},
+	},
+}
+
+func IsSupported(lang string) bool {
+	_, ok := languages[strings.ToLower(lang)]
+	return ok
+}
+
+type Runner struct {
+	limits   Limits
+	logger   *slog.Logger
+	user     string
+	cgroup   string
+	workRoot string
+
+	probeOnce sync.Once
+	probeErr  error
+	uid, gid  uint32
+}
+
+// NewRunner creates a runner executing code in an isolated sandbox. Submissions run as the
+// unprivileged SANDBOX_USER (default codequest-sandbox) in a child of the cgroup v2 directory
+// SANDBOX_CGROUP (default /sys/fs/cgroup/codequest-sandbox), which must delegate the memory
+// and pids controllers. Work directories are created in SANDBOX_WORKDIR, preferably a tmpfs.
+func NewRunner(limits Limits, logger *slog.Logger) *Runner {
+	return &Runner{
+		limits:   limits,
+		logger:   logger,
+		user:     envOr("SANDBOX_USER", "codequest-sandbox"),
+		cgroup:   envOr("SANDBOX_CGROUP", "/sys/fs/cgroup/codequest-sandbox"),
+		workRoot: envOr("SANDBOX_WORKDIR", os.TempDir()),
+	}
+}
+
+func envOr(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// Check reports why submissions cannot be isolated on this host, or nil when they can. A
+// runner that fails the check refuses every run.
+func (r *Runner) Check() error {
+	r.probeOnce.Do(func() {
+		r.probeErr = r.probe()
+		if r.probeErr != nil {
+			r.logger.Error("Code sandbox unavailable, refusing to run submissions", "err", r.probeErr)
+		}
+	})
+	return r.probeErr
+}
+
+func (r *Runner) Run(ctx context.Context, lang string, code string, stdin string) (*Result, error) {
+	spec, ok := languages[strings.ToLower(lang)]
+	if !ok {
+		return nil, ErrUnsupportedLanguage
+	}
+	if err := r.Check(); err != nil {
+		return nil, ErrSandboxUnavailable
+	}
+
+	workDir, err := r.newWorkDir()
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(workDir)
+
+	if err := os.WriteFile(filepath.Join(workDir, spec.fileName), []byte(code), 0o644); err != nil {
+		return nil, err
+	}
+
+	if len(spec.compile) > 0 {
+		compileCtx, cancel := context.WithTimeout(ctx, r.limits.CompileTimeout)
+		defer cancel()
+
+		stderr := &limitedBuffer{max: r.limits.MaxOutputBytes}
+		exit, err := r.run(compileCtx, workDir, spec.compile, compileEnv(), r.compileLimits(), nil, nil, stderr)
+		// The wall clock kills the process too, so a timeout is told apart before anything else.
+		if compileCtx.Err() != nil {
+			return &Result{TimedOut: true, CompileError: true, ExitCode: -1}, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if exit.code != 0 {
+			return &Result{
+				Stderr:         stderr.String(),
+				ExitCode:       exit.code,
+				CompileError:   true,
+				TimedOut:       exit.cpuExceeded,
+				MemoryExceeded: exit.memoryExceeded,
+			}, nil
+		}
+	}
+
+	runCtx, cancel := context.WithTimeout(ctx, r.limits.WallClock)
+	defer cancel()
+
+	stdout := &limitedBuffer{max: r.limits.MaxOutputBytes}
+	stderr := &limitedBuffer{max: r.limits.MaxOutputBytes}
+	start := time.Now()
+	exit, err := r.run(runCtx, workDir, spec.run, runEnv(), r.runLimits(), strings.NewReader(stdin), stdout, stderr)
+	result := &Result{
+		Stdout:   stdout.String(),
+		Stderr:   stderr.String(),
+		Duration: time.Since(start),
+	}
+
+	if runCtx.Err() == context.DeadlineExceeded {
+		result.TimedOut = true
+		result.ExitCode = -1
+		return result, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result.ExitCode = exit.code
+	result.TimedOut = exit.cpuExceeded
+	result.MemoryExceeded = exit.memoryExceeded
+	return result, nil
+}
+
+func runEnv() []string {
+	return []string{
+		"PATH=" + sandboxPath,
+		"HOME=" + sandboxDir,
+		"GOMAXPROCS=2",
+	}
+}
+
+// compileEnv gives every compile a build cache of its own: a shared one would let a submission
+// plant build outputs that later submissions link against. The standard library is rebuilt
+// for each run, hence the generous CompileTimeout.
+func compileEnv() []string {
+	return []string{
+		"PATH=" + sandboxPath,
+		"HOME=" + sandboxDir,
+		"GOCACHE=" + sandboxDir + "/.gocache",
+		"GOPATH=" + sandboxDir + "/.gopath",
+		"GOMAXPROCS=4",
+		"GO111MODULE=off",
+		"CGO_ENABLED=0",
+	}
+}
+
+type limitedBuffer struct {
+	buf bytes.Buffer
+	max int
+}
+
+func (lb *limitedBuffer) Write(p []byte) (int, error) {
+	remaining := lb.max - lb.buf.Len()
+	if remaining > 0 {
+		if len(p) > remaining {
+			lb.buf.Write(p[:remaining])
+		} else {
+			lb.buf.Write(p)
+		}
+	}
+	return len(p), nil
+}
+
+func (lb *limitedBuffer) String() string {
+	return lb.buf.String()
+}
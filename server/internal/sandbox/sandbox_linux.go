@@ -0,0 +1,229 @@
+//go:build linux
+
+package sandbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"os/user"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// readOnlyPaths are the parts of the host filesystem a submission can see: the toolchains and
+// the libraries they load. The server binary, its sources and its configuration stay outside.
+var readOnlyPaths = []string{
+	"/usr/bin", "/usr/lib", "/usr/lib64", "/usr/share", "/usr/local/go",
+	"/bin", "/lib", "/lib64", "/etc/alternatives", "/etc/ld.so.cache",
+}
+
+// probe checks that submissions can be fully isolated: a dedicated user the server does not
+// run as, a cgroup that limits memory and processes, and a working bubblewrap.
+func (r *Runner) probe() error {
+	account, err := user.Lookup(r.user)
+	if err != nil {
+		return fmt.Errorf("sandbox user %q: %w", r.user, err)
+	}
+	uid, err := strconv.ParseUint(account.Uid, 10, 32)
+	if err != nil {
+		return err
+	}
+	gid, err := strconv.ParseUint(account.Gid, 10, 32)
+	if err != nil {
+		return err
+	}
+	if uid == 0 || int(uid) == os.Geteuid() {
+		return fmt.Errorf("sandbox user %q must be an unprivileged user other than the server's", r.user)
+	}
+	r.uid, r.gid = uint32(uid), uint32(gid)
+
+	controllers, err := os.ReadFile(filepath.Join(r.cgroup, "cgroup.subtree_control"))
+	if err != nil {
+		return fmt.Errorf("sandbox cgroup: %w", err)
+	}
+	for _, controller := range []string{"memory", "pids"} {
+		if !slices.Contains(strings.Fields(string(controllers)), controller) {
+			return fmt.Errorf("sandbox cgroup %s does not delegate the %s controller", r.cgroup, controller)
+		}
+	}
+
+	workDir, err := r.newWorkDir()
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(workDir)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	stderr := &limitedBuffer{max: 4 << 10}
+	exit, err := r.run(ctx, workDir, []string{"true"}, runEnv(), r.runLimits(), nil, nil, stderr)
+	if err != nil {
+		return fmt.Errorf("sandbox test run: %w", err)
+	}
+	if exit.code != 0 {
+		return fmt.Errorf("sandbox test run exited with code %d: %s", exit.code, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+// newWorkDir creates an empty working directory owned by the sandbox user.
+func (r *Runner) newWorkDir() (string, error) {
+	dir, err := os.MkdirTemp(r.workRoot, "codequest-run-")
+	if err != nil {
+		return "", err
+	}
+	if err := os.Chown(dir, int(r.uid), int(r.gid)); err != nil {
+		os.RemoveAll(dir)
+		return "", err
+	}
+	return dir, nil
+}
+
+// run executes argv with bubblewrap as the sandbox user, in fresh user, PID, mount, network
+// and IPC namespaces whose read-only root only holds readOnlyPaths, the work directory and an
+// empty /tmp. The process gets a cgroup of its own, which enforces the memory and process
+// limits, kills everything the submission forked and tells afterwards which limit was hit.
+func (r *Runner) run(ctx context.Context, workDir string, argv, env []string, limits processLimits, stdin io.Reader, stdout, stderr io.Writer) (*exit, error) {
+	cgroup, err := os.MkdirTemp(r.cgroup, "run-")
+	if err != nil {
+		return nil, err
+	}
+	defer r.removeCgroup(cgroup)
+
+	if err := writeCgroup(cgroup, "memory.max", strconv.FormatInt(limits.memoryBytes, 10)); err != nil {
+		return nil, err
+	}
+	// The file is missing when the kernel does not account swap, and then there is none to limit.
+	if err := writeCgroup(cgroup, "memory.swap.max", "0"); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	if err := writeCgroup(cgroup, "pids.max", strconv.Itoa(limits.processes)); err != nil {
+		return nil, err
+	}
+
+	dir, err := os.Open(cgroup)
+	if err != nil {
+		return nil, err
+	}
+	defer dir.Close()
+
+	cmd := exec.CommandContext(ctx, "bwrap", bwrapArgs(workDir, argv, limits)...)
+	cmd.Env = env
+	cmd.Stdin = stdin
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		Credential:  &syscall.Credential{Uid: r.uid, Gid: r.gid},
+		UseCgroupFD: true,
+		CgroupFD:    int(dir.Fd()),
+		Setsid:      true,
+	}
+	cmd.Cancel = func() error {
+		return writeCgroup(cgroup, "cgroup.kill", "1")
+	}
+	cmd.WaitDelay = time.Second
+
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) {
+			return nil, err
+		}
+	}
+
+	result := &exit{code: cmd.ProcessState.ExitCode()}
+	if result.code == 0 {
+		return result, nil
+	}
+
+	ooms, err := readCgroupValue(cgroup, "memory.events", "oom_kill")
+	if err != nil {
+		return nil, err
+	}
+	result.memoryExceeded = ooms > 0
+
+	// bwrap exits with 128 plus the signal that killed the submission. The kernel sends
+	// SIGXCPU at the soft CPU limit and SIGKILL at the hard one.
+	if result.code == 128+int(syscall.SIGXCPU) || result.code == 128+int(syscall.SIGKILL) {
+		usage, err := readCgroupValue(cgroup, "cpu.stat", "usage_usec")
+		if err != nil {
+			return nil, err
+		}
+		used := time.Duration(usage) * time.Microsecond
+		result.cpuExceeded = !result.memoryExceeded && used >= time.Duration(limits.cpuSeconds)*time.Second-100*time.Millisecond
+	}
+	return result, nil
+}
+
+func bwrapArgs(workDir string, argv []string, limits processLimits) []string {
+	args := []string{
+		"--unshare-all",
+		"--die-with-parent",
+		"--new-session",
+	}
+	for _, path := range readOnlyPaths {
+		args = append(args, "--ro-bind-try", path, path)
+	}
+	args = append(args,
+		"--proc", "/proc",
+		"--dev", "/dev",
+		"--tmpfs", "/tmp",
+		"--bind", workDir, sandboxDir,
+		"--chdir", sandboxDir,
+		"--remount-ro", "/",
+	)
+
+	// Memory and processes are limited by the cgroup; CPU time and file size per process.
+	script := fmt.Sprintf("ulimit -t %d; ulimit -f %d; exec \"$@\"", limits.cpuSeconds, limits.fileBytes/512+1)
+	args = append(args, "--", "sh", "-c", script, "sh")
+	return append(args, argv...)
+}
+
+// removeCgroup kills whatever is left in a run's cgroup and removes it. The kernel refuses to
+// remove the cgroup until the killed processes are gone.
+func (r *Runner) removeCgroup(cgroup string) {
+	writeCgroup(cgroup, "cgroup.kill", "1")
+	for range 100 {
+		err := os.Remove(cgroup)
+		if err == nil || errors.Is(err, fs.ErrNotExist) {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	r.logger.Warn("Could not remove sandbox cgroup", "cgroup", cgroup)
+}
+
+func writeCgroup(cgroup, file, value string) error {
+	f, err := os.OpenFile(filepath.Join(cgroup, file), os.O_WRONLY, 0)
+	if err != nil {
+		return err
+	}
+	if _, err := f.WriteString(value); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// readCgroupValue returns one counter of a flat keyed cgroup file such as memory.events.
+func readCgroupValue(cgroup, file, key string) (int64, error) {
+	data, err := os.ReadFile(filepath.Join(cgroup, file))
+	if err != nil {
+		return 0, err
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		name, value, ok := strings.Cut(line, " ")
+		if ok && name == key {
+			return strconv.ParseInt(value, 10, 64)
+		}
+	}
+	return 0, fmt.Errorf("%s has no %s", file, key)
+}
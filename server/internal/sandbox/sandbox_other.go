@@ -0,0 +1,21 @@
+//go:build !linux
+
+package sandbox
+
+import (
+	"context"
+	"io"
+)
+
+// Submissions are isolated with Linux namespaces and cgroups, so other platforms cannot run them.
+func (r *Runner) probe() error {
+	return ErrSandboxUnsupported
+}
+
+func (r *Runner) newWorkDir() (string, error) {
+	return "", ErrSandboxUnsupported
+}
+
+func (r *Runner) run(ctx context.Context, workDir string, argv, env []string, limits processLimits, stdin io.Reader, stdout, stderr io.Writer) (*exit, error) {
+	return nil, ErrSandboxUnsupported
+}
@@ -3,6 +3,7 @@ package server
 import (
 	"log/slog"
 
+	"github.com/Suplice/CodeQuest/internal/ranking"
 	"github.com/Suplice/CodeQuest/internal/routes"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
@@ -14,7 +15,7 @@ type Server struct {
 	router 		*gin.Engine
 }
 
-func NewServer(db *gorm.DB, logger *slog.Logger) *Server {
+func NewServer(db *gorm.DB, logger *slog.Logger, rankingStore ranking.Store) *Server {
 
 	server := &Server{
 		db: db,
@@ -28,7 +29,7 @@ func NewServer(db *gorm.DB, logger *slog.Logger) *Server {
 		AllowCredentials: true,
 	}))
 
-	routes.SetupRoutes(server.router, server.db, logger)
+	routes.SetupRoutes(server.router, server.db, logger, rankingStore)
 	
 
 	return server
@@ -0,0 +1,127 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+
+	"github.com/Suplice/CodeQuest/internal/dto"
+	"github.com/Suplice/CodeQuest/internal/events"
+	"github.com/Suplice/CodeQuest/internal/models"
+	"github.com/Suplice/CodeQuest/internal/repositories"
+)
+
+const (
+	defaultActivityPageSize = 20
+	maxActivityPageSize     = 100
+)
+
+// ActivityTypes are the events recorded in the activity timeline.
+var ActivityTypes = []events.Type{
+	events.AnswerSubmitted,
+	events.TaskCompleted,
+	events.LevelUp,
+	events.BadgeEarned,
+	events.FriendAccepted,
+	events.StreakMilestone,
+	events.QuestCompleted,
+}
+
+type ActivityService struct {
+	activityRepository *repositories.ActivityRepository
+	logger             *slog.Logger
+}
+
+func NewActivityService(_activityRepository *repositories.ActivityRepository, _logger *slog.Logger) *ActivityService {
+	return &ActivityService{activityRepository: _activityRepository, logger: _logger}
+}
+
+// Subscribe records every activity type in the timeline.
+func (as *ActivityService) Subscribe(dispatcher *events.Dispatcher) {
+	for _, t := range ActivityTypes {
+		dispatcher.Subscribe(t, as.HandleEvent)
+	}
+}
+
+func (as *ActivityService) HandleEvent(event events.Event) ([]events.Event, error) {
+	metadata, err := json.Marshal(event.Payload)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &models.ActivityLog{
+		UserID:       event.UserID,
+		ActionType:   string(event.Type),
+		Description:  describeEvent(event),
+		PointsEarned: event.Points,
+		XPEarned:     event.XP,
+		Metadata:     metadata,
+		Timestamp:    event.OccurredAt,
+	}
+	if event.TaskID != 0 {
+		entry.TaskID = &event.TaskID
+	}
+	if event.BadgeID != 0 {
+		entry.BadgeID = &event.BadgeID
+	}
+	return nil, as.activityRepository.Create(entry)
+}
+
+func describeEvent(event events.Event) string {
+	p := event.Payload
+	switch event.Type {
+	case events.AnswerSubmitted:
+		if correct, _ := p["correct"].(bool); correct {
+			return fmt.Sprintf("Answered a question in %q correctly", p["title"])
+		}
+		return fmt.Sprintf("Answered a question in %q incorrectly", p["title"])
+	case events.TaskCompleted:
+		return fmt.Sprintf("Completed %q", p["title"])
+	case events.LevelUp:
+		return fmt.Sprintf("Reached level %v", p["level"])
+	case events.BadgeEarned:
+		return fmt.Sprintf("Earned the %v badge", p["name"])
+	case events.FriendAccepted:
+		return fmt.Sprintf("Became friends with %v", p["friend_username"])
+	case events.StreakMilestone:
+		return fmt.Sprintf("Reached a %v day streak", p["streak"])
+	case events.QuestCompleted:
+		return fmt.Sprintf("Completed the quest %q", p["title"])
+	}
+	return string(event.Type)
+}
+
+// GetTimeline returns a page of the user's activity, newest first. cursor is the NextCursor
+// of the previous page, empty for the first one.
+func (as *ActivityService) GetTimeline(userID uint, cursor string, limit int, types []string) (*dto.ActivityPageDTO, error) {
+	if limit <= 0 {
+		limit = defaultActivityPageSize
+	}
+	limit = min(limit, maxActivityPageSize)
+
+	var after uint64
+	if cursor != "" {
+		var err error
+		if after, err = strconv.ParseUint(cursor, 10, 64); err != nil {
+			return nil, fmt.Errorf("%w: invalid cursor", ErrValidation)
+		}
+	}
+	for _, t := range types {
+		if !slices.Contains(ActivityTypes, events.Type(t)) {
+			return nil, fmt.Errorf("%w: unknown activity type %q", ErrValidation, t)
+		}
+	}
+
+	entries, err := as.activityRepository.GetTimeline(userID, uint(after), limit, types)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &dto.ActivityPageDTO{Items: entries}
+	if len(entries) == limit {
+		page.NextCursor = strconv.FormatUint(uint64(entries[len(entries)-1].ID), 10)
+	}
+	return page, nil
+}
@@ -1,20 +1,38 @@
 package services
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
 	"log/slog"
+	"slices"
+	"strings"
 
+	"github.com/Suplice/CodeQuest/internal/badges"
+	"github.com/Suplice/CodeQuest/internal/content"
+	"github.com/Suplice/CodeQuest/internal/dto"
+	"github.com/Suplice/CodeQuest/internal/grading"
 	"github.com/Suplice/CodeQuest/internal/models"
 	"github.com/Suplice/CodeQuest/internal/repositories"
+	"github.com/Suplice/CodeQuest/internal/sandbox"
+)
+
+// ErrValidation wraps every content validation failure so controllers can answer with 400.
+var ErrValidation = errors.New("validation failed")
+
+var (
+	taskTypes    = []string{models.TaskTypeQuiz, models.TaskTypeFillBlank, models.TaskTypeCode}
+	difficulties = []string{models.DifficultyEasy, models.DifficultyMedium, models.DifficultyHard}
 )
 
 type AdminService struct {
 	adminRepo *repositories.AdminRepository
+	graders   *grading.Registry
 	logger    *slog.Logger
 }
 
-func NewAdminService(adminRepo *repositories.AdminRepository, logger *slog.Logger) *AdminService {
-	return &AdminService{adminRepo: adminRepo, logger: logger}
+func NewAdminService(adminRepo *repositories.AdminRepository, graders *grading.Registry, logger *slog.Logger) *AdminService {
+	return &AdminService{adminRepo: adminRepo, graders: graders, logger: logger}
 }
 
 func (as *AdminService) DeleteUser(adminID uint, targetUserID uint) error {
@@ -45,4 +63,358 @@ func (as *AdminService) GetSystemStats() (*repositories.SystemStats, error) {
 
 func (as *AdminService) GetAllUsers() ([]models.User, error) {
 	return as.adminRepo.GetAllUsers()
-}
\ No newline at end of file
+}
+func (as *AdminService) GetAllTasks() ([]models.Task, error) {
+	return as.adminRepo.GetAllTasks()
+}
+
+func (as *AdminService) GetTask(taskID uint) (*models.Task, error) {
+	return as.adminRepo.GetTaskByID(taskID)
+}
+
+func (as *AdminService) CreateTask(data dto.TaskUpsertDTO) (*models.Task, error) {
+	task, err := buildTask(data)
+	if err != nil {
+		return nil, err
+	}
+	if err := as.ensureTaskSlugFree(task.Slug, 0); err != nil {
+		return nil, err
+	}
+
+	for i, q := range data.Questions {
+		question, err := buildQuestion(as.graders, task, q)
+		if err != nil {
+			return nil, fmt.Errorf("question %d: %w", i+1, err)
+		}
+		if questionSlugTaken(task.TaskQuestions, question.Slug, 0) {
+			return nil, fmt.Errorf("question %d: %w: slug %q is used twice", i+1, ErrValidation, question.Slug)
+		}
+		task.TaskQuestions = append(task.TaskQuestions, *question)
+	}
+
+	if err := as.adminRepo.CreateTask(task); err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+func (as *AdminService) UpdateTask(taskID uint, data dto.TaskUpsertDTO) (*models.Task, error) {
+	existing, err := as.adminRepo.GetTaskByID(taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	task, err := buildTask(data)
+	if err != nil {
+		return nil, err
+	}
+	task.ID = taskID
+	if data.IsActive == nil {
+		task.IsActive = existing.IsActive
+	}
+	if strings.TrimSpace(data.Slug) == "" && existing.Slug != "" {
+		task.Slug = existing.Slug
+	}
+	if err := as.ensureTaskSlugFree(task.Slug, taskID); err != nil {
+		return nil, err
+	}
+
+	for _, q := range existing.TaskQuestions {
+		if q.Type != task.Type {
+			return nil, fmt.Errorf("%w: task type cannot change while it has %s questions", ErrValidation, q.Type)
+		}
+	}
+
+	if err := as.adminRepo.UpdateTask(task); err != nil {
+		return nil, err
+	}
+	return as.adminRepo.GetTaskByID(taskID)
+}
+
+func (as *AdminService) SetTaskActive(taskID uint, isActive bool) error {
+	return as.adminRepo.SetTaskActive(taskID, isActive)
+}
+
+func (as *AdminService) CreateQuestion(taskID uint, data dto.QuestionUpsertDTO) (*models.TaskQuestion, error) {
+	task, err := as.adminRepo.GetTaskByID(taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	question, err := buildQuestion(as.graders, task, data)
+	if err != nil {
+		return nil, err
+	}
+	if questionSlugTaken(task.TaskQuestions, question.Slug, 0) {
+		return nil, fmt.Errorf("%w: question slug %q is already used in this task", ErrValidation, question.Slug)
+	}
+	question.TaskID = taskID
+
+	if err := as.adminRepo.CreateQuestion(question); err != nil {
+		return nil, err
+	}
+	return question, nil
+}
+
+func (as *AdminService) UpdateQuestion(taskID, questionID uint, data dto.QuestionUpsertDTO) (*models.TaskQuestion, error) {
+	task, err := as.adminRepo.GetTaskByID(taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	question, err := buildQuestion(as.graders, task, data)
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(data.Slug) == "" {
+		for _, existing := range task.TaskQuestions {
+			if existing.ID == questionID {
+				question.Slug = existing.Slug
+			}
+		}
+	}
+	if questionSlugTaken(task.TaskQuestions, question.Slug, questionID) {
+		return nil, fmt.Errorf("%w: question slug %q is already used in this task", ErrValidation, question.Slug)
+	}
+	question.ID = questionID
+	question.TaskID = taskID
+
+	if err := as.adminRepo.UpdateQuestion(question); err != nil {
+		return nil, err
+	}
+	return as.adminRepo.GetQuestion(taskID, questionID)
+}
+
+func (as *AdminService) DeleteQuestion(taskID, questionID uint) error {
+	return as.adminRepo.DeleteQuestion(taskID, questionID)
+}
+
+func (as *AdminService) ReorderQuestions(taskID uint, questionIDs []uint) (*models.Task, error) {
+	if _, err := as.adminRepo.GetTaskByID(taskID); err != nil {
+		return nil, err
+	}
+	if err := as.adminRepo.ReorderQuestions(taskID, questionIDs); err != nil {
+		return nil, err
+	}
+	return as.adminRepo.GetTaskByID(taskID)
+}
+
+func (as *AdminService) GetAllBadges() ([]models.Badge, error) {
+	return as.adminRepo.GetAllBadges()
+}
+
+func (as *AdminService) CreateBadge(data dto.BadgeUpsertDTO) (*models.Badge, error) {
+	badge, err := buildBadge(data)
+	if err != nil {
+		return nil, err
+	}
+	if err := as.ensureBadgeSlugFree(badge.Slug, 0); err != nil {
+		return nil, err
+	}
+
+	if err := as.adminRepo.CreateBadge(badge); err != nil {
+		return nil, err
+	}
+	return badge, nil
+}
+
+func (as *AdminService) UpdateBadge(badgeID uint, data dto.BadgeUpsertDTO) (*models.Badge, error) {
+	existing, err := as.adminRepo.GetBadgeByID(badgeID)
+	if err != nil {
+		return nil, err
+	}
+
+	badge, err := buildBadge(data)
+	if err != nil {
+		return nil, err
+	}
+	badge.ID = badgeID
+	if strings.TrimSpace(data.Slug) == "" && existing.Slug != "" {
+		badge.Slug = existing.Slug
+	}
+	if err := as.ensureBadgeSlugFree(badge.Slug, badgeID); err != nil {
+		return nil, err
+	}
+
+	if err := as.adminRepo.UpdateBadge(badge); err != nil {
+		return nil, err
+	}
+	return as.adminRepo.GetBadgeByID(badgeID)
+}
+
+func (as *AdminService) DeleteBadge(badgeID uint) error {
+	return as.adminRepo.DeleteBadge(badgeID)
+}
+
+func (as *AdminService) ensureBadgeSlugFree(slug string, badgeID uint) error {
+	taken, err := as.adminRepo.BadgeSlugTaken(slug, badgeID)
+	if err != nil {
+		return err
+	}
+	if taken {
+		return fmt.Errorf("%w: slug %q is already used by another badge", ErrValidation, slug)
+	}
+	return nil
+}
+
+func (as *AdminService) ensureTaskSlugFree(slug string, taskID uint) error {
+	taken, err := as.adminRepo.TaskSlugTaken(slug, taskID)
+	if err != nil {
+		return err
+	}
+	if taken {
+		return fmt.Errorf("%w: slug %q is already used by another task", ErrValidation, slug)
+	}
+	return nil
+}
+
+func questionSlugTaken(questions []models.TaskQuestion, slug string, questionID uint) bool {
+	if slug == "" {
+		return false
+	}
+	for _, q := range questions {
+		if q.Slug == slug && q.ID != questionID {
+			return true
+		}
+	}
+	return false
+}
+
+// buildTask validates task fields shared by the admin API and content imports. Without an
+// explicit slug one is derived from the title.
+func buildTask(data dto.TaskUpsertDTO) (*models.Task, error) {
+	slug := strings.TrimSpace(data.Slug)
+	if slug == "" {
+		slug = content.Slugify(data.Title)
+	}
+	if !content.IsValidSlug(slug) {
+		return nil, fmt.Errorf("%w: slug %q may only contain lowercase letters, digits and dashes", ErrValidation, slug)
+	}
+
+	taskType := strings.ToUpper(strings.TrimSpace(data.Type))
+	if !slices.Contains(taskTypes, taskType) {
+		return nil, fmt.Errorf("%w: type must be one of %s", ErrValidation, strings.Join(taskTypes, ", "))
+	}
+
+	difficulty := strings.ToUpper(strings.TrimSpace(data.Difficulty))
+	if !slices.Contains(difficulties, difficulty) {
+		return nil, fmt.Errorf("%w: difficulty must be one of %s", ErrValidation, strings.Join(difficulties, ", "))
+	}
+
+	language := strings.TrimSpace(data.Language)
+	if taskType == models.TaskTypeCode && !sandbox.IsSupported(language) {
+		return nil, fmt.Errorf("%w: language %q cannot be executed in the sandbox", ErrValidation, language)
+	}
+
+	isActive := true
+	if data.IsActive != nil {
+		isActive = *data.IsActive
+	}
+
+	return &models.Task{
+		Slug:        slug,
+		Title:       strings.TrimSpace(data.Title),
+		Description: data.Description,
+		Type:        taskType,
+		Language:    language,
+		Difficulty:  difficulty,
+		Points:      data.Points,
+		XP:          data.XP,
+		IsActive:    isActive,
+	}, nil
+}
+
+// buildBadge validates badge fields for the admin API. Without an explicit slug one is
+// derived from the name.
+func buildBadge(data dto.BadgeUpsertDTO) (*models.Badge, error) {
+	slug := strings.TrimSpace(data.Slug)
+	if slug == "" {
+		slug = content.Slugify(data.Name)
+	}
+	if !content.IsValidSlug(slug) {
+		return nil, fmt.Errorf("%w: slug %q may only contain lowercase letters, digits and dashes", ErrValidation, slug)
+	}
+
+	requirement, err := normalizeRequirement(data.Requirement)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.Badge{
+		Slug:        slug,
+		Name:        strings.TrimSpace(data.Name),
+		Description: data.Description,
+		IconURL:     strings.TrimSpace(data.IconURL),
+		Requirement: requirement,
+	}, nil
+}
+
+// normalizeRequirement checks that a badge requirement parses as a rule, so a typo is
+// rejected when the badge is saved instead of silently never matching.
+func normalizeRequirement(requirement string) (string, error) {
+	requirement = strings.TrimSpace(requirement)
+	if _, err := badges.ParseRule(requirement); err != nil {
+		return "", fmt.Errorf("%w: %v", ErrValidation, err)
+	}
+	return requirement, nil
+}
+
+func buildQuestion(graders *grading.Registry, task *models.Task, data dto.QuestionUpsertDTO) (*models.TaskQuestion, error) {
+	slug := strings.TrimSpace(data.Slug)
+	if slug != "" && !content.IsValidSlug(slug) {
+		return nil, fmt.Errorf("%w: question slug %q may only contain lowercase letters, digits and dashes", ErrValidation, slug)
+	}
+
+	questionType := strings.ToUpper(strings.TrimSpace(data.Type))
+	if !slices.Contains(taskTypes, questionType) {
+		return nil, fmt.Errorf("%w: question type must be one of %s", ErrValidation, strings.Join(taskTypes, ", "))
+	}
+	if questionType != task.Type {
+		return nil, fmt.Errorf("%w: question type %s does not match task type %s", ErrValidation, questionType, task.Type)
+	}
+
+	question := &models.TaskQuestion{
+		TaskID:         task.ID,
+		Slug:           slug,
+		QuestionText:   data.QuestionText,
+		Type:           questionType,
+		GradingConfig:  data.GradingConfig,
+		CorrectAnswer:  data.CorrectAnswer,
+		TestInput:      data.TestInput,
+		ExpectedOutput: data.ExpectedOutput,
+		Explanation:    data.Explanation,
+	}
+
+	switch questionType {
+	case models.TaskTypeQuiz:
+		var options []string
+		if err := json.Unmarshal(data.Options, &options); err != nil || len(options) < 2 {
+			return nil, fmt.Errorf("%w: QUIZ questions need at least two string options", ErrValidation)
+		}
+		if !slices.Contains(options, data.CorrectAnswer) {
+			return nil, fmt.Errorf("%w: correct answer must be one of the options", ErrValidation)
+		}
+		question.Options = data.Options
+	case models.TaskTypeFillBlank:
+		if !strings.Contains(data.QuestionText, models.BlankMarker) {
+			return nil, fmt.Errorf("%w: FILL_BLANK questions must contain the blank marker %q", ErrValidation, models.BlankMarker)
+		}
+		if strings.TrimSpace(data.CorrectAnswer) == "" {
+			return nil, fmt.Errorf("%w: FILL_BLANK questions need a correct answer", ErrValidation)
+		}
+	case models.TaskTypeCode:
+		if strings.TrimSpace(data.ExpectedOutput) == "" {
+			return nil, fmt.Errorf("%w: CODE questions need an expected output", ErrValidation)
+		}
+	}
+
+	correct := question.CorrectAnswer
+	if questionType == models.TaskTypeCode {
+		correct = question.ExpectedOutput
+	}
+	if err := graders.Validate(questionType, question.GradingConfig, correct); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrValidation, err)
+	}
+
+	return question, nil
+}
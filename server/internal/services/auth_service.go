@@ -22,11 +22,12 @@ import (
 type AuthService struct {
 	authRepository 	*repositories.AuthRepository
 	userService 	*UserService
+	levelingService *LevelingService
 	logger			*slog.Logger
 }
 
-func NewAuthService(_logger *slog.Logger, _us *UserService, _ar *repositories.AuthRepository ) *AuthService {
-	return &AuthService{authRepository: _ar, userService: _us ,logger: _logger}
+func NewAuthService(_logger *slog.Logger, _us *UserService, _ar *repositories.AuthRepository, _ls *LevelingService) *AuthService {
+	return &AuthService{authRepository: _ar, userService: _us, levelingService: _ls, logger: _logger}
 }
 
 func (as *AuthService) Register(data dto.RegisterRequestDTO) (*models.User, error) {
@@ -60,6 +61,7 @@ func (as *AuthService) Register(data dto.RegisterRequestDTO) (*models.User, erro
 
 	user.PasswordHash = ""
 
+	as.levelingService.Annotate(user)
 	return user, nil
 } 
 
@@ -77,6 +79,7 @@ func (as *AuthService) LoginWithEmail(data dto.LoginRequestDTO) (*models.User, e
 
 	user.PasswordHash = ""
 
+	as.levelingService.Annotate(user)
 	return user, nil
 
 }
@@ -89,6 +92,7 @@ func (as *AuthService) FetchUser(userId uint) (*models.User, error) {
 		return nil, err
 	}
 
+	as.levelingService.Annotate(user)
 	return user, nil
 
 }
@@ -136,6 +140,7 @@ func (as *AuthService) LoginWithGoogle(code string) (*models.User, error) {
 	} 
 
 	if user != nil && user.GoogleID == userInfo.GoogleID {
+		as.levelingService.Annotate(user)
 		return user, nil
 	}
 
@@ -145,6 +150,7 @@ func (as *AuthService) LoginWithGoogle(code string) (*models.User, error) {
 		return nil, err
 	}
 
+	as.levelingService.Annotate(registeredUser)
 	return registeredUser, nil
 
 }
@@ -215,6 +221,7 @@ func (as *AuthService) LoginWithGithub(code string) (*models.User, error) {
 	} 
 
 	if user != nil && user.GithubID == userInfo.GithubID {
+		as.levelingService.Annotate(user)
 		return user, nil
 	}
 
@@ -224,6 +231,7 @@ func (as *AuthService) LoginWithGithub(code string) (*models.User, error) {
 		return nil, err
 	}
 
+	as.levelingService.Annotate(registeredUser)
 	return registeredUser, nil
 
 }
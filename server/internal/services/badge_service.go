@@ -0,0 +1,187 @@
+package services
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/Suplice/CodeQuest/internal/badges"
+	"github.com/Suplice/CodeQuest/internal/dto"
+	"github.com/Suplice/CodeQuest/internal/events"
+	"github.com/Suplice/CodeQuest/internal/models"
+	"github.com/Suplice/CodeQuest/internal/repositories"
+)
+
+// BadgeService awards badges whose Requirement rule the user satisfies. Rules live in the
+// database, so adding an achievement only needs a new badge row.
+type BadgeService struct {
+	badgeRepository *repositories.BadgeRepository
+	logger          *slog.Logger
+}
+
+func NewBadgeService(_badgeRepository *repositories.BadgeRepository, _logger *slog.Logger) *BadgeService {
+	return &BadgeService{badgeRepository: _badgeRepository, logger: _logger}
+}
+
+// HandleEvent re-evaluates the user's unearned badges and emits a BadgeEarned event for each new one.
+func (bs *BadgeService) HandleEvent(event events.Event) ([]events.Event, error) {
+	return bs.EvaluateUser(event.UserID)
+}
+
+func (bs *BadgeService) EvaluateUser(userID uint) ([]events.Event, error) {
+	candidates, err := bs.badgeRepository.GetUnearnedBadges(userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+
+	stats, err := bs.badgeRepository.GetUserStats(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var earned []events.Event
+	for _, badge := range candidates {
+		rule, err := badges.ParseRule(badge.Requirement)
+		if err != nil {
+			bs.logger.Warn("Skipping badge with invalid requirement", "badgeID", badge.ID, "requirement", badge.Requirement, "err", err)
+			continue
+		}
+		if !rule.Evaluate(*stats) {
+			continue
+		}
+
+		isNew, err := bs.badgeRepository.AwardBadge(userID, badge.ID)
+		if err != nil {
+			return earned, err
+		}
+		if !isNew {
+			continue
+		}
+
+		bs.logger.Info("Badge earned!", "userID", userID, "badgeName", badge.Name)
+		earned = append(earned, events.Event{
+			Type:    events.BadgeEarned,
+			UserID:  userID,
+			BadgeID: badge.ID,
+			Payload: map[string]any{"slug": badge.Slug, "name": badge.Name, "icon_url": badge.IconURL},
+		})
+	}
+	return earned, nil
+}
+
+// GetCatalog lists every badge with the user's earned state and, for unearned badges, the
+// progress toward the requirement the user is furthest from meeting.
+func (bs *BadgeService) GetCatalog(userID uint) ([]dto.BadgeDTO, error) {
+	all, err := bs.badgeRepository.GetAllBadges()
+	if err != nil {
+		return nil, err
+	}
+	owned, err := bs.badgeRepository.GetUserBadges(userID)
+	if err != nil {
+		return nil, err
+	}
+	stats, err := bs.badgeRepository.GetUserStats(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	earnedByID := make(map[uint]models.UserBadge, len(owned))
+	for _, ub := range owned {
+		earnedByID[ub.BadgeID] = ub
+	}
+
+	catalog := make([]dto.BadgeDTO, 0, len(all))
+	for _, badge := range all {
+		item := badgeToDTO(badge)
+		if ub, ok := earnedByID[badge.ID]; ok {
+			item.Earned = true
+			item.AchievedAt = &ub.AchievedAt
+		} else {
+			item.Progress = badgeProgress(badge, *stats)
+		}
+		catalog = append(catalog, item)
+	}
+	return catalog, nil
+}
+
+func (bs *BadgeService) GetEarnedBadges(userID uint) ([]dto.BadgeDTO, error) {
+	owned, err := bs.badgeRepository.GetUserBadges(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	earned := make([]dto.BadgeDTO, 0, len(owned))
+	for _, ub := range owned {
+		item := badgeToDTO(ub.Badge)
+		item.Earned = true
+		item.AchievedAt = &ub.AchievedAt
+		earned = append(earned, item)
+	}
+	return earned, nil
+}
+
+func badgeToDTO(badge models.Badge) dto.BadgeDTO {
+	return dto.BadgeDTO{
+		ID:          badge.ID,
+		Slug:        badge.Slug,
+		Name:        badge.Name,
+		Description: badge.Description,
+		IconURL:     badge.IconURL,
+		Requirement: badge.Requirement,
+	}
+}
+
+func badgeProgress(badge models.Badge, stats badges.Stats) *dto.BadgeProgressDTO {
+	rule, err := badges.ParseRule(badge.Requirement)
+	if err != nil {
+		return nil
+	}
+	condition, ok := rule.Bottleneck(stats)
+	if !ok {
+		return nil
+	}
+
+	target, _ := condition.Target()
+	current := min(condition.Current(stats), target)
+	return &dto.BadgeProgressDTO{
+		Current: current,
+		Target:  target,
+		Percent: float64(current) / float64(target) * 100,
+		Label:   fmt.Sprintf("%d/%d %s toward %s", current, target, metricUnit(condition), badge.Name),
+	}
+}
+
+func metricUnit(c badges.Condition) string {
+	switch c.Metric {
+	case badges.MetricCompletedTasks:
+		return "tasks"
+	case badges.MetricPerfectTasks:
+		return "perfect tasks"
+	case badges.MetricLanguageCompleted:
+		return c.Arg + " tasks"
+	case badges.MetricStreak:
+		return "streak days"
+	case badges.MetricXP:
+		return "XP"
+	}
+	return strings.ReplaceAll(c.Metric, "_", " ")
+}
+
+// earnedBadges extracts the BadgeEarned events from what a dispatch emitted, for responses
+// that let the client celebrate new badges.
+func earnedBadges(emitted []events.Event) []dto.EarnedBadgeDTO {
+	var result []dto.EarnedBadgeDTO
+	for _, e := range emitted {
+		if e.Type != events.BadgeEarned {
+			continue
+		}
+		slug, _ := e.Payload["slug"].(string)
+		name, _ := e.Payload["name"].(string)
+		iconURL, _ := e.Payload["icon_url"].(string)
+		result = append(result, dto.EarnedBadgeDTO{ID: e.BadgeID, Slug: slug, Name: name, IconURL: iconURL})
+	}
+	return result
+}
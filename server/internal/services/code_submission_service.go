@@ -0,0 +1,134 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/Suplice/CodeQuest/internal/dto"
+	"github.com/Suplice/CodeQuest/internal/events"
+	"github.com/Suplice/CodeQuest/internal/grading"
+	"github.com/Suplice/CodeQuest/internal/models"
+	"github.com/Suplice/CodeQuest/internal/repositories"
+	"github.com/Suplice/CodeQuest/internal/sandbox"
+)
+
+// ErrNotCodeQuestion is returned for code submitted to a question that expects a plain answer.
+var ErrNotCodeQuestion = errors.New("question does not accept code submissions")
+
+type CodeSubmissionService struct {
+	taskRepository       *repositories.TaskRepository
+	submissionRepository *repositories.CodeSubmissionRepository
+	runner               *sandbox.Runner
+	graders              *grading.Registry
+	dispatcher           *events.Dispatcher
+	logger               *slog.Logger
+}
+
+func NewCodeSubmissionService(_taskRepository *repositories.TaskRepository, _submissionRepository *repositories.CodeSubmissionRepository, _runner *sandbox.Runner, _graders *grading.Registry, _dispatcher *events.Dispatcher, _logger *slog.Logger) *CodeSubmissionService {
+	return &CodeSubmissionService{
+		taskRepository:       _taskRepository,
+		submissionRepository: _submissionRepository,
+		runner:               _runner,
+		graders:              _graders,
+		dispatcher:           _dispatcher,
+		logger:               _logger,
+	}
+}
+
+type SubmitCodeResponse struct {
+	SubmissionID uint                 `json:"submission_id"`
+	Status       string               `json:"status"`
+	Output       string               `json:"output"`
+	ErrorMsg     string               `json:"error_msg,omitempty"`
+	IsCorrect    bool                 `json:"is_correct"`
+	IsCompleted  bool                 `json:"is_completed"`
+	UpdatedUser  *models.User         `json:"updated_user,omitempty"`
+	EarnedBadges []dto.EarnedBadgeDTO `json:"earned_badges,omitempty"`
+	LevelUp      *dto.LevelUpDTO      `json:"level_up,omitempty"`
+}
+
+func (css *CodeSubmissionService) SubmitCode(ctx context.Context, userID, taskID, questionID uint, code string) (*SubmitCodeResponse, error) {
+	question, err := css.taskRepository.GetQuestionForTask(taskID, questionID)
+	if err != nil {
+		css.logger.Error("Could not get question for code submission", "err", err, "taskID", taskID, "questionID", questionID)
+		return nil, err
+	}
+
+	if question.Task.Type != models.TaskTypeCode || question.Type != models.TaskTypeCode {
+		return nil, ErrNotCodeQuestion
+	}
+	if !sandbox.IsSupported(question.Task.Language) {
+		return nil, sandbox.ErrUnsupportedLanguage
+	}
+
+	submission := &models.CodeSubmission{
+		UserID:         userID,
+		TaskID:         taskID,
+		TaskQuestionID: questionID,
+		Code:           code,
+		Language:       question.Task.Language,
+		Status:         models.SubmissionStatusPending,
+	}
+	if err := css.submissionRepository.Create(submission); err != nil {
+		return nil, err
+	}
+
+	result, runErr := css.runner.Run(ctx, question.Task.Language, code, question.TestInput)
+	if runErr != nil {
+		css.logger.Error("Sandbox failed to execute submission", "err", runErr, "submissionID", submission.ID)
+		submission.Status = models.SubmissionStatusSystemError
+		submission.ErrorMsg = runErr.Error()
+		if err := css.submissionRepository.UpdateResult(submission); err != nil {
+			return nil, err
+		}
+		return nil, sandbox.ErrSandboxUnavailable
+	}
+
+	outputMatches, err := css.graders.Grade(question.Type, question.GradingConfig, result.Stdout, question.ExpectedOutput)
+	if err != nil {
+		css.logger.Error("Could not grade submission output", "err", err, "questionID", questionID)
+		return nil, err
+	}
+
+	submission.Output = result.Stdout
+	submission.ErrorMsg = result.Stderr
+	switch {
+	case result.CompileError:
+		submission.Status = models.SubmissionStatusCompileError
+	case result.TimedOut:
+		submission.Status = models.SubmissionStatusTimeLimitExceeded
+	case result.MemoryExceeded:
+		submission.Status = models.SubmissionStatusMemoryExceeded
+	case result.ExitCode != 0:
+		submission.Status = models.SubmissionStatusRuntimeError
+	case outputMatches:
+		submission.Status = models.SubmissionStatusAccepted
+	default:
+		submission.Status = models.SubmissionStatusWrongAnswer
+	}
+
+	if err := css.submissionRepository.UpdateResult(submission); err != nil {
+		return nil, err
+	}
+
+	isCorrect := submission.Status == models.SubmissionStatusAccepted
+
+	attempt, err := css.taskRepository.SaveAnswerAttempt(userID, taskID, questionID, code, isCorrect)
+	if err != nil {
+		css.logger.Error("Could not save code answer attempt", "err", err, "userID", userID, "taskID", taskID)
+		return nil, err
+	}
+
+	response := &SubmitCodeResponse{
+		SubmissionID: submission.ID,
+		Status:       submission.Status,
+		Output:       submission.Output,
+		ErrorMsg:     submission.ErrorMsg,
+		IsCorrect:    isCorrect,
+		IsCompleted:  attempt.IsCompleted,
+		UpdatedUser:  attempt.User,
+	}
+	response.EarnedBadges, response.LevelUp = dispatchAnswerEvents(css.dispatcher, userID, question, isCorrect, attempt)
+	return response, nil
+}
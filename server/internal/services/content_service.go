@@ -0,0 +1,462 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/Suplice/CodeQuest/internal/content"
+	"github.com/Suplice/CodeQuest/internal/dto"
+	"github.com/Suplice/CodeQuest/internal/grading"
+	"github.com/Suplice/CodeQuest/internal/models"
+	"github.com/Suplice/CodeQuest/internal/repositories"
+	"gorm.io/datatypes"
+)
+
+// ContentService imports and exports content bundles. Tasks, questions and badges are matched
+// by slug, so importing the same bundle twice changes nothing. A bundle owns the questions of
+// its tasks: questions missing from the bundle are deleted, while tasks and badges that are not
+// mentioned are left alone.
+type ContentService struct {
+	contentRepo *repositories.ContentRepository
+	graders     *grading.Registry
+	logger      *slog.Logger
+}
+
+func NewContentService(contentRepo *repositories.ContentRepository, graders *grading.Registry, logger *slog.Logger) *ContentService {
+	return &ContentService{contentRepo: contentRepo, graders: graders, logger: logger}
+}
+
+// Import validates the whole bundle before touching the database. With dryRun set it only
+// reports what would change.
+func (cs *ContentService) Import(bundle *content.Bundle, dryRun bool) (*content.Diff, error) {
+	tasks, err := cs.buildTasks(bundle.Tasks)
+	if err != nil {
+		return nil, err
+	}
+	badges, err := buildBadges(bundle.Badges)
+	if err != nil {
+		return nil, err
+	}
+
+	taskSlugs := make([]string, len(tasks))
+	for i, task := range tasks {
+		taskSlugs[i] = task.Slug
+	}
+	existingTasks, err := cs.contentRepo.GetTasksBySlugs(taskSlugs)
+	if err != nil {
+		return nil, err
+	}
+	tasksBySlug := make(map[string]models.Task, len(existingTasks))
+	for _, task := range existingTasks {
+		tasksBySlug[task.Slug] = task
+	}
+
+	badgeSlugs := make([]string, len(badges))
+	for i, badge := range badges {
+		badgeSlugs[i] = badge.Slug
+	}
+	existingBadges, err := cs.contentRepo.GetBadgesBySlugs(badgeSlugs)
+	if err != nil {
+		return nil, err
+	}
+	badgesBySlug := make(map[string]models.Badge, len(existingBadges))
+	for _, badge := range existingBadges {
+		badgesBySlug[badge.Slug] = badge
+	}
+
+	diff := &content.Diff{DryRun: dryRun}
+	changes := &repositories.ContentChanges{}
+
+	for _, task := range tasks {
+		current, ok := tasksBySlug[task.Slug]
+		if !ok {
+			diff.Add(content.KindTask, task.Slug, content.ActionCreate)
+			for _, q := range task.TaskQuestions {
+				diff.Add(content.KindQuestion, task.Slug+"/"+q.Slug, content.ActionCreate)
+			}
+			changes.CreateTasks = append(changes.CreateTasks, task)
+			continue
+		}
+
+		task.ID = current.ID
+		if fields := taskChangedFields(&current, task); len(fields) > 0 {
+			diff.Add(content.KindTask, task.Slug, content.ActionUpdate, fields...)
+			changes.UpdateTasks = append(changes.UpdateTasks, task)
+		} else {
+			diff.Add(content.KindTask, task.Slug, content.ActionUnchanged)
+		}
+
+		remaining := make(map[string]models.TaskQuestion, len(current.TaskQuestions))
+		for _, q := range current.TaskQuestions {
+			remaining[questionSlug(&q)] = q
+		}
+
+		for i := range task.TaskQuestions {
+			question := &task.TaskQuestions[i]
+			question.TaskID = current.ID
+			key := task.Slug + "/" + question.Slug
+
+			existing, ok := remaining[question.Slug]
+			if !ok {
+				diff.Add(content.KindQuestion, key, content.ActionCreate)
+				changes.CreateQuestions = append(changes.CreateQuestions, question)
+				continue
+			}
+			delete(remaining, question.Slug)
+
+			question.ID = existing.ID
+			if fields := questionChangedFields(&existing, question); len(fields) > 0 {
+				diff.Add(content.KindQuestion, key, content.ActionUpdate, fields...)
+				changes.UpdateQuestions = append(changes.UpdateQuestions, question)
+			} else {
+				diff.Add(content.KindQuestion, key, content.ActionUnchanged)
+			}
+		}
+
+		for _, q := range current.TaskQuestions {
+			if _, ok := remaining[questionSlug(&q)]; ok {
+				diff.Add(content.KindQuestion, task.Slug+"/"+questionSlug(&q), content.ActionDelete)
+				changes.DeleteQuestionIDs = append(changes.DeleteQuestionIDs, q.ID)
+			}
+		}
+	}
+
+	for _, badge := range badges {
+		current, ok := badgesBySlug[badge.Slug]
+		if !ok {
+			diff.Add(content.KindBadge, badge.Slug, content.ActionCreate)
+			changes.CreateBadges = append(changes.CreateBadges, badge)
+			continue
+		}
+
+		badge.ID = current.ID
+		if fields := badgeChangedFields(&current, badge); len(fields) > 0 {
+			diff.Add(content.KindBadge, badge.Slug, content.ActionUpdate, fields...)
+			changes.UpdateBadges = append(changes.UpdateBadges, badge)
+		} else {
+			diff.Add(content.KindBadge, badge.Slug, content.ActionUnchanged)
+		}
+	}
+
+	if dryRun || !diff.HasChanges() {
+		return diff, nil
+	}
+
+	if err := cs.contentRepo.ApplyImport(changes); err != nil {
+		cs.logger.Error("Failed to import content bundle", "err", err, "bundle", bundle.Metadata.Name)
+		return nil, err
+	}
+	cs.logger.Info("Imported content bundle", "bundle", bundle.Metadata.Name,
+		"created", diff.Count(content.ActionCreate), "updated", diff.Count(content.ActionUpdate), "deleted", diff.Count(content.ActionDelete))
+	return diff, nil
+}
+
+// Export writes every task, including inactive ones, and every badge into a bundle. Imports
+// match on stored slugs, which every row has since migration 0016; a row inserted by hand
+// without one gets a slug derived from its title or ID.
+func (cs *ContentService) Export(metadata content.Metadata) (*content.Bundle, error) {
+	tasks, badges, err := cs.contentRepo.GetAllContent()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	metadata.ExportedAt = &now
+	bundle := &content.Bundle{Version: content.CurrentVersion, Metadata: metadata}
+
+	usedSlugs := make(map[string]bool, len(tasks))
+	for _, task := range tasks {
+		if task.Slug != "" {
+			usedSlugs[task.Slug] = true
+		}
+	}
+
+	for _, task := range tasks {
+		slug := task.Slug
+		if slug == "" {
+			slug = content.Slugify(task.Title)
+			if slug == "" || usedSlugs[slug] {
+				slug = fmt.Sprintf("task-%d", task.ID)
+			}
+			usedSlugs[slug] = true
+			cs.logger.Warn("Exporting task without a slug", "taskID", task.ID, "slug", slug)
+		}
+
+		isActive := task.IsActive
+		spec := content.TaskSpec{
+			Slug:        slug,
+			Title:       task.Title,
+			Description: task.Description,
+			Type:        task.Type,
+			Language:    task.Language,
+			Difficulty:  task.Difficulty,
+			Points:      task.Points,
+			XP:          task.XP,
+			IsActive:    &isActive,
+		}
+
+		for _, q := range task.TaskQuestions {
+			questionSpec := content.QuestionSpec{
+				Slug:           questionSlug(&q),
+				Text:           q.QuestionText,
+				CorrectAnswer:  q.CorrectAnswer,
+				TestInput:      q.TestInput,
+				ExpectedOutput: q.ExpectedOutput,
+				Explanation:    q.Explanation,
+			}
+			if len(q.Options) > 0 {
+				if err := json.Unmarshal(q.Options, &questionSpec.Options); err != nil {
+					return nil, fmt.Errorf("question %d has invalid options: %w", q.ID, err)
+				}
+			}
+			if canonicalJSON(q.GradingConfig) != "" {
+				cfg, err := grading.ParseConfig(q.GradingConfig)
+				if err != nil {
+					return nil, fmt.Errorf("question %d: %w", q.ID, err)
+				}
+				questionSpec.Grading = &cfg
+			}
+			spec.Questions = append(spec.Questions, questionSpec)
+		}
+		bundle.Tasks = append(bundle.Tasks, spec)
+	}
+
+	for _, badge := range badges {
+		slug := badge.Slug
+		if slug == "" {
+			slug = fmt.Sprintf("badge-%d", badge.ID)
+			cs.logger.Warn("Exporting badge without a slug", "badgeID", badge.ID, "slug", slug)
+		}
+		bundle.Badges = append(bundle.Badges, content.BadgeSpec{
+			Slug:        slug,
+			Name:        badge.Name,
+			Description: badge.Description,
+			IconURL:     badge.IconURL,
+			Requirement: badge.Requirement,
+		})
+	}
+
+	return bundle, nil
+}
+
+func (cs *ContentService) buildTasks(specs []content.TaskSpec) ([]*models.Task, error) {
+	tasks := make([]*models.Task, 0, len(specs))
+	seen := make(map[string]bool, len(specs))
+
+	for _, spec := range specs {
+		if spec.Slug == "" {
+			return nil, fmt.Errorf("task %q: %w: slug is required", spec.Title, ErrValidation)
+		}
+		if seen[spec.Slug] {
+			return nil, fmt.Errorf("task %s: %w: slug is used twice", spec.Slug, ErrValidation)
+		}
+		seen[spec.Slug] = true
+
+		task, err := buildTask(dto.TaskUpsertDTO{
+			Slug:        spec.Slug,
+			Title:       spec.Title,
+			Description: spec.Description,
+			Type:        spec.Type,
+			Language:    spec.Language,
+			Difficulty:  spec.Difficulty,
+			Points:      spec.Points,
+			XP:          spec.XP,
+			IsActive:    spec.IsActive,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("task %s: %w", spec.Slug, err)
+		}
+
+		for i, questionSpec := range spec.Questions {
+			if questionSpec.Slug == "" {
+				return nil, fmt.Errorf("task %s question %d: %w: slug is required", spec.Slug, i+1, ErrValidation)
+			}
+			data, err := questionSpecToDTO(task.Type, questionSpec)
+			if err != nil {
+				return nil, fmt.Errorf("task %s question %s: %w", spec.Slug, questionSpec.Slug, err)
+			}
+			question, err := buildQuestion(cs.graders, task, data)
+			if err != nil {
+				return nil, fmt.Errorf("task %s question %s: %w", spec.Slug, questionSpec.Slug, err)
+			}
+			if questionSlugTaken(task.TaskQuestions, question.Slug, 0) {
+				return nil, fmt.Errorf("task %s question %s: %w: slug is used twice", spec.Slug, question.Slug, ErrValidation)
+			}
+			question.Position = i
+			task.TaskQuestions = append(task.TaskQuestions, *question)
+		}
+
+		tasks = append(tasks, task)
+	}
+	return tasks, nil
+}
+
+func questionSpecToDTO(taskType string, spec content.QuestionSpec) (dto.QuestionUpsertDTO, error) {
+	data := dto.QuestionUpsertDTO{
+		Slug:           spec.Slug,
+		QuestionText:   spec.Text,
+		Type:           taskType,
+		CorrectAnswer:  spec.CorrectAnswer,
+		TestInput:      spec.TestInput,
+		ExpectedOutput: spec.ExpectedOutput,
+		Explanation:    spec.Explanation,
+	}
+	if len(spec.Options) > 0 {
+		options, err := json.Marshal(spec.Options)
+		if err != nil {
+			return data, err
+		}
+		data.Options = datatypes.JSON(options)
+	}
+	if spec.Grading != nil {
+		cfg, err := json.Marshal(spec.Grading)
+		if err != nil {
+			return data, err
+		}
+		data.GradingConfig = datatypes.JSON(cfg)
+	}
+	return data, nil
+}
+
+func buildBadges(specs []content.BadgeSpec) ([]*models.Badge, error) {
+	badges := make([]*models.Badge, 0, len(specs))
+	seen := make(map[string]bool, len(specs))
+
+	for _, spec := range specs {
+		if !content.IsValidSlug(spec.Slug) {
+			return nil, fmt.Errorf("badge %q: %w: slug %q may only contain lowercase letters, digits and dashes", spec.Name, ErrValidation, spec.Slug)
+		}
+		if seen[spec.Slug] {
+			return nil, fmt.Errorf("badge %s: %w: slug is used twice", spec.Slug, ErrValidation)
+		}
+		seen[spec.Slug] = true
+		if spec.Name == "" {
+			return nil, fmt.Errorf("badge %s: %w: name is required", spec.Slug, ErrValidation)
+		}
+		requirement, err := normalizeRequirement(spec.Requirement)
+		if err != nil {
+			return nil, fmt.Errorf("badge %s: %w", spec.Slug, err)
+		}
+
+		badges = append(badges, &models.Badge{
+			Slug:        spec.Slug,
+			Name:        spec.Name,
+			Description: spec.Description,
+			IconURL:     spec.IconURL,
+			Requirement: requirement,
+		})
+	}
+	return badges, nil
+}
+
+// questionSlug falls back to a slug derived from the ID for questions created without one,
+// so exporting and re-importing them matches the same rows.
+func questionSlug(q *models.TaskQuestion) string {
+	if q.Slug != "" {
+		return q.Slug
+	}
+	return fmt.Sprintf("q%d", q.ID)
+}
+
+func taskChangedFields(current, next *models.Task) []string {
+	var fields []string
+	if current.Title != next.Title {
+		fields = append(fields, "title")
+	}
+	if current.Description != next.Description {
+		fields = append(fields, "description")
+	}
+	if current.Type != next.Type {
+		fields = append(fields, "type")
+	}
+	if current.Language != next.Language {
+		fields = append(fields, "language")
+	}
+	if current.Difficulty != next.Difficulty {
+		fields = append(fields, "difficulty")
+	}
+	if current.Points != next.Points {
+		fields = append(fields, "points")
+	}
+	if current.XP != next.XP {
+		fields = append(fields, "xp")
+	}
+	if current.IsActive != next.IsActive {
+		fields = append(fields, "is_active")
+	}
+	return fields
+}
+
+func questionChangedFields(current, next *models.TaskQuestion) []string {
+	var fields []string
+	if current.Slug != next.Slug {
+		fields = append(fields, "slug")
+	}
+	if current.QuestionText != next.QuestionText {
+		fields = append(fields, "text")
+	}
+	if current.Type != next.Type {
+		fields = append(fields, "type")
+	}
+	if canonicalJSON(current.Options) != canonicalJSON(next.Options) {
+		fields = append(fields, "options")
+	}
+	if canonicalJSON(current.GradingConfig) != canonicalJSON(next.GradingConfig) {
+		fields = append(fields, "grading")
+	}
+	if current.CorrectAnswer != next.CorrectAnswer {
+		fields = append(fields, "correct_answer")
+	}
+	if current.TestInput != next.TestInput {
+		fields = append(fields, "test_input")
+	}
+	if current.ExpectedOutput != next.ExpectedOutput {
+		fields = append(fields, "expected_output")
+	}
+	if current.Explanation != next.Explanation {
+		fields = append(fields, "explanation")
+	}
+	if current.Position != next.Position {
+		fields = append(fields, "position")
+	}
+	return fields
+}
+
+func badgeChangedFields(current, next *models.Badge) []string {
+	var fields []string
+	if current.Name != next.Name {
+		fields = append(fields, "name")
+	}
+	if current.Description != next.Description {
+		fields = append(fields, "description")
+	}
+	if current.IconURL != next.IconURL {
+		fields = append(fields, "icon_url")
+	}
+	if current.Requirement != next.Requirement {
+		fields = append(fields, "requirement")
+	}
+	return fields
+}
+
+// canonicalJSON normalises stored JSON so that formatting differences and empty values
+// ("", "null", "{}") do not show up as changes.
+func canonicalJSON(raw datatypes.JSON) string {
+	if len(raw) == 0 {
+		return ""
+	}
+	var value interface{}
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return string(raw)
+	}
+	if value == nil {
+		return ""
+	}
+	if m, ok := value.(map[string]interface{}); ok && len(m) == 0 {
+		return ""
+	}
+	normalized, _ := json.Marshal(value)
+	return string(normalized)
+}
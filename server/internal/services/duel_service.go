@@ -0,0 +1,380 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/Suplice/CodeQuest/internal/dto"
+	"github.com/Suplice/CodeQuest/internal/events"
+	"github.com/Suplice/CodeQuest/internal/grading"
+	"github.com/Suplice/CodeQuest/internal/models"
+	"github.com/Suplice/CodeQuest/internal/repositories"
+)
+
+const (
+	// DuelAcceptWindow is how long a challenge waits for the opponent.
+	DuelAcceptWindow = 24 * time.Hour
+	// DuelPlayWindow is how long both players have to finish once the duel is accepted.
+	DuelPlayWindow   = 24 * time.Hour
+	DuelMaxWager     = 500
+	duelHistoryLimit = 50
+)
+
+// ErrNotFriends is returned when a duel is offered to someone who is not a friend.
+var ErrNotFriends = errors.New("users are not friends")
+
+var duelDifficulties = []string{models.DifficultyEasy, models.DifficultyMedium, models.DifficultyHard}
+
+type DuelService struct {
+	duelRepo   *repositories.DuelRepository
+	graders    *grading.Registry
+	dispatcher *events.Dispatcher
+	logger     *slog.Logger
+}
+
+func NewDuelService(_duelRepo *repositories.DuelRepository, _graders *grading.Registry, _dispatcher *events.Dispatcher, _logger *slog.Logger) *DuelService {
+	return &DuelService{duelRepo: _duelRepo, graders: _graders, dispatcher: _dispatcher, logger: _logger}
+}
+
+// pointsChanged reports the wager moving in or out of the players' balances.
+func (ds *DuelService) pointsChanged(wager int, userIDs ...uint) {
+	if wager == 0 {
+		return
+	}
+	for _, userID := range userIDs {
+		ds.dispatcher.Dispatch(events.Event{Type: events.PointsChanged, UserID: userID})
+	}
+}
+
+func (ds *DuelService) CreateDuel(challengerID uint, data dto.CreateDuelDTO) (*dto.DuelDTO, error) {
+	if data.OpponentID == challengerID {
+		return nil, fmt.Errorf("%w: cannot duel yourself", ErrValidation)
+	}
+	if data.Wager < 0 || data.Wager > DuelMaxWager {
+		return nil, fmt.Errorf("%w: wager must be between 0 and %d", ErrValidation, DuelMaxWager)
+	}
+	if data.TaskID == 0 && !slices.Contains(duelDifficulties, data.Difficulty) {
+		return nil, fmt.Errorf("%w: task_id or a difficulty of EASY, MEDIUM or HARD is required", ErrValidation)
+	}
+
+	friends, err := ds.duelRepo.AreFriends(challengerID, data.OpponentID)
+	if err != nil {
+		return nil, err
+	}
+	if !friends {
+		return nil, ErrNotFriends
+	}
+
+	var task *models.Task
+	if data.TaskID != 0 {
+		task, err = ds.duelRepo.GetDuelTask(data.TaskID, challengerID, data.OpponentID)
+	} else {
+		task, err = ds.duelRepo.GetRandomDuelTask(data.Difficulty, challengerID, data.OpponentID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	duel := &models.Duel{
+		ChallengerID: challengerID,
+		OpponentID:   data.OpponentID,
+		TaskID:       task.ID,
+		Wager:        data.Wager,
+		Status:       models.DuelStatusPending,
+		ExpiresAt:    time.Now().Add(DuelAcceptWindow),
+	}
+	if err := ds.duelRepo.CreateDuel(duel); err != nil {
+		return nil, err
+	}
+	ds.logger.Info("Duel created", "duelID", duel.ID, "challengerID", challengerID, "opponentID", data.OpponentID, "wager", data.Wager)
+	ds.pointsChanged(duel.Wager, challengerID)
+	return ds.GetDuel(duel.ID, challengerID)
+}
+
+func (ds *DuelService) AcceptDuel(duelID, userID uint) (*dto.DuelDTO, error) {
+	if err := ds.duelRepo.AcceptDuel(duelID, userID, time.Now(), DuelPlayWindow); err != nil {
+		return nil, err
+	}
+	view, err := ds.GetDuel(duelID, userID)
+	if err != nil {
+		return nil, err
+	}
+	ds.pointsChanged(view.Wager, userID)
+	return view, nil
+}
+
+func (ds *DuelService) DeclineDuel(duelID, userID uint) (*dto.DuelDTO, error) {
+	if err := ds.duelRepo.DeclineDuel(duelID, userID, time.Now()); err != nil {
+		return nil, err
+	}
+	view, err := ds.GetDuel(duelID, userID)
+	if err != nil {
+		return nil, err
+	}
+	ds.pointsChanged(view.Wager, view.Challenger.User.ID)
+	return view, nil
+}
+
+// GetDuel returns the duel as seen by one of its players. Loading an active duel starts the
+// player's clock and includes the questions.
+func (ds *DuelService) GetDuel(duelID, userID uint) (*dto.DuelDTO, error) {
+	duel, err := ds.duelRepo.GetDuel(duelID)
+	if err != nil {
+		return nil, err
+	}
+	if duel.ChallengerID != userID && duel.OpponentID != userID {
+		return nil, repositories.ErrDuelNotFound
+	}
+
+	var questions []models.TaskQuestion
+	if duel.Status == models.DuelStatusActive || duel.Status == models.DuelStatusFinished {
+		questions, err = ds.duelRepo.GetDuelQuestions(duel.TaskID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if duel.Status == models.DuelStatusActive && time.Now().Before(duel.ExpiresAt) {
+		startedAt := duel.OpponentStartedAt
+		if userID == duel.ChallengerID {
+			startedAt = duel.ChallengerStartedAt
+		}
+		if startedAt == nil {
+			if err := ds.duelRepo.StartDuel(duel, userID, time.Now()); err != nil {
+				return nil, err
+			}
+			if duel, err = ds.duelRepo.GetDuel(duelID); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// A finished duel is no way to learn answers: only questions the player already solved
+	// in the task itself are revealed.
+	solved := map[uint]bool{}
+	if duel.Status == models.DuelStatusFinished {
+		if solved, err = ds.duelRepo.GetSolvedQuestionIDs(userID, duel.TaskID); err != nil {
+			return nil, err
+		}
+	}
+
+	view := duelView(duel, userID)
+	view.Questions = duelQuestions(questions, solved)
+	return &view, nil
+}
+
+// SubmitAnswer grades the player's only attempt at a question. When both players have
+// answered everything the duel is settled right away.
+func (ds *DuelService) SubmitAnswer(duelID, userID uint, data dto.DuelAnswerDTO) (*dto.DuelAnswerResultDTO, error) {
+	duel, err := ds.duelRepo.GetDuel(duelID)
+	if err != nil {
+		return nil, err
+	}
+	if duel.ChallengerID != userID && duel.OpponentID != userID {
+		return nil, repositories.ErrDuelNotFound
+	}
+
+	questions, err := ds.duelRepo.GetDuelQuestions(duel.TaskID)
+	if err != nil {
+		return nil, err
+	}
+	i := slices.IndexFunc(questions, func(q models.TaskQuestion) bool { return q.ID == data.QuestionID })
+	if i < 0 {
+		return nil, repositories.ErrQuestionNotFound
+	}
+	question := questions[i]
+
+	isCorrect, err := ds.graders.Grade(question.Type, question.GradingConfig, data.Answer, question.CorrectAnswer)
+	if err != nil {
+		ds.logger.Error("Could not grade duel answer", "err", err, "questionID", question.ID)
+		return nil, err
+	}
+
+	duel, err = ds.duelRepo.SaveDuelAnswer(duelID, userID, question.ID, data.Answer, isCorrect, len(questions), time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if duel.ChallengerFinishedAt != nil && duel.OpponentFinishedAt != nil {
+		settled, err := ds.duelRepo.SettleDuel(duel.ID, models.DuelStatusFinished, duelWinner(duel), time.Now())
+		if err != nil {
+			return nil, err
+		}
+		if settled {
+			ds.pointsChanged(duel.Wager, duel.ChallengerID, duel.OpponentID)
+		}
+	}
+
+	view, err := ds.GetDuel(duelID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.DuelAnswerResultDTO{IsCorrect: isCorrect, Duel: *view}, nil
+}
+
+// GetDuels returns the caller's open duels, or those in status when it is set.
+func (ds *DuelService) GetDuels(userID uint, status string) ([]dto.DuelDTO, error) {
+	statuses := []string{models.DuelStatusPending, models.DuelStatusActive}
+	if status != "" {
+		statuses = []string{status}
+	}
+	duels, err := ds.duelRepo.GetUserDuels(userID, statuses)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]dto.DuelDTO, len(duels))
+	for i := range duels {
+		results[i] = duelView(&duels[i], userID)
+	}
+	return results, nil
+}
+
+// GetHistory returns the caller's record against a friend and their closed duels.
+func (ds *DuelService) GetHistory(userID, friendID uint) (*dto.DuelHistoryDTO, error) {
+	record, err := ds.duelRepo.GetDuelRecord(userID, friendID)
+	if err != nil {
+		return nil, err
+	}
+	duels, err := ds.duelRepo.GetDuelHistory(userID, friendID, duelHistoryLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	history := &dto.DuelHistoryDTO{
+		FriendID: friendID,
+		Wins:     record.Wins,
+		Losses:   record.Losses,
+		Draws:    record.Draws,
+		Duels:    make([]dto.DuelDTO, len(duels)),
+	}
+	for i := range duels {
+		history.Duels[i] = duelView(&duels[i], userID)
+	}
+	return history, nil
+}
+
+// ExpireDuels closes duels past their deadline and returns how many it closed. Unanswered
+// challenges and duels nobody finished are refunded; a duel only one player finished goes to
+// that player.
+func (ds *DuelService) ExpireDuels(ctx context.Context) (int, error) {
+	now := time.Now()
+	duels, err := ds.duelRepo.GetDuelsToExpire(now)
+	if err != nil {
+		return 0, err
+	}
+
+	expired := 0
+	for i := range duels {
+		if err := ctx.Err(); err != nil {
+			return expired, err
+		}
+		duel := &duels[i]
+
+		status, winnerID := models.DuelStatusExpired, (*uint)(nil)
+		challengerDone, opponentDone := duel.ChallengerFinishedAt != nil, duel.OpponentFinishedAt != nil
+		switch {
+		case challengerDone && opponentDone:
+			status, winnerID = models.DuelStatusFinished, duelWinner(duel)
+		case challengerDone:
+			status, winnerID = models.DuelStatusFinished, &duel.ChallengerID
+		case opponentDone:
+			status, winnerID = models.DuelStatusFinished, &duel.OpponentID
+		}
+
+		ok, err := ds.duelRepo.SettleDuel(duel.ID, status, winnerID, now)
+		if err != nil {
+			return expired, err
+		}
+		if ok {
+			ds.pointsChanged(duel.Wager, duel.ChallengerID, duel.OpponentID)
+			expired++
+		}
+	}
+	return expired, nil
+}
+
+// duelWinner decides a duel both players finished: more correct answers wins, then the
+// shorter time. It returns nil for a draw.
+func duelWinner(duel *models.Duel) *uint {
+	if duel.ChallengerCorrect != duel.OpponentCorrect {
+		if duel.ChallengerCorrect > duel.OpponentCorrect {
+			return &duel.ChallengerID
+		}
+		return &duel.OpponentID
+	}
+
+	challengerTime := duel.ChallengerFinishedAt.Sub(*duel.ChallengerStartedAt)
+	opponentTime := duel.OpponentFinishedAt.Sub(*duel.OpponentStartedAt)
+	switch {
+	case challengerTime < opponentTime:
+		return &duel.ChallengerID
+	case opponentTime < challengerTime:
+		return &duel.OpponentID
+	}
+	return nil
+}
+
+func duelView(duel *models.Duel, viewerID uint) dto.DuelDTO {
+	over := duel.Status == models.DuelStatusFinished
+	answered := make(map[uint]int)
+	for _, a := range duel.Answers {
+		answered[a.UserID]++
+	}
+
+	player := func(user models.User, startedAt, finishedAt *time.Time, correct int) dto.DuelPlayerDTO {
+		p := dto.DuelPlayerDTO{
+			User:     dto.UserShortInfo{ID: user.ID, Username: user.Username, AvatarURL: user.AvatarURL, Level: user.Level, Points: user.Points},
+			Started:  startedAt != nil,
+			Finished: finishedAt != nil,
+		}
+		if over || user.ID == viewerID {
+			count := answered[user.ID]
+			p.Answered = &count
+			if finishedAt != nil {
+				ms := finishedAt.Sub(*startedAt).Milliseconds()
+				p.Correct, p.DurationMs = &correct, &ms
+			}
+		}
+		return p
+	}
+
+	return dto.DuelDTO{
+		ID:         duel.ID,
+		Status:     duel.Status,
+		TaskID:     duel.TaskID,
+		TaskTitle:  duel.Task.Title,
+		Difficulty: duel.Task.Difficulty,
+		Wager:      duel.Wager,
+		Challenger: player(duel.Challenger, duel.ChallengerStartedAt, duel.ChallengerFinishedAt, duel.ChallengerCorrect),
+		Opponent:   player(duel.Opponent, duel.OpponentStartedAt, duel.OpponentFinishedAt, duel.OpponentCorrect),
+		WinnerID:   duel.WinnerID,
+		ExpiresAt:  duel.ExpiresAt,
+		AcceptedAt: duel.AcceptedAt,
+		FinishedAt: duel.FinishedAt,
+		CreatedAt:  duel.CreatedAt,
+	}
+}
+
+// duelQuestions hides the answers of every question not in revealed.
+func duelQuestions(questions []models.TaskQuestion, revealed map[uint]bool) []dto.TaskQuestionDTO {
+	result := make([]dto.TaskQuestionDTO, len(questions))
+	for i, q := range questions {
+		result[i] = dto.TaskQuestionDTO{
+			ID:           q.ID,
+			TaskID:       q.TaskID,
+			QuestionText: q.QuestionText,
+			Type:         q.Type,
+			Options:      q.Options,
+			IsRevealed:   revealed[q.ID],
+		}
+		if revealed[q.ID] {
+			correctAnswer, explanation := q.CorrectAnswer, q.Explanation
+			result[i].CorrectAnswer = &correctAnswer
+			result[i].Explanation = &explanation
+		}
+	}
+	return result
+}
@@ -0,0 +1,23 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/Suplice/CodeQuest/internal/models"
+)
+
+func TestDuelQuestionsRevealOnlySolvedQuestions(t *testing.T) {
+	questions := []models.TaskQuestion{
+		{QuestionText: "first", CorrectAnswer: "a", Explanation: "because a"},
+		{QuestionText: "second", CorrectAnswer: "b", Explanation: "because b"},
+	}
+	questions[0].ID, questions[1].ID = 10, 11
+
+	public := duelQuestions(questions, map[uint]bool{11: true})
+	if public[0].IsRevealed || public[0].CorrectAnswer != nil || public[0].Explanation != nil {
+		t.Errorf("unsolved question is revealed: %+v", public[0])
+	}
+	if !public[1].IsRevealed || public[1].CorrectAnswer == nil || *public[1].CorrectAnswer != "b" {
+		t.Errorf("solved question is not revealed: %+v", public[1])
+	}
+}
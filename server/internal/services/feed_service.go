@@ -0,0 +1,184 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/Suplice/CodeQuest/internal/dto"
+	"github.com/Suplice/CodeQuest/internal/events"
+	"github.com/Suplice/CodeQuest/internal/models"
+	"github.com/Suplice/CodeQuest/internal/repositories"
+)
+
+const (
+	defaultFeedPageSize = 20
+	maxFeedPageSize     = 50
+	// feedScanFactor is how many raw entries are read per requested item, so that collapsed
+	// bursts still fill a page.
+	feedScanFactor = 4
+	// feedBurstWindow is the longest gap between two entries that are still shown as one item.
+	feedBurstWindow = time.Hour
+)
+
+// FeedTypes are the activity types friends can see.
+var FeedTypes = []events.Type{events.TaskCompleted, events.BadgeEarned, events.LevelUp}
+
+type FeedService struct {
+	activityRepository *repositories.ActivityRepository
+	logger             *slog.Logger
+}
+
+func NewFeedService(_activityRepository *repositories.ActivityRepository, _logger *slog.Logger) *FeedService {
+	return &FeedService{activityRepository: _activityRepository, logger: _logger}
+}
+
+// GetFeed returns the recent activity of the user's friends, newest first, with bursts of the
+// same kind of activity collapsed into one item. cursor is the NextCursor of the previous page.
+func (fs *FeedService) GetFeed(userID uint, cursor string, limit int, types []string) (*dto.FeedPageDTO, error) {
+	if limit <= 0 {
+		limit = defaultFeedPageSize
+	}
+	limit = min(limit, maxFeedPageSize)
+
+	var after uint64
+	if cursor != "" {
+		var err error
+		if after, err = strconv.ParseUint(cursor, 10, 64); err != nil {
+			return nil, fmt.Errorf("%w: invalid cursor", ErrValidation)
+		}
+	}
+	if len(types) == 0 {
+		for _, t := range FeedTypes {
+			types = append(types, string(t))
+		}
+	}
+	for _, t := range types {
+		if !slices.Contains(FeedTypes, events.Type(t)) {
+			return nil, fmt.Errorf("%w: unknown feed type %q", ErrValidation, t)
+		}
+	}
+
+	scanSize := limit * feedScanFactor
+	entries, err := fs.activityRepository.GetFriendFeed(userID, uint(after), scanSize, types)
+	if err != nil {
+		return nil, err
+	}
+
+	groups, consumed := collapseFeed(entries, limit)
+
+	page := &dto.FeedPageDTO{Items: make([]dto.FeedItemDTO, 0, len(groups))}
+	if consumed < len(entries) || len(entries) == scanSize {
+		page.NextCursor = strconv.FormatUint(uint64(entries[consumed-1].ID), 10)
+	}
+	if len(groups) == 0 {
+		return page, nil
+	}
+
+	userIDs := make([]uint, 0, len(groups))
+	for _, g := range groups {
+		if !slices.Contains(userIDs, g[0].UserID) {
+			userIDs = append(userIDs, g[0].UserID)
+		}
+	}
+	users, err := fs.activityRepository.GetUsersShortInfo(userIDs)
+	if err != nil {
+		return nil, err
+	}
+	usersByID := make(map[uint]dto.UserShortInfo, len(users))
+	for _, u := range users {
+		usersByID[u.ID] = u
+	}
+
+	for _, g := range groups {
+		page.Items = append(page.Items, feedItem(usersByID[g[0].UserID], g))
+	}
+	return page, nil
+}
+
+// collapseFeed groups entries (newest first) into at most limit items. An entry joins the
+// newest open item with the same user, type and language when it happened within
+// feedBurstWindow of that item's oldest entry. It also returns how many entries were used;
+// the rest belong to the next page.
+func collapseFeed(entries []models.ActivityLog, limit int) ([][]models.ActivityLog, int) {
+	var groups [][]models.ActivityLog
+	open := make(map[string]int)
+
+	for i, entry := range entries {
+		key := fmt.Sprintf("%d|%s|%s", entry.UserID, entry.ActionType, feedLanguage(entry))
+		if idx, ok := open[key]; ok {
+			oldest := groups[idx][len(groups[idx])-1]
+			if oldest.Timestamp.Sub(entry.Timestamp) <= feedBurstWindow {
+				groups[idx] = append(groups[idx], entry)
+				continue
+			}
+		}
+		if len(groups) == limit {
+			return groups, i
+		}
+		open[key] = len(groups)
+		groups = append(groups, []models.ActivityLog{entry})
+	}
+	return groups, len(entries)
+}
+
+func feedItem(user dto.UserShortInfo, group []models.ActivityLog) dto.FeedItemDTO {
+	newest, oldest := group[0], group[len(group)-1]
+	item := dto.FeedItemDTO{
+		User:     user,
+		Type:     newest.ActionType,
+		Count:    len(group),
+		Language: feedLanguage(newest),
+		FirstAt:  oldest.Timestamp,
+		LastAt:   newest.Timestamp,
+		Entries:  group,
+	}
+	for _, entry := range group {
+		item.XPEarned += entry.XPEarned
+		item.PointsEarned += entry.PointsEarned
+	}
+
+	meta := feedMetadata(newest)
+	switch events.Type(newest.ActionType) {
+	case events.TaskCompleted:
+		switch {
+		case item.Count == 1:
+			item.Summary = fmt.Sprintf("%s completed %q", user.Username, meta["title"])
+		case item.Language != "":
+			item.Summary = fmt.Sprintf("%s completed %d %s tasks", user.Username, item.Count, item.Language)
+		default:
+			item.Summary = fmt.Sprintf("%s completed %d tasks", user.Username, item.Count)
+		}
+	case events.BadgeEarned:
+		if item.Count == 1 {
+			item.Summary = fmt.Sprintf("%s earned the %v badge", user.Username, meta["name"])
+		} else {
+			item.Summary = fmt.Sprintf("%s earned %d badges", user.Username, item.Count)
+		}
+	case events.LevelUp:
+		item.Summary = fmt.Sprintf("%s reached level %v", user.Username, meta["level"])
+	default:
+		item.Summary = fmt.Sprintf("%s: %s", user.Username, newest.Description)
+	}
+	return item
+}
+
+func feedMetadata(entry models.ActivityLog) map[string]any {
+	var meta map[string]any
+	if len(entry.Metadata) > 0 {
+		_ = json.Unmarshal(entry.Metadata, &meta)
+	}
+	return meta
+}
+
+// feedLanguage is the task language of a completion, which splits bursts per language.
+func feedLanguage(entry models.ActivityLog) string {
+	if events.Type(entry.ActionType) != events.TaskCompleted {
+		return ""
+	}
+	language, _ := feedMetadata(entry)["language"].(string)
+	return language
+}
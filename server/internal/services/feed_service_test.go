@@ -0,0 +1,132 @@
+package services
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/Suplice/CodeQuest/internal/events"
+	"github.com/Suplice/CodeQuest/internal/models"
+)
+
+func TestCollapseFeed(t *testing.T) {
+	now := time.Date(2026, 5, 4, 18, 0, 0, 0, time.UTC)
+	entry := func(id, userID uint, kind events.Type, language string, minutesAgo int) models.ActivityLog {
+		e := models.ActivityLog{UserID: userID, ActionType: string(kind), Timestamp: now.Add(-time.Duration(minutesAgo) * time.Minute)}
+		e.ID = id
+		if language != "" {
+			e.Metadata = []byte(fmt.Sprintf(`{"language":%q}`, language))
+		}
+		return e
+	}
+
+	tests := []struct {
+		name     string
+		entries  []models.ActivityLog
+		limit    int
+		groups   [][]uint
+		consumed int
+	}{
+		{
+			name: "burst within the window",
+			entries: []models.ActivityLog{
+				entry(3, 1, events.TaskCompleted, "go", 0),
+				entry(2, 1, events.TaskCompleted, "go", 20),
+				entry(1, 1, events.TaskCompleted, "go", 60),
+			},
+			limit:    10,
+			groups:   [][]uint{{3, 2, 1}},
+			consumed: 3,
+		},
+		{
+			name: "burst longer than the window with short gaps",
+			entries: []models.ActivityLog{
+				entry(4, 1, events.TaskCompleted, "go", 0),
+				entry(3, 1, events.TaskCompleted, "go", 50),
+				entry(2, 1, events.TaskCompleted, "go", 100),
+				entry(1, 1, events.TaskCompleted, "go", 150),
+			},
+			limit:    10,
+			groups:   [][]uint{{4, 3, 2, 1}},
+			consumed: 4,
+		},
+		{
+			name: "gap longer than the window",
+			entries: []models.ActivityLog{
+				entry(2, 1, events.TaskCompleted, "go", 0),
+				entry(1, 1, events.TaskCompleted, "go", 61),
+			},
+			limit:    10,
+			groups:   [][]uint{{2}, {1}},
+			consumed: 2,
+		},
+		{
+			name: "mixed languages",
+			entries: []models.ActivityLog{
+				entry(4, 1, events.TaskCompleted, "go", 0),
+				entry(3, 1, events.TaskCompleted, "python", 5),
+				entry(2, 1, events.TaskCompleted, "go", 10),
+				entry(1, 1, events.TaskCompleted, "python", 15),
+			},
+			limit:    10,
+			groups:   [][]uint{{4, 2}, {3, 1}},
+			consumed: 4,
+		},
+		{
+			name: "users and types stay apart",
+			entries: []models.ActivityLog{
+				entry(4, 1, events.TaskCompleted, "go", 0),
+				entry(3, 2, events.TaskCompleted, "go", 5),
+				entry(2, 1, events.BadgeEarned, "", 10),
+				entry(1, 1, events.TaskCompleted, "go", 15),
+			},
+			limit:    10,
+			groups:   [][]uint{{4, 1}, {3}, {2}},
+			consumed: 4,
+		},
+		{
+			name: "page cut in the middle of a burst",
+			entries: []models.ActivityLog{
+				entry(5, 1, events.TaskCompleted, "go", 0),
+				entry(4, 2, events.TaskCompleted, "go", 5),
+				entry(3, 1, events.TaskCompleted, "go", 10),
+				entry(2, 3, events.LevelUp, "", 15),
+				entry(1, 1, events.TaskCompleted, "go", 20),
+			},
+			limit:    2,
+			groups:   [][]uint{{5, 3}, {4}},
+			consumed: 3,
+		},
+		{
+			name: "full page still takes the rest of an open burst",
+			entries: []models.ActivityLog{
+				entry(3, 1, events.TaskCompleted, "go", 0),
+				entry(2, 1, events.TaskCompleted, "go", 10),
+				entry(1, 2, events.TaskCompleted, "go", 20),
+			},
+			limit:    1,
+			groups:   [][]uint{{3, 2}},
+			consumed: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			groups, consumed := collapseFeed(tt.entries, tt.limit)
+			var ids [][]uint
+			for _, g := range groups {
+				var group []uint
+				for _, e := range g {
+					group = append(group, e.ID)
+				}
+				ids = append(ids, group)
+			}
+			if !slices.EqualFunc(ids, tt.groups, slices.Equal[[]uint]) {
+				t.Errorf("groups %v, want %v", ids, tt.groups)
+			}
+			if consumed != tt.consumed {
+				t.Errorf("consumed %d entries, want %d", consumed, tt.consumed)
+			}
+		})
+	}
+}
@@ -3,18 +3,26 @@ package services
 import (
 	"errors"
 	"log/slog"
+	"time"
 
 	"github.com/Suplice/CodeQuest/internal/dto"
+	"github.com/Suplice/CodeQuest/internal/events"
+	"github.com/Suplice/CodeQuest/internal/models"
 	"github.com/Suplice/CodeQuest/internal/repositories"
 )
 
+// FriendRequestCooldown is how long a sender has to wait before asking again after their
+// request was declined.
+const FriendRequestCooldown = 7 * 24 * time.Hour
+
 type FriendshipService struct {
-	repo   *repositories.FriendshipRepository
-	logger *slog.Logger
+	repo       *repositories.FriendshipRepository
+	dispatcher *events.Dispatcher
+	logger     *slog.Logger
 }
 
-func NewFriendshipService(repo *repositories.FriendshipRepository, logger *slog.Logger) *FriendshipService {
-	return &FriendshipService{repo: repo, logger: logger}
+func NewFriendshipService(repo *repositories.FriendshipRepository, dispatcher *events.Dispatcher, logger *slog.Logger) *FriendshipService {
+	return &FriendshipService{repo: repo, dispatcher: dispatcher, logger: logger}
 }
 
 func (fs *FriendshipService) GetAcceptedFriends(userID uint) ([]dto.FriendshipDTO, error) {
@@ -34,7 +42,7 @@ func (fs *FriendshipService) SearchUsers(query string, currentUserID uint) ([]dt
 }
 
 func (fs *FriendshipService) SendRequest(userID, friendID uint) error {
-	return fs.repo.CreateFriendRequest(userID, friendID)
+	return fs.repo.CreateFriendRequest(userID, friendID, FriendRequestCooldown)
 }
 
 func (fs *FriendshipService) CancelRequest(friendshipID uint, userID uint) error {
@@ -51,9 +59,51 @@ func (fs *FriendshipService) RespondToRequest(friendshipID uint, currentUserID u
 	default:
 		return errors.New("invalid action")
 	}
-	return fs.repo.UpdateFriendshipStatus(friendshipID, currentUserID, newStatus)
+	friendship, err := fs.repo.UpdateFriendshipStatus(friendshipID, currentUserID, newStatus)
+	if err != nil {
+		return err
+	}
+
+	if newStatus == "accepted" {
+		// Both users gained a friend.
+		fs.dispatcher.Dispatch(events.Event{
+			Type:    events.FriendAccepted,
+			UserID:  friendship.UserID,
+			Payload: map[string]any{"friend_id": friendship.FriendID, "friend_username": friendship.Friend.Username},
+		})
+		fs.dispatcher.Dispatch(events.Event{
+			Type:    events.FriendAccepted,
+			UserID:  friendship.FriendID,
+			Payload: map[string]any{"friend_id": friendship.UserID, "friend_username": friendship.User.Username},
+		})
+	}
+	return nil
 }
 
 func (fs *FriendshipService) RemoveFriend(friendshipID uint, currentUserID uint) error {
 	return fs.repo.DeleteFriendship(friendshipID, currentUserID)
-}
\ No newline at end of file
+}
+
+func (fs *FriendshipService) BlockUser(userID, targetID uint) error {
+	return fs.repo.BlockUser(userID, targetID)
+}
+
+func (fs *FriendshipService) UnblockUser(userID, targetID uint) error {
+	return fs.repo.RemoveRestriction(userID, targetID, models.UserBlockKindBlock)
+}
+
+func (fs *FriendshipService) GetBlockedUsers(userID uint) ([]dto.RestrictedUserDTO, error) {
+	return fs.repo.GetRestrictedUsers(userID, models.UserBlockKindBlock)
+}
+
+func (fs *FriendshipService) MuteUser(userID, targetID uint) error {
+	return fs.repo.MuteUser(userID, targetID)
+}
+
+func (fs *FriendshipService) UnmuteUser(userID, targetID uint) error {
+	return fs.repo.RemoveRestriction(userID, targetID, models.UserBlockKindMute)
+}
+
+func (fs *FriendshipService) GetMutedUsers(userID uint) ([]dto.RestrictedUserDTO, error) {
+	return fs.repo.GetRestrictedUsers(userID, models.UserBlockKindMute)
+}
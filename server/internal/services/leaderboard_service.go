@@ -1,22 +1,49 @@
 package services
 
 import (
+	"context"
 	"errors"
 	"log/slog"
+	"time"
 
 	"github.com/Suplice/CodeQuest/internal/dto"
+	"github.com/Suplice/CodeQuest/internal/models"
 	"github.com/Suplice/CodeQuest/internal/repositories"
 )
 
 type LeaderboardService struct {
 	leaderboardRepo *repositories.LeaderboardRepository
+	seasonRepo      *repositories.SeasonRepository
+	rankingService  *RankingService
 	logger          *slog.Logger
 }
 
-func NewLeaderboardService(lRepo *repositories.LeaderboardRepository, logger *slog.Logger) *LeaderboardService {
-	return &LeaderboardService{leaderboardRepo: lRepo,  logger: logger}
+// NewLeaderboardService creates the service. rankingService may be nil, in which case every
+// leaderboard is ranked in SQL.
+func NewLeaderboardService(lRepo *repositories.LeaderboardRepository, sRepo *repositories.SeasonRepository, rankingService *RankingService, logger *slog.Logger) *LeaderboardService {
+	return &LeaderboardService{leaderboardRepo: lRepo, seasonRepo: sRepo, rankingService: rankingService, logger: logger}
 }
 
+const (
+	PeriodAll    = "all"
+	PeriodWeek   = "week"
+	PeriodMonth  = "month"
+	PeriodSeason = "season"
+)
+
+// periodWindow returns the UTC calendar week (starting Monday) or month containing now.
+func periodWindow(period string, now time.Time) (time.Time, time.Time) {
+	now = now.UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if period == PeriodMonth {
+		start := today.AddDate(0, 0, 1-today.Day())
+		return start, start.AddDate(0, 1, 0)
+	}
+	start := today.AddDate(0, 0, -((int(today.Weekday()) + 6) % 7))
+	return start, start.AddDate(0, 0, 7)
+}
+
+// addRank assigns competition ranks to entries sorted by Value, the same way RANK() does.
 func addRank(entries []dto.LeaderboardEntryDTO) []dto.LeaderboardEntryDTO {
 	if len(entries) == 0 {
 		return entries
@@ -32,57 +59,167 @@ func addRank(entries []dto.LeaderboardEntryDTO) []dto.LeaderboardEntryDTO {
 	return entries
 }
 
-func (ls *LeaderboardService) GetLeaderboard(criteria string, filter string, currentUserID uint, limit int) ([]dto.LeaderboardEntryDTO, error) {
-	var friendIDs []uint
-	var err error
+func rankingEntries(criteria string, rows []repositories.RankingRow) []dto.LeaderboardEntryDTO {
+	results := make([]dto.LeaderboardEntryDTO, len(rows))
+	for i, r := range rows {
+		results[i] = dto.LeaderboardEntryDTO{
+			User: dto.UserShortInfo{
+				ID:          r.UserID,
+				Username:    r.Username,
+				AvatarURL:   r.AvatarURL,
+				Level:       r.Level,
+				Points:      r.Points,
+				AvatarFrame: r.AvatarFrame,
+			},
+			Value: r.Value,
+			Rank:  r.Rank,
+		}
+		if criteria == "completed" {
+			completedCount := r.Value
+			results[i].CompletedCourses = &completedCount
+		}
+	}
+	return results
+}
+
+func (ls *LeaderboardService) friendIDs(filter string, currentUserID uint) ([]uint, error) {
+	if filter != "friends" {
+		return nil, nil
+	}
+	return ls.leaderboardRepo.GetFriendIDs(currentUserID)
+}
 
-	if filter == "friends" {
-		friendIDs, err = ls.leaderboardRepo.GetFriendIDs(currentUserID)
+// window resolves a period to the time range it ranks, nil for all-time totals.
+func (ls *LeaderboardService) window(period string) (*repositories.RankingWindow, error) {
+	switch period {
+	case PeriodAll:
+		return nil, nil
+	case PeriodWeek, PeriodMonth:
+		from, to := periodWindow(period, time.Now())
+		return &repositories.RankingWindow{From: from, To: to}, nil
+	case PeriodSeason:
+		season, err := ls.seasonRepo.GetSeasonAt(time.Now())
 		if err != nil {
-			return nil, err 
+			return nil, err
 		}
+		return &repositories.RankingWindow{From: season.StartsAt, To: season.EndsAt}, nil
 	}
+	return nil, errors.New("invalid leaderboard period")
+}
+
+// Criteria lists the names accepted as leaderboard criteria.
+func (ls *LeaderboardService) Criteria() []string {
+	return ls.leaderboardRepo.CriteriaNames()
+}
 
-	var results []dto.LeaderboardEntryDTO
+func (ls *LeaderboardService) scope(filter, period, language string, currentUserID uint) (repositories.RankingScope, error) {
+	friendIDs, err := ls.friendIDs(filter, currentUserID)
+	if err != nil {
+		return repositories.RankingScope{}, err
+	}
+	window, err := ls.window(period)
+	if err != nil {
+		return repositories.RankingScope{}, err
+	}
+	return repositories.RankingScope{Window: window, Language: language, FriendIDs: friendIDs}, nil
+}
 
-	switch criteria {
-	case "level":
-		users, err := ls.leaderboardRepo.GetUsersByLevel(limit, friendIDs)
-		if err != nil { return nil, err }
-		results = make([]dto.LeaderboardEntryDTO, len(users))
-		for i, u := range users {
-			results[i] = dto.LeaderboardEntryDTO{User: u, Value: u.Level}
+// GetLeaderboard returns a page of the ranking by all-time totals, or for the week, month and
+// season periods by what users earned within that window. With the friends filter users are
+// ranked among the caller's friends, and language limits it to tasks of that language.
+func (ls *LeaderboardService) GetLeaderboard(criteria, filter, period, language string, currentUserID uint, limit, offset int) ([]dto.LeaderboardEntryDTO, error) {
+	if ls.rankingService != nil && ls.rankingService.Cached(criteria, filter, period, language) {
+		ctx, cancel := context.WithTimeout(context.Background(), rankingStoreTimeout)
+		rows, ok, err := ls.rankingService.Page(ctx, criteria, period, limit, offset)
+		cancel()
+		if err != nil {
+			ls.logger.Warn("Ranking store unavailable, ranking in SQL", "err", err, "criteria", criteria, "period", period)
+		} else if ok {
+			return rankingEntries(criteria, rows), nil
 		}
-	case "points":
-		users, err := ls.leaderboardRepo.GetUsersByPoints(limit, friendIDs)
-		if err != nil { return nil, err }
-		results = make([]dto.LeaderboardEntryDTO, len(users))
-		for i, u := range users {
-			results[i] = dto.LeaderboardEntryDTO{User: u, Value: u.Points}
+	}
+
+	scope, err := ls.scope(filter, period, language, currentUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := ls.leaderboardRepo.GetRanking(criteria, scope, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	return rankingEntries(criteria, rows), nil
+}
+
+// GetLeaderboardAround returns the caller's exact rank with up to n neighbours above and below.
+func (ls *LeaderboardService) GetLeaderboardAround(criteria, filter, period, language string, currentUserID uint, n int) (*dto.LeaderboardAroundDTO, error) {
+	rows, err := ls.rankingAround(criteria, filter, period, language, currentUserID, n)
+	if err != nil {
+		return nil, err
+	}
+
+	around := &dto.LeaderboardAroundDTO{Entries: rankingEntries(criteria, rows)}
+	for i, r := range rows {
+		around.Total = r.Total
+		if r.UserID == currentUserID {
+			me := around.Entries[i]
+			around.Me = &me
 		}
-	case "completed":
-		completedResults, err := ls.leaderboardRepo.GetUsersByCompletedCourses(limit, friendIDs)
-		if err != nil { return nil, err }
-		results = make([]dto.LeaderboardEntryDTO, len(completedResults))
-		for i, r := range completedResults {
-			completedCount := r.CompletedCount
-			results[i] = dto.LeaderboardEntryDTO{
-				User: dto.UserShortInfo{
-					ID:        r.UserID,
-					Username:  r.Username,
-					AvatarURL: r.AvatarURL,
-					Level:     r.Level,
-					Points:    r.Points,
-				},
-				Value:           r.CompletedCount,
-				CompletedCourses: &completedCount, 
-			}
+	}
+	return around, nil
+}
+
+func (ls *LeaderboardService) rankingAround(criteria, filter, period, language string, currentUserID uint, n int) ([]repositories.RankingRow, error) {
+	if ls.rankingService != nil && ls.rankingService.Cached(criteria, filter, period, language) {
+		ctx, cancel := context.WithTimeout(context.Background(), rankingStoreTimeout)
+		rows, ok, err := ls.rankingService.Around(ctx, criteria, period, currentUserID, n)
+		cancel()
+		if err != nil {
+			ls.logger.Warn("Ranking store unavailable, ranking in SQL", "err", err, "criteria", criteria, "period", period)
+		} else if ok {
+			return rows, nil
 		}
-	default:
-		return nil, errors.New("invalid leaderboard criteria")
 	}
 
-	results = addRank(results)
+	scope, err := ls.scope(filter, period, language, currentUserID)
+	if err != nil {
+		return nil, err
+	}
+	return ls.leaderboardRepo.GetRankingAround(criteria, scope, currentUserID, n)
+}
 
+func (ls *LeaderboardService) GetSeasons() ([]models.Season, error) {
+	return ls.seasonRepo.GetSeasons()
+}
+
+// GetSeasonStandings returns the archived final standings of a season, or the live ranking
+// while it is still running. With the friends filter ranks are among friends.
+func (ls *LeaderboardService) GetSeasonStandings(seasonID uint, criteria string, filter string, currentUserID uint, limit int) ([]dto.LeaderboardEntryDTO, error) {
+	season, err := ls.seasonRepo.GetSeasonByID(seasonID)
+	if err != nil {
+		return nil, err
+	}
+	friendIDs, err := ls.friendIDs(filter, currentUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if season.ArchivedAt == nil {
+		window := &repositories.RankingWindow{From: season.StartsAt, To: season.EndsAt}
+		rows, err := ls.leaderboardRepo.GetRanking(criteria, repositories.RankingScope{Window: window, FriendIDs: friendIDs}, limit, 0)
+		if err != nil {
+			return nil, err
+		}
+		return rankingEntries(criteria, rows), nil
+	}
+
+	rows, err := ls.seasonRepo.GetStandings(season.ID, criteria, limit, friendIDs)
+	if err != nil {
+		return nil, err
+	}
+	results := rankingEntries(criteria, rows)
+	if len(friendIDs) > 0 {
+		results = addRank(results)
+	}
 	return results, nil
-}
\ No newline at end of file
+}
@@ -0,0 +1,247 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/Suplice/CodeQuest/internal/dto"
+	"github.com/Suplice/CodeQuest/internal/events"
+	"github.com/Suplice/CodeQuest/internal/models"
+	"github.com/Suplice/CodeQuest/internal/repositories"
+)
+
+const (
+	LeagueCohortSize = 30
+	// LeagueZoneSize is how many members are promoted and demoted at the end of a week. Small
+	// cohorts move at most half their members each way.
+	LeagueZoneSize     = 5
+	leagueHistoryLimit = 10
+
+	LeagueZonePromotion = "promotion"
+	LeagueZoneDemotion  = "demotion"
+)
+
+// LeagueService runs the weekly leagues. Users are placed in a cohort of their tier when they
+// first earn XP in a week and ranked by the XP earned that week; when the week is over the top
+// of each cohort moves up a tier and the bottom moves down.
+type LeagueService struct {
+	leagueRepo      *repositories.LeagueRepository
+	leaderboardRepo *repositories.LeaderboardRepository
+	logger          *slog.Logger
+}
+
+func NewLeagueService(_leagueRepo *repositories.LeagueRepository, _leaderboardRepo *repositories.LeaderboardRepository, _logger *slog.Logger) *LeagueService {
+	return &LeagueService{leagueRepo: _leagueRepo, leaderboardRepo: _leaderboardRepo, logger: _logger}
+}
+
+// zoneSizes returns how many of n members are promoted and demoted from tier.
+func zoneSizes(tier string, n int) (promote, demote int) {
+	size := min(LeagueZoneSize, n/2)
+	if tier != models.LeagueDiamond {
+		promote = size
+	}
+	if tier != models.LeagueBronze {
+		demote = size
+	}
+	return promote, demote
+}
+
+// zone returns the zone of the member at the 0-based position. Members without XP are never
+// promoted.
+func zone(position, n, xp, promote, demote int) string {
+	switch {
+	case position < promote && xp > 0:
+		return LeagueZonePromotion
+	case position >= n-demote:
+		return LeagueZoneDemotion
+	}
+	return ""
+}
+
+func shiftTier(tier string, by int) string {
+	i := slices.Index(models.LeagueTiers, tier)
+	if i < 0 {
+		return models.LeagueBronze
+	}
+	return models.LeagueTiers[max(0, min(len(models.LeagueTiers)-1, i+by))]
+}
+
+// standings ranks a cohort by the XP its members earned in the week, reusing the windowed xp
+// leaderboard. Members who earned nothing share the last rank.
+func (ls *LeagueService) standings(cohort models.LeagueCohort, members []models.LeagueMember) ([]dto.LeagueStandingDTO, error) {
+	if len(members) == 0 {
+		return []dto.LeagueStandingDTO{}, nil
+	}
+
+	userIDs := make([]uint, len(members))
+	for i, m := range members {
+		userIDs[i] = m.UserID
+	}
+	window := &repositories.RankingWindow{From: cohort.WeekStart, To: cohort.WeekStart.AddDate(0, 0, 7)}
+	rows, err := ls.leaderboardRepo.GetRanking("xp", repositories.RankingScope{Window: window, FriendIDs: userIDs}, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	standings := make([]dto.LeagueStandingDTO, 0, len(members))
+	ranked := make(map[uint]bool, len(rows))
+	for _, r := range rows {
+		ranked[r.UserID] = true
+		standings = append(standings, dto.LeagueStandingDTO{
+			Rank: r.Rank,
+			User: dto.UserShortInfo{ID: r.UserID, Username: r.Username, AvatarURL: r.AvatarURL, Level: r.Level, Points: r.Points, AvatarFrame: r.AvatarFrame},
+			XP:   r.Value,
+		})
+	}
+
+	var idle []uint
+	for _, id := range userIDs {
+		if !ranked[id] {
+			idle = append(idle, id)
+		}
+	}
+	users, err := ls.leaderboardRepo.GetUsersByIDs(idle)
+	if err != nil {
+		return nil, err
+	}
+	for _, u := range users {
+		standings = append(standings, dto.LeagueStandingDTO{
+			Rank: len(rows) + 1,
+			User: dto.UserShortInfo{ID: u.ID, Username: u.Username, AvatarURL: u.AvatarURL, Level: u.Level, Points: u.Points, AvatarFrame: u.AvatarFrame},
+		})
+	}
+
+	promote, demote := zoneSizes(cohort.Tier, len(standings))
+	for i := range standings {
+		standings[i].Zone = zone(i, len(standings), standings[i].XP, promote, demote)
+	}
+	return standings, nil
+}
+
+// GetLeague returns the caller's tier, their cohort's current standings and recent results.
+func (ls *LeagueService) GetLeague(userID uint) (*dto.LeagueDTO, error) {
+	tier, err := ls.leagueRepo.GetUserTier(userID)
+	if err != nil {
+		return nil, err
+	}
+	weekStart, weekEnd := periodWindow(PeriodWeek, time.Now())
+	history, err := ls.leagueRepo.GetHistory(userID, leagueHistoryLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	league := &dto.LeagueDTO{Tier: tier, WeekStart: weekStart, WeekEnd: weekEnd, History: history}
+
+	member, err := ls.leagueRepo.GetMembership(userID, weekStart)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNoLeagueMembership) {
+			return league, nil
+		}
+		return nil, err
+	}
+
+	members, err := ls.leagueRepo.GetCohortMembers(member.CohortID)
+	if err != nil {
+		return nil, err
+	}
+	standings, err := ls.standings(member.Cohort, members)
+	if err != nil {
+		return nil, err
+	}
+	promote, demote := zoneSizes(member.Cohort.Tier, len(standings))
+	league.Tier = member.Tier
+	league.Cohort = &dto.LeagueCohortDTO{ID: member.CohortID, PromoteCount: promote, DemoteCount: demote, Standings: standings}
+	return league, nil
+}
+
+// HandleEvent places users in this week's cohort when they complete a task. A result from an
+// earlier week that the job has not finalized yet is settled first, so the user joins the
+// tier they earned.
+func (ls *LeagueService) HandleEvent(event events.Event) ([]events.Event, error) {
+	if event.Type != events.TaskCompleted {
+		return nil, nil
+	}
+
+	weekStart, _ := periodWindow(PeriodWeek, event.OccurredAt)
+	pending, err := ls.leagueRepo.GetPendingMembership(event.UserID, weekStart)
+	if err != nil {
+		return nil, err
+	}
+	if pending != nil {
+		if _, err := ls.finalizeCohort(pending.Cohort); err != nil {
+			return nil, err
+		}
+	}
+
+	tier, err := ls.leagueRepo.GetUserTier(event.UserID)
+	if err != nil {
+		return nil, err
+	}
+	_, err = ls.leagueRepo.PlaceUser(event.UserID, tier, weekStart, LeagueCohortSize)
+	return nil, err
+}
+
+// FinalizeEndedWeeks settles every cohort of a finished week and returns how many it settled.
+func (ls *LeagueService) FinalizeEndedWeeks(ctx context.Context) (int, error) {
+	weekStart, _ := periodWindow(PeriodWeek, time.Now())
+	cohorts, err := ls.leagueRepo.GetCohortsToFinalize(weekStart)
+	if err != nil {
+		return 0, err
+	}
+
+	finalized := 0
+	for _, cohort := range cohorts {
+		if err := ctx.Err(); err != nil {
+			return finalized, err
+		}
+		ok, err := ls.finalizeCohort(cohort)
+		if err != nil {
+			return finalized, err
+		}
+		if ok {
+			finalized++
+		}
+	}
+	return finalized, nil
+}
+
+func (ls *LeagueService) finalizeCohort(cohort models.LeagueCohort) (bool, error) {
+	members, err := ls.leagueRepo.GetCohortMembers(cohort.ID)
+	if err != nil {
+		return false, err
+	}
+	standings, err := ls.standings(cohort, members)
+	if err != nil {
+		return false, err
+	}
+
+	byUser := make(map[uint]int, len(members))
+	for i, m := range members {
+		byUser[m.UserID] = i
+	}
+	newTiers := make(map[uint]string)
+	for _, s := range standings {
+		m := &members[byUser[s.User.ID]]
+		m.XP, m.Rank, m.Result = s.XP, s.Rank, models.LeagueResultStayed
+		switch s.Zone {
+		case LeagueZonePromotion:
+			m.Result = models.LeagueResultPromoted
+			newTiers[m.UserID] = shiftTier(cohort.Tier, 1)
+		case LeagueZoneDemotion:
+			m.Result = models.LeagueResultDemoted
+			newTiers[m.UserID] = shiftTier(cohort.Tier, -1)
+		}
+	}
+
+	ok, err := ls.leagueRepo.FinalizeCohort(cohort.ID, members, newTiers)
+	if err != nil {
+		return false, err
+	}
+	if ok {
+		ls.logger.Info("Finalized league cohort", "cohortID", cohort.ID, "tier", cohort.Tier, "members", len(members), "moved", len(newTiers))
+	}
+	return ok, nil
+}
@@ -0,0 +1,70 @@
+package services
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/Suplice/CodeQuest/internal/models"
+)
+
+func TestLeagueZones(t *testing.T) {
+	const (
+		up   = LeagueZonePromotion
+		down = LeagueZoneDemotion
+	)
+
+	tests := []struct {
+		name  string
+		tier  string
+		xp    []int
+		zones []string
+	}{
+		{"single member", models.LeagueSilver, []int{50}, []string{""}},
+		{"two members", models.LeagueSilver, []int{50, 10}, []string{up, down}},
+		{"three members", models.LeagueGold, []int{50, 30, 10}, []string{up, "", down}},
+		{"small cohort moves half", models.LeagueGold, []int{60, 50, 40, 30, 20, 10}, []string{up, up, up, down, down, down}},
+		{
+			"full zones",
+			models.LeagueSilver,
+			[]int{120, 110, 100, 90, 80, 70, 60, 50, 40, 30, 20, 10},
+			[]string{up, up, up, up, up, "", "", down, down, down, down, down},
+		},
+		{"bronze is never demoted", models.LeagueBronze, []int{60, 50, 40, 30, 20, 10}, []string{up, up, up, "", "", ""}},
+		{"diamond is never promoted", models.LeagueDiamond, []int{60, 50, 40, 30, 20, 10}, []string{"", "", "", down, down, down}},
+		{"members without xp are not promoted", models.LeagueSilver, []int{30, 0, 0, 0}, []string{up, "", down, down}},
+		{"nobody earned xp", models.LeagueBronze, []int{0, 0, 0, 0}, []string{"", "", "", ""}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := len(tt.xp)
+			promote, demote := zoneSizes(tt.tier, n)
+			zones := make([]string, n)
+			for i, xp := range tt.xp {
+				zones[i] = zone(i, n, xp, promote, demote)
+			}
+			if !slices.Equal(zones, tt.zones) {
+				t.Errorf("zones %q, want %q", zones, tt.zones)
+			}
+		})
+	}
+}
+
+func TestShiftTier(t *testing.T) {
+	tests := []struct {
+		tier string
+		by   int
+		want string
+	}{
+		{models.LeagueBronze, 1, models.LeagueSilver},
+		{models.LeagueGold, -1, models.LeagueSilver},
+		{models.LeagueBronze, -1, models.LeagueBronze},
+		{models.LeagueDiamond, 1, models.LeagueDiamond},
+		{models.LeaguePlatinum, 0, models.LeaguePlatinum},
+		{"wood", 1, models.LeagueBronze},
+	}
+	for _, tt := range tests {
+		if got := shiftTier(tt.tier, tt.by); got != tt.want {
+			t.Errorf("shiftTier(%q, %d) = %q, want %q", tt.tier, tt.by, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package services
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/Suplice/CodeQuest/internal/dto"
+	"github.com/Suplice/CodeQuest/internal/leveling"
+	"github.com/Suplice/CodeQuest/internal/models"
+	"github.com/Suplice/CodeQuest/internal/repositories"
+)
+
+// previewLevels is how many levels the admin curve endpoint lists thresholds for.
+const previewLevels = 20
+
+type LevelingService struct {
+	levelingRepository *repositories.LevelingRepository
+	logger             *slog.Logger
+}
+
+func NewLevelingService(_levelingRepository *repositories.LevelingRepository, _logger *slog.Logger) *LevelingService {
+	return &LevelingService{levelingRepository: _levelingRepository, logger: _logger}
+}
+
+func (ls *LevelingService) GetCurve() (*dto.LevelingCurveDTO, error) {
+	curve, err := ls.levelingRepository.GetCurve()
+	if err != nil {
+		return nil, err
+	}
+	return curveToDTO(curve), nil
+}
+
+// UpdateCurve validates and stores a new curve and recomputes every user's level with it.
+// Levels the new curve puts lower are kept.
+func (ls *LevelingService) UpdateCurve(curve leveling.Curve) (*dto.LevelingCurveDTO, int, error) {
+	if err := curve.Validate(); err != nil {
+		return nil, 0, fmt.Errorf("%w: %v", ErrValidation, err)
+	}
+
+	changed, err := ls.levelingRepository.SaveCurve(curve)
+	if err != nil {
+		return nil, 0, err
+	}
+	ls.logger.Info("Leveling curve updated", "kind", curve.Kind, "usersRecomputed", changed)
+	return curveToDTO(curve), changed, nil
+}
+
+// Annotate fills the user's XP-to-next-level fields. Failing to load the curve only leaves
+// them empty, so it is logged instead of failing the request.
+func (ls *LevelingService) Annotate(user *models.User) {
+	if user == nil {
+		return
+	}
+	curve, err := ls.levelingRepository.GetCurve()
+	if err != nil {
+		ls.logger.Error("Failed to load leveling curve", "err", err, "userID", user.ID)
+		return
+	}
+	curve.Annotate(user)
+}
+
+func curveToDTO(curve leveling.Curve) *dto.LevelingCurveDTO {
+	limit := previewLevels
+	if curve.MaxLevel > 0 {
+		limit = min(limit, curve.MaxLevel)
+	}
+
+	preview := make([]dto.LevelThresholdDTO, 0, limit)
+	for level := 1; level <= limit; level++ {
+		preview = append(preview, dto.LevelThresholdDTO{Level: level, XP: curve.Threshold(level)})
+	}
+	return &dto.LevelingCurveDTO{Curve: curve, Preview: preview}
+}
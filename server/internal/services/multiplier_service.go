@@ -0,0 +1,125 @@
+package services
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Suplice/CodeQuest/internal/dto"
+	"github.com/Suplice/CodeQuest/internal/models"
+	"github.com/Suplice/CodeQuest/internal/repositories"
+)
+
+const (
+	// MaxMultiplierFactor caps a single event's factors; stacked events can exceed it.
+	MaxMultiplierFactor = 10
+	// multiplierLookahead is how far ahead GetCurrentEvents announces upcoming events.
+	multiplierLookahead = 7 * 24 * time.Hour
+)
+
+var (
+	multiplierDifficulties = []string{models.DifficultyEasy, models.DifficultyMedium, models.DifficultyHard}
+	multiplierTaskTypes    = []string{models.TaskTypeQuiz, models.TaskTypeFillBlank, models.TaskTypeCode}
+)
+
+// MultiplierService schedules multiplier events. The factors are applied when a completed
+// task's rewards are granted, see repositories.TaskRepository.SaveAnswerAttempt.
+type MultiplierService struct {
+	multiplierRepo *repositories.MultiplierRepository
+	logger         *slog.Logger
+}
+
+func NewMultiplierService(_multiplierRepo *repositories.MultiplierRepository, _logger *slog.Logger) *MultiplierService {
+	return &MultiplierService{multiplierRepo: _multiplierRepo, logger: _logger}
+}
+
+func (ms *MultiplierService) GetEvents() ([]models.MultiplierEvent, error) {
+	return ms.multiplierRepo.GetEvents()
+}
+
+// GetCurrentEvents returns the running events and those starting within the next week.
+func (ms *MultiplierService) GetCurrentEvents() ([]models.MultiplierEvent, error) {
+	now := time.Now()
+	return ms.multiplierRepo.GetCurrentEvents(now, now.Add(multiplierLookahead))
+}
+
+func (ms *MultiplierService) CreateEvent(data dto.MultiplierEventUpsertDTO) (*models.MultiplierEvent, error) {
+	event, err := buildMultiplierEvent(data)
+	if err != nil {
+		return nil, err
+	}
+	if err := ms.multiplierRepo.CreateEvent(event); err != nil {
+		return nil, err
+	}
+	ms.logger.Info("Multiplier event scheduled", "eventID", event.ID, "name", event.Name, "startsAt", event.StartsAt, "endsAt", event.EndsAt)
+	return event, nil
+}
+
+// UpdateEvent reschedules an event. Rewards already granted keep the factors they were
+// granted with.
+func (ms *MultiplierService) UpdateEvent(eventID uint, data dto.MultiplierEventUpsertDTO) (*models.MultiplierEvent, error) {
+	if _, err := ms.multiplierRepo.GetEventByID(eventID); err != nil {
+		return nil, err
+	}
+
+	event, err := buildMultiplierEvent(data)
+	if err != nil {
+		return nil, err
+	}
+	event.ID = eventID
+	if err := ms.multiplierRepo.UpdateEvent(event); err != nil {
+		return nil, err
+	}
+	return ms.multiplierRepo.GetEventByID(eventID)
+}
+
+func (ms *MultiplierService) DeleteEvent(eventID uint) error {
+	return ms.multiplierRepo.DeleteEvent(eventID)
+}
+
+func buildMultiplierEvent(data dto.MultiplierEventUpsertDTO) (*models.MultiplierEvent, error) {
+	name := strings.TrimSpace(data.Name)
+	if name == "" {
+		return nil, fmt.Errorf("%w: name is required", ErrValidation)
+	}
+	if !data.EndsAt.After(data.StartsAt) {
+		return nil, fmt.Errorf("%w: ends_at must be after starts_at", ErrValidation)
+	}
+
+	difficulty := strings.ToUpper(strings.TrimSpace(data.Difficulty))
+	if difficulty != "" && !slices.Contains(multiplierDifficulties, difficulty) {
+		return nil, fmt.Errorf("%w: difficulty must be one of %s", ErrValidation, strings.Join(multiplierDifficulties, ", "))
+	}
+	taskType := strings.ToUpper(strings.TrimSpace(data.TaskType))
+	if taskType != "" && !slices.Contains(multiplierTaskTypes, taskType) {
+		return nil, fmt.Errorf("%w: task_type must be one of %s", ErrValidation, strings.Join(multiplierTaskTypes, ", "))
+	}
+
+	event := &models.MultiplierEvent{
+		Name:         name,
+		StartsAt:     data.StartsAt.UTC(),
+		EndsAt:       data.EndsAt.UTC(),
+		Language:     strings.TrimSpace(data.Language),
+		Difficulty:   difficulty,
+		TaskType:     taskType,
+		XPFactor:     1,
+		PointsFactor: 1,
+	}
+	if data.XPFactor != nil {
+		event.XPFactor = *data.XPFactor
+	}
+	if data.PointsFactor != nil {
+		event.PointsFactor = *data.PointsFactor
+	}
+	for _, factor := range []float64{event.XPFactor, event.PointsFactor} {
+		if factor <= 0 || factor > MaxMultiplierFactor {
+			return nil, fmt.Errorf("%w: factors must be greater than 0 and at most %d", ErrValidation, MaxMultiplierFactor)
+		}
+	}
+	if event.XPFactor == 1 && event.PointsFactor == 1 {
+		return nil, fmt.Errorf("%w: xp_factor or points_factor must differ from 1", ErrValidation)
+	}
+	return event, nil
+}
@@ -0,0 +1,26 @@
+package services
+
+import (
+	"log/slog"
+
+	"github.com/Suplice/CodeQuest/internal/models"
+	"github.com/Suplice/CodeQuest/internal/repositories"
+)
+
+const maxTransactionsPageSize = 100
+
+type PointService struct {
+	pointRepository *repositories.PointRepository
+	logger          *slog.Logger
+}
+
+func NewPointService(_pointRepository *repositories.PointRepository, _logger *slog.Logger) *PointService {
+	return &PointService{pointRepository: _pointRepository, logger: _logger}
+}
+
+func (ps *PointService) GetTransactions(userID uint, limit, offset int) ([]models.PointTransaction, error) {
+	if limit <= 0 || limit > maxTransactionsPageSize {
+		limit = maxTransactionsPageSize
+	}
+	return ps.pointRepository.GetTransactions(userID, limit, max(offset, 0))
+}
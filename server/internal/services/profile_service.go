@@ -9,13 +9,25 @@ import (
 
 type ProfileService struct {
 	profileRepository *repositories.ProfileRepository
+	badgeService      *BadgeService
+	levelingService   *LevelingService
 	logger            *slog.Logger
 }
 
-func NewProfileService(_logger *slog.Logger, _pr *repositories.ProfileRepository) *ProfileService {
-	return &ProfileService{profileRepository: _pr,  logger: _logger}
+func NewProfileService(_logger *slog.Logger, _pr *repositories.ProfileRepository, _badgeService *BadgeService, _levelingService *LevelingService) *ProfileService {
+	return &ProfileService{profileRepository: _pr, badgeService: _badgeService, levelingService: _levelingService, logger: _logger}
 }
 
 func (ps *ProfileService) GetProfile(profileUserID, currentUserID uint) (*dto.ProfileDTO, error) {
-	return ps.profileRepository.GetProfileData(profileUserID, currentUserID)
+	profile, err := ps.profileRepository.GetProfileData(profileUserID, currentUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	profile.Badges, err = ps.badgeService.GetEarnedBadges(profileUserID)
+	if err != nil {
+		return nil, err
+	}
+	ps.levelingService.Annotate(&profile.User)
+	return profile, nil
 }
\ No newline at end of file
@@ -0,0 +1,194 @@
+package services
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/Suplice/CodeQuest/internal/dto"
+	"github.com/Suplice/CodeQuest/internal/events"
+	"github.com/Suplice/CodeQuest/internal/models"
+	"github.com/Suplice/CodeQuest/internal/quests"
+	"github.com/Suplice/CodeQuest/internal/repositories"
+	"github.com/Suplice/CodeQuest/internal/streak"
+)
+
+// QuestService assigns daily and weekly quests and advances them from answer and completion
+// events. Quests are assigned the first time they are needed in a period, either when the user
+// opens them or when their activity would count toward them.
+type QuestService struct {
+	questRepo *repositories.QuestRepository
+	logger    *slog.Logger
+}
+
+func NewQuestService(_questRepo *repositories.QuestRepository, _logger *slog.Logger) *QuestService {
+	return &QuestService{questRepo: _questRepo, logger: _logger}
+}
+
+// questDate stores a local period start as its calendar date.
+func questDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// current returns the user's quests for the day and week containing at, assigning them when
+// the user has none yet, along with the local start of both periods.
+func (qs *QuestService) current(userID uint, at time.Time) ([]models.UserQuest, time.Time, time.Time, error) {
+	timezone, err := qs.questRepo.GetUserTimezone(userID)
+	if err != nil {
+		return nil, time.Time{}, time.Time{}, err
+	}
+	loc := streak.Location(timezone)
+	dayStart := quests.PeriodStart(quests.PeriodDaily, at, loc)
+	weekStart := quests.PeriodStart(quests.PeriodWeekly, at, loc)
+
+	assigned, err := qs.questRepo.GetQuests(userID, questDate(dayStart), questDate(weekStart))
+	if err != nil {
+		return nil, time.Time{}, time.Time{}, err
+	}
+
+	counts := make(map[string]int)
+	for _, q := range assigned {
+		counts[q.Period]++
+	}
+	var missing []models.UserQuest
+	for period, start := range map[string]time.Time{quests.PeriodDaily: dayStart, quests.PeriodWeekly: weekStart} {
+		if counts[period] > 0 {
+			continue
+		}
+		for _, t := range quests.Pick(userID, period, start) {
+			missing = append(missing, models.UserQuest{
+				UserID:       userID,
+				Slug:         t.Slug,
+				Period:       t.Period,
+				PeriodStart:  questDate(start),
+				Title:        t.Title,
+				Metric:       t.Metric,
+				Difficulty:   t.Difficulty,
+				Language:     t.Language,
+				Target:       t.Target,
+				RewardXP:     t.RewardXP,
+				RewardPoints: t.RewardPoints,
+			})
+		}
+	}
+	if len(missing) == 0 {
+		return assigned, dayStart, weekStart, nil
+	}
+
+	if err := qs.questRepo.AssignQuests(missing); err != nil {
+		return nil, time.Time{}, time.Time{}, err
+	}
+	assigned, err = qs.questRepo.GetQuests(userID, questDate(dayStart), questDate(weekStart))
+	if err != nil {
+		return nil, time.Time{}, time.Time{}, err
+	}
+	return assigned, dayStart, weekStart, nil
+}
+
+// GetQuests returns the caller's quests for the current day and week with their progress.
+func (qs *QuestService) GetQuests(userID uint) (*dto.QuestsDTO, error) {
+	assigned, dayStart, weekStart, err := qs.current(userID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	result := &dto.QuestsDTO{
+		Daily:  dto.QuestPeriodDTO{Start: dayStart, ResetsAt: quests.PeriodEnd(quests.PeriodDaily, dayStart), Quests: []dto.QuestDTO{}},
+		Weekly: dto.QuestPeriodDTO{Start: weekStart, ResetsAt: quests.PeriodEnd(quests.PeriodWeekly, weekStart), Quests: []dto.QuestDTO{}},
+	}
+	for _, q := range assigned {
+		quest := dto.QuestDTO{
+			ID:           q.ID,
+			Slug:         q.Slug,
+			Title:        q.Title,
+			Difficulty:   q.Difficulty,
+			Language:     q.Language,
+			Progress:     q.Progress,
+			Target:       q.Target,
+			RewardXP:     q.RewardXP,
+			RewardPoints: q.RewardPoints,
+			Completed:    q.CompletedAt != nil,
+			CompletedAt:  q.CompletedAt,
+		}
+		if q.Period == quests.PeriodWeekly {
+			result.Weekly.Quests = append(result.Weekly.Quests, quest)
+		} else {
+			result.Daily.Quests = append(result.Daily.Quests, quest)
+		}
+	}
+	return result, nil
+}
+
+// activity describes what an event contributes to quests, or reports false when it cannot
+// count toward any.
+func (qs *QuestService) activity(event events.Event) (quests.Activity, bool, error) {
+	switch event.Type {
+	case events.AnswerSubmitted:
+		// Answering a solved question again is not progress.
+		first, _ := event.Payload["first_correct"].(bool)
+		return quests.Activity{CorrectAnswers: 1}, first, nil
+	case events.TaskCompleted:
+		facts, err := qs.questRepo.GetTaskFacts(event.UserID, event.TaskID)
+		if err != nil {
+			return quests.Activity{}, false, err
+		}
+		return quests.Activity{
+			TaskCompleted: true,
+			Perfect:       facts.Mistakes == 0,
+			XP:            event.XP,
+			Difficulty:    facts.Difficulty,
+			Language:      facts.Language,
+		}, true, nil
+	}
+	return quests.Activity{}, false, nil
+}
+
+// HandleEvent advances the user's quests from correct answers and completed tasks. Every quest
+// it completes emits a QuestCompleted event, followed by LevelUp when the rewards are enough to
+// reach a new level.
+func (qs *QuestService) HandleEvent(event events.Event) ([]events.Event, error) {
+	a, ok, err := qs.activity(event)
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	assigned, _, _, err := qs.current(event.UserID, event.OccurredAt)
+	if err != nil {
+		return nil, err
+	}
+	increments := make(map[uint]int)
+	for _, q := range assigned {
+		if q.CompletedAt != nil {
+			continue
+		}
+		if by := quests.Increment(q.Metric, q.Difficulty, q.Language, a); by > 0 {
+			increments[q.ID] = by
+		}
+	}
+	if len(increments) == 0 {
+		return nil, nil
+	}
+
+	result, err := qs.questRepo.AdvanceQuests(event.UserID, increments, event.OccurredAt)
+	if err != nil || result.User == nil {
+		return nil, err
+	}
+
+	var followUps []events.Event
+	for _, q := range result.Completed {
+		followUps = append(followUps, events.Event{
+			Type:    events.QuestCompleted,
+			UserID:  event.UserID,
+			XP:      q.RewardXP,
+			Points:  q.RewardPoints,
+			Payload: map[string]any{"slug": q.Slug, "title": q.Title, "period": q.Period},
+		})
+	}
+	if result.User.Level > result.PreviousLevel {
+		followUps = append(followUps, events.Event{
+			Type:    events.LevelUp,
+			UserID:  event.UserID,
+			Payload: map[string]any{"previous_level": result.PreviousLevel, "level": result.User.Level},
+		})
+	}
+	return followUps, nil
+}
@@ -0,0 +1,34 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/Suplice/CodeQuest/internal/events"
+)
+
+func TestQuestActivityCountsFirstCorrectAnswersOnly(t *testing.T) {
+	qs := &QuestService{}
+	tests := []struct {
+		name    string
+		payload map[string]any
+		want    bool
+	}{
+		{"wrong answer", map[string]any{"correct": false, "first_correct": false}, false},
+		{"first correct answer", map[string]any{"correct": true, "first_correct": true}, true},
+		{"repeated correct answer", map[string]any{"correct": true, "first_correct": false}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, ok, err := qs.activity(events.Event{Type: events.AnswerSubmitted, UserID: 1, Payload: tt.payload})
+			if err != nil {
+				t.Fatalf("activity: %v", err)
+			}
+			if ok != tt.want {
+				t.Errorf("counts = %v, want %v", ok, tt.want)
+			}
+			if ok && a.CorrectAnswers != 1 {
+				t.Errorf("CorrectAnswers = %d, want 1", a.CorrectAnswers)
+			}
+		})
+	}
+}
@@ -0,0 +1,236 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/Suplice/CodeQuest/internal/events"
+	"github.com/Suplice/CodeQuest/internal/ranking"
+	"github.com/Suplice/CodeQuest/internal/repositories"
+)
+
+const (
+	// RankingRebuildInterval is how often cached boards are rebuilt from the database. Boards
+	// expire after a few missed rebuilds, so a stopped rebuilder falls back to SQL.
+	RankingRebuildInterval = 10 * time.Minute
+	rankingBoardTTL        = 3 * RankingRebuildInterval
+	rankingStoreTimeout    = 2 * time.Second
+)
+
+// CachedCriteria are the criteria kept in the ranking store, for the all-time, week and month
+// periods without a language or friends scope. Everything else is ranked in SQL.
+var CachedCriteria = []string{"level", "xp", "points", "completed"}
+
+var cachedPeriods = []string{PeriodAll, PeriodWeek, PeriodMonth}
+
+// RankingService serves the most requested leaderboards from a ranking.Store. Boards are
+// rebuilt from the database by Rebuild and kept current in between by HandleEvent. Users sharing
+// a score get the same rank as in SQL, but their order within the tie may differ.
+type RankingService struct {
+	store           ranking.Store
+	leaderboardRepo *repositories.LeaderboardRepository
+	logger          *slog.Logger
+}
+
+func NewRankingService(_store ranking.Store, _leaderboardRepo *repositories.LeaderboardRepository, _logger *slog.Logger) *RankingService {
+	return &RankingService{store: _store, leaderboardRepo: _leaderboardRepo, logger: _logger}
+}
+
+// Cached reports whether a leaderboard query can be answered from the store.
+func (rs *RankingService) Cached(criteria, filter, period, language string) bool {
+	return filter != "friends" && language == "" &&
+		slices.Contains(CachedCriteria, criteria) && slices.Contains(cachedPeriods, period)
+}
+
+// boardKey names the board of a criteria for the period containing at, e.g. "xp:week:2026-10-19".
+func boardKey(criteria, period string, at time.Time) string {
+	if period == PeriodAll {
+		return criteria + ":" + PeriodAll
+	}
+	from, _ := periodWindow(period, at)
+	return criteria + ":" + period + ":" + from.Format("2006-01-02")
+}
+
+// Page returns a page of a cached leaderboard. ok is false when the board has not been built,
+// in which case the caller should rank in SQL.
+func (rs *RankingService) Page(ctx context.Context, criteria, period string, limit, offset int) ([]repositories.RankingRow, bool, error) {
+	board := boardKey(criteria, period, time.Now())
+	exists, err := rs.store.Exists(ctx, board)
+	if err != nil || !exists {
+		return nil, false, err
+	}
+
+	entries, err := rs.store.Range(ctx, board, offset, limit)
+	if err != nil {
+		return nil, false, err
+	}
+	rows, err := rs.rows(ctx, board, entries, offset)
+	if err != nil {
+		return nil, false, err
+	}
+	return rows, true, nil
+}
+
+// Around returns the user's row on a cached leaderboard with up to n rows above and below.
+// ok is false when the board has not been built or the user is not on it yet.
+func (rs *RankingService) Around(ctx context.Context, criteria, period string, userID uint, n int) ([]repositories.RankingRow, bool, error) {
+	board := boardKey(criteria, period, time.Now())
+	position, _, found, err := rs.store.Position(ctx, board, userID)
+	if err != nil || !found {
+		return nil, false, err
+	}
+
+	start := max(position-n, 0)
+	entries, err := rs.store.Range(ctx, board, start, position-start+n+1)
+	if err != nil {
+		return nil, false, err
+	}
+	rows, err := rs.rows(ctx, board, entries, start)
+	if err != nil {
+		return nil, false, err
+	}
+	return rows, true, nil
+}
+
+// rows ranks entries read from position offset and fills in the user columns. Only the first
+// rank needs a lookup: after it, a score equal to the previous one shares its rank and any
+// other score is ranked by its position.
+func (rs *RankingService) rows(ctx context.Context, board string, entries []ranking.Entry, offset int) ([]repositories.RankingRow, error) {
+	if len(entries) == 0 {
+		return []repositories.RankingRow{}, nil
+	}
+
+	total, err := rs.store.Count(ctx, board)
+	if err != nil {
+		return nil, err
+	}
+	above, err := rs.store.CountAbove(ctx, board, entries[0].Score)
+	if err != nil {
+		return nil, err
+	}
+
+	userIDs := make([]uint, len(entries))
+	for i, e := range entries {
+		userIDs[i] = e.UserID
+	}
+	users, err := rs.leaderboardRepo.GetUsersByIDs(userIDs)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uint]int, len(users))
+	for i, u := range users {
+		byID[u.ID] = i
+	}
+
+	rows := make([]repositories.RankingRow, 0, len(entries))
+	rank := above + 1
+	for i, e := range entries {
+		if i > 0 && e.Score != entries[i-1].Score {
+			rank = offset + i + 1
+		}
+		row := repositories.RankingRow{UserID: e.UserID, Value: int(e.Score), Rank: rank, Position: offset + i + 1, Total: total}
+		if j, ok := byID[e.UserID]; ok {
+			row.Username, row.AvatarURL, row.Level, row.Points = users[j].Username, users[j].AvatarURL, users[j].Level, users[j].Points
+			row.AvatarFrame = users[j].AvatarFrame
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+type boardUpdate struct {
+	board string
+	set   bool
+	value float64
+}
+
+// RankingEvents are the events that change a cached board.
+var RankingEvents = []events.Type{events.TaskCompleted, events.QuestCompleted, events.PointsChanged}
+
+// HandleEvent applies task and quest rewards and other point changes to the cached boards.
+// Boards that have not been built are left alone; the next rebuild includes the change.
+func (rs *RankingService) HandleEvent(event events.Event) ([]events.Event, error) {
+	if !slices.Contains(RankingEvents, event.Type) {
+		return nil, nil
+	}
+
+	users, err := rs.leaderboardRepo.GetUsersByIDs([]uint{event.UserID})
+	if err != nil || len(users) == 0 {
+		return nil, err
+	}
+	user := users[0]
+
+	ctx, cancel := context.WithTimeout(context.Background(), rankingStoreTimeout)
+	defer cancel()
+
+	// All-time totals are set from the user row rather than incremented, so a missed event
+	// does not leave the score off for good. Windowed boards only count rewards, like the
+	// ledger they are rebuilt from.
+	at := event.OccurredAt
+	updates := []boardUpdate{{boardKey("points", PeriodAll, at), true, float64(user.Points)}}
+	if event.Type != events.PointsChanged {
+		updates = append(updates,
+			boardUpdate{boardKey("level", PeriodAll, at), true, float64(user.Level)},
+			boardUpdate{boardKey("xp", PeriodAll, at), true, float64(user.XP)},
+		)
+		for _, period := range []string{PeriodWeek, PeriodMonth} {
+			updates = append(updates,
+				boardUpdate{boardKey("level", period, at), false, float64(event.XP)},
+				boardUpdate{boardKey("xp", period, at), false, float64(event.XP)},
+				boardUpdate{boardKey("points", period, at), false, float64(event.Points)},
+			)
+		}
+	}
+	if event.Type == events.TaskCompleted {
+		for _, period := range cachedPeriods {
+			updates = append(updates, boardUpdate{boardKey("completed", period, at), false, 1})
+		}
+	}
+
+	for _, u := range updates {
+		switch {
+		case u.set:
+			err = rs.store.Set(ctx, u.board, event.UserID, u.value)
+		case u.value != 0:
+			err = rs.store.Incr(ctx, u.board, event.UserID, u.value)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return nil, nil
+}
+
+// Rebuild recomputes every cached board from the database and returns how many it replaced.
+// Rewards committed while a board is being rebuilt may be missed until the next rebuild.
+func (rs *RankingService) Rebuild(ctx context.Context) (int, error) {
+	now := time.Now()
+	rebuilt := 0
+	for _, criteria := range CachedCriteria {
+		for _, period := range cachedPeriods {
+			var scope repositories.RankingScope
+			if period != PeriodAll {
+				from, to := periodWindow(period, now)
+				scope.Window = &repositories.RankingWindow{From: from, To: to}
+			}
+
+			rows, err := rs.leaderboardRepo.GetRanking(criteria, scope, 0, 0)
+			if err != nil {
+				return rebuilt, err
+			}
+			scores := make(map[uint]float64, len(rows))
+			for _, r := range rows {
+				scores[r.UserID] = float64(r.Value)
+			}
+
+			if err := rs.store.Replace(ctx, boardKey(criteria, period, now), scores, rankingBoardTTL); err != nil {
+				return rebuilt, err
+			}
+			rebuilt++
+		}
+	}
+	rs.logger.Info("Rebuilt ranking boards", "boards", rebuilt)
+	return rebuilt, nil
+}
@@ -0,0 +1,148 @@
+package services
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Suplice/CodeQuest/internal/events"
+	"github.com/Suplice/CodeQuest/internal/models"
+	"github.com/Suplice/CodeQuest/internal/ranking"
+	"github.com/Suplice/CodeQuest/internal/repositories"
+	"github.com/Suplice/CodeQuest/internal/testdb"
+	"gorm.io/gorm"
+)
+
+func TestRankingServiceCached(t *testing.T) {
+	rs := &RankingService{}
+	tests := []struct {
+		criteria, filter, period, language string
+		want                               bool
+	}{
+		{"xp", "all", PeriodAll, "", true},
+		{"points", "all", PeriodWeek, "", true},
+		{"completed", "all", PeriodMonth, "", true},
+		{"xp", "friends", PeriodAll, "", false},
+		{"xp", "all", PeriodAll, "go", false},
+		{"xp", "all", PeriodSeason, "", false},
+		{"streak", "all", PeriodAll, "", false},
+	}
+	for _, tt := range tests {
+		if got := rs.Cached(tt.criteria, tt.filter, tt.period, tt.language); got != tt.want {
+			t.Errorf("Cached(%q, %q, %q, %q) = %v, want %v", tt.criteria, tt.filter, tt.period, tt.language, got, tt.want)
+		}
+	}
+}
+
+func assertBoard(t *testing.T, rs *RankingService, criteria, period string, want map[uint]int) {
+	t.Helper()
+
+	rows, ok, err := rs.Page(context.Background(), criteria, period, 100, 0)
+	if err != nil {
+		t.Fatalf("Page(%s, %s): %v", criteria, period, err)
+	}
+	if !ok {
+		t.Fatalf("Page(%s, %s): board was not built", criteria, period)
+	}
+	got := make(map[uint]int, len(rows))
+	for _, r := range rows {
+		got[r.UserID] = r.Value
+	}
+	for userID, value := range want {
+		if got[userID] != value {
+			t.Errorf("%s/%s: user %d has %d, want %d", criteria, period, userID, got[userID], value)
+		}
+	}
+}
+
+func setBalance(t *testing.T, db *gorm.DB, userID uint, xp, points int) {
+	t.Helper()
+	if err := db.Model(&models.User{}).Where("id = ?", userID).Updates(map[string]any{"xp": xp, "points": points}).Error; err != nil {
+		t.Fatalf("update user %d: %v", userID, err)
+	}
+}
+
+func TestRankingServiceHandleEvent(t *testing.T) {
+	db := testdb.Open(t)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	rs := NewRankingService(ranking.NewMemoryStore(), repositories.NewLeaderboardRepository(db, logger), logger)
+
+	alice := testdb.CreateUser(t, db, "alice")
+	bob := testdb.CreateUser(t, db, "bob")
+	setBalance(t, db, alice.ID, 100, 100)
+	setBalance(t, db, bob.ID, 40, 40)
+	// Windowed boards are only built when someone earned a reward in the window.
+	if err := db.Create(&models.PointTransaction{UserID: alice.ID, Kind: models.PointTransactionTaskReward, XP: 100, Points: 100}).Error; err != nil {
+		t.Fatalf("create ledger entry: %v", err)
+	}
+
+	if _, err := rs.Rebuild(context.Background()); err != nil {
+		t.Fatalf("Rebuild: %v", err)
+	}
+	assertBoard(t, rs, "points", PeriodAll, map[uint]int{alice.ID: 100, bob.ID: 40})
+	assertBoard(t, rs, "xp", PeriodWeek, map[uint]int{alice.ID: 100})
+	assertBoard(t, rs, "completed", PeriodWeek, map[uint]int{alice.ID: 1})
+
+	now := time.Now()
+	steps := []struct {
+		name string
+		// The user's balance after the change the event reports.
+		xp, points int
+		event      events.Event
+		want       map[string]map[uint]int
+	}{
+		{
+			name:   "shop purchase only moves all-time points",
+			event:  events.Event{Type: events.PointsChanged, UserID: alice.ID, OccurredAt: now},
+			xp:     100,
+			points: 30,
+			want: map[string]map[uint]int{
+				"points/" + PeriodAll:  {alice.ID: 30},
+				"points/" + PeriodWeek: {alice.ID: 100},
+				"xp/" + PeriodAll:      {alice.ID: 100},
+			},
+		},
+		{
+			name:   "quest reward counts toward XP and points but not completions",
+			event:  events.Event{Type: events.QuestCompleted, UserID: bob.ID, XP: 50, Points: 20, OccurredAt: now},
+			xp:     90,
+			points: 60,
+			want: map[string]map[uint]int{
+				"xp/" + PeriodAll:          {bob.ID: 90},
+				"xp/" + PeriodWeek:         {bob.ID: 50},
+				"points/" + PeriodMonth:    {bob.ID: 20},
+				"completed/" + PeriodWeek:  {bob.ID: 0},
+				"completed/" + PeriodMonth: {alice.ID: 1},
+			},
+		},
+		{
+			name:   "task reward counts everywhere",
+			event:  events.Event{Type: events.TaskCompleted, UserID: bob.ID, XP: 10, Points: 10, OccurredAt: now},
+			xp:     100,
+			points: 70,
+			want: map[string]map[uint]int{
+				"xp/" + PeriodAll:          {bob.ID: 100},
+				"xp/" + PeriodWeek:         {bob.ID: 60},
+				"points/" + PeriodAll:      {bob.ID: 70},
+				"completed/" + PeriodAll:   {bob.ID: 1},
+				"completed/" + PeriodMonth: {bob.ID: 1},
+			},
+		},
+	}
+
+	for _, step := range steps {
+		t.Run(step.name, func(t *testing.T) {
+			setBalance(t, db, step.event.UserID, step.xp, step.points)
+			if _, err := rs.HandleEvent(step.event); err != nil {
+				t.Fatalf("HandleEvent: %v", err)
+			}
+			for board, want := range step.want {
+				criteria, period, _ := strings.Cut(board, "/")
+				assertBoard(t, rs, criteria, period, want)
+			}
+		})
+	}
+}
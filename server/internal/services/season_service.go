@@ -0,0 +1,139 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/Suplice/CodeQuest/internal/dto"
+	"github.com/Suplice/CodeQuest/internal/models"
+	"github.com/Suplice/CodeQuest/internal/repositories"
+)
+
+// SeasonCriteria are the leaderboards whose final standings are archived per season.
+var SeasonCriteria = []string{"level", "points", "completed"}
+
+type SeasonService struct {
+	seasonRepo      *repositories.SeasonRepository
+	leaderboardRepo *repositories.LeaderboardRepository
+	logger          *slog.Logger
+}
+
+func NewSeasonService(_seasonRepo *repositories.SeasonRepository, _leaderboardRepo *repositories.LeaderboardRepository, _logger *slog.Logger) *SeasonService {
+	return &SeasonService{seasonRepo: _seasonRepo, leaderboardRepo: _leaderboardRepo, logger: _logger}
+}
+
+func (ss *SeasonService) GetSeasons() ([]models.Season, error) {
+	return ss.seasonRepo.GetSeasons()
+}
+
+func (ss *SeasonService) CreateSeason(data dto.SeasonUpsertDTO) (*models.Season, error) {
+	season, err := buildSeason(data)
+	if err != nil {
+		return nil, err
+	}
+	if err := ss.ensureNoOverlap(season); err != nil {
+		return nil, err
+	}
+
+	if err := ss.seasonRepo.CreateSeason(season); err != nil {
+		return nil, err
+	}
+	return season, nil
+}
+
+func (ss *SeasonService) UpdateSeason(seasonID uint, data dto.SeasonUpsertDTO) (*models.Season, error) {
+	existing, err := ss.seasonRepo.GetSeasonByID(seasonID)
+	if err != nil {
+		return nil, err
+	}
+	if existing.ArchivedAt != nil {
+		return nil, fmt.Errorf("%w: season has already been archived", ErrValidation)
+	}
+
+	season, err := buildSeason(data)
+	if err != nil {
+		return nil, err
+	}
+	season.ID = seasonID
+	if err := ss.ensureNoOverlap(season); err != nil {
+		return nil, err
+	}
+
+	if err := ss.seasonRepo.UpdateSeason(season); err != nil {
+		return nil, err
+	}
+	return ss.seasonRepo.GetSeasonByID(seasonID)
+}
+
+func (ss *SeasonService) DeleteSeason(seasonID uint) error {
+	return ss.seasonRepo.DeleteSeason(seasonID)
+}
+
+func (ss *SeasonService) ensureNoOverlap(season *models.Season) error {
+	overlaps, err := ss.seasonRepo.SeasonOverlaps(season.StartsAt, season.EndsAt, season.ID)
+	if err != nil {
+		return err
+	}
+	if overlaps {
+		return fmt.Errorf("%w: season overlaps another season", ErrValidation)
+	}
+	return nil
+}
+
+func buildSeason(data dto.SeasonUpsertDTO) (*models.Season, error) {
+	name := strings.TrimSpace(data.Name)
+	if name == "" {
+		return nil, fmt.Errorf("%w: name is required", ErrValidation)
+	}
+	if !data.EndsAt.After(data.StartsAt) {
+		return nil, fmt.Errorf("%w: ends_at must be after starts_at", ErrValidation)
+	}
+	return &models.Season{Name: name, StartsAt: data.StartsAt.UTC(), EndsAt: data.EndsAt.UTC()}, nil
+}
+
+// ArchiveEndedSeasons stores the final standings of every season that has ended and returns
+// how many were archived.
+func (ss *SeasonService) ArchiveEndedSeasons(ctx context.Context) (int, error) {
+	seasons, err := ss.seasonRepo.GetSeasonsToArchive(time.Now())
+	if err != nil {
+		return 0, err
+	}
+
+	archived := 0
+	for _, season := range seasons {
+		if err := ctx.Err(); err != nil {
+			return archived, err
+		}
+
+		window := &repositories.RankingWindow{From: season.StartsAt, To: season.EndsAt}
+		var standings []models.SeasonStanding
+		for _, criteria := range SeasonCriteria {
+			rows, err := ss.leaderboardRepo.GetRanking(criteria, repositories.RankingScope{Window: window}, 0, 0)
+			if err != nil {
+				return archived, err
+			}
+			for _, r := range rows {
+				standings = append(standings, models.SeasonStanding{
+					SeasonID: season.ID,
+					Criteria: criteria,
+					UserID:   r.UserID,
+					Rank:     r.Rank,
+					Value:    r.Value,
+				})
+			}
+		}
+
+		ok, err := ss.seasonRepo.ArchiveSeason(season.ID, standings)
+		if err != nil {
+			return archived, err
+		}
+		if ok {
+			archived++
+			ss.logger.Info("Archived season standings", "seasonID", season.ID, "name", season.Name, "standings", len(standings))
+		}
+	}
+	return archived, nil
+}
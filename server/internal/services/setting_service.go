@@ -32,6 +32,12 @@ func (ss *SettingService) UpdateSettingsForUser(userId string, settings []dto.Us
             return errors.New(constants.ErrSettingsSameKeys)
         }
         keySet[setting.SettingKey] = true
+
+        if setting.SettingKey == constants.SettingActivityVisibility &&
+            setting.SettingValue != constants.ActivityVisibilityFriends &&
+            setting.SettingValue != constants.ActivityVisibilityPrivate {
+            return errors.New(constants.ErrInvalidSettingValue)
+        }
     }
 
 
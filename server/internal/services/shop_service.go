@@ -0,0 +1,252 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/Suplice/CodeQuest/internal/content"
+	"github.com/Suplice/CodeQuest/internal/dto"
+	"github.com/Suplice/CodeQuest/internal/events"
+	"github.com/Suplice/CodeQuest/internal/models"
+	"github.com/Suplice/CodeQuest/internal/repositories"
+	"github.com/Suplice/CodeQuest/internal/streak"
+)
+
+// ErrNoHintAvailable is returned for questions whose type has no hint to give.
+var ErrNoHintAvailable = errors.New("no hint available")
+
+// maxShopQuantity caps how many of a stackable item one purchase can buy.
+const maxShopQuantity = 100
+
+// ShopService sells items for points. Cosmetics are equipped from the inventory and shown on
+// profiles and leaderboards; hint tokens are spent on hints and streak freezes by the streak job.
+type ShopService struct {
+	shopRepo   *repositories.ShopRepository
+	dispatcher *events.Dispatcher
+	logger     *slog.Logger
+}
+
+func NewShopService(_shopRepo *repositories.ShopRepository, _dispatcher *events.Dispatcher, _logger *slog.Logger) *ShopService {
+	return &ShopService{shopRepo: _shopRepo, dispatcher: _dispatcher, logger: _logger}
+}
+
+// GetCatalog returns the items for sale with how many of each the user holds.
+func (ss *ShopService) GetCatalog(userID uint) ([]dto.ShopItemDTO, error) {
+	items, err := ss.shopRepo.GetItems(false)
+	if err != nil {
+		return nil, err
+	}
+	wallet, err := ss.shopRepo.GetWallet(userID)
+	if err != nil {
+		return nil, err
+	}
+	inventory, err := ss.shopRepo.GetInventory(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	owned := make(map[uint]int, len(inventory))
+	for _, i := range inventory {
+		owned[i.ItemID] = i.Quantity
+	}
+	catalog := make([]dto.ShopItemDTO, len(items))
+	for i, item := range items {
+		catalog[i] = dto.ShopItemDTO{ShopItem: item, Owned: owned[item.ID]}
+		if item.Kind == models.ShopItemStreakFreeze {
+			catalog[i].Owned = wallet.StreakFreezes
+		}
+	}
+	return catalog, nil
+}
+
+func (ss *ShopService) GetInventory(userID uint) (*dto.InventoryDTO, error) {
+	wallet, err := ss.shopRepo.GetWallet(userID)
+	if err != nil {
+		return nil, err
+	}
+	items, err := ss.shopRepo.GetInventory(userID)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.InventoryDTO{
+		Points:        wallet.Points,
+		StreakFreezes: wallet.StreakFreezes,
+		AvatarFrame:   wallet.AvatarFrame,
+		ProfileTheme:  wallet.ProfileTheme,
+		Items:         items,
+	}, nil
+}
+
+func (ss *ShopService) Purchase(userID, itemID uint, quantity int) (*dto.ShopPurchaseDTO, error) {
+	if quantity < 1 || quantity > maxShopQuantity {
+		return nil, fmt.Errorf("%w: quantity must be between 1 and %d", ErrValidation, maxShopQuantity)
+	}
+
+	user, err := ss.shopRepo.Purchase(userID, itemID, quantity, streak.MaxFreezes)
+	if err != nil {
+		return nil, err
+	}
+	item, err := ss.shopRepo.GetItemByID(itemID)
+	if err != nil {
+		return nil, err
+	}
+	ss.logger.Info("Shop item purchased", "userID", userID, "itemID", itemID, "quantity", quantity)
+	ss.dispatcher.Dispatch(events.Event{Type: events.PointsChanged, UserID: userID})
+	return &dto.ShopPurchaseDTO{Item: *item, Quantity: quantity, Points: user.Points, StreakFreezes: user.StreakFreezes}, nil
+}
+
+// SetEquipped equips or unequips one of the user's cosmetics and returns the updated inventory.
+func (ss *ShopService) SetEquipped(userID, itemID uint, equipped bool) (*dto.InventoryDTO, error) {
+	if err := ss.shopRepo.SetEquipped(userID, itemID, equipped); err != nil {
+		return nil, err
+	}
+	return ss.GetInventory(userID)
+}
+
+// UseHint returns a hint for the question. The first time the user sees it, it costs one of
+// their hint tokens.
+func (ss *ShopService) UseHint(userID, questionID uint) (*dto.QuestionHintDTO, error) {
+	question, err := ss.shopRepo.GetHintQuestion(questionID)
+	if err != nil {
+		return nil, err
+	}
+
+	hint := &dto.QuestionHintDTO{QuestionID: question.ID}
+
+	switch question.Type {
+	case models.TaskTypeQuiz:
+		var options []string
+		if err := json.Unmarshal(question.Options, &options); err != nil {
+			ss.logger.Error("Could not parse question options", "err", err, "questionID", questionID)
+			return nil, err
+		}
+		for _, option := range options {
+			if option != question.CorrectAnswer {
+				eliminated := option
+				hint.EliminatedOption = &eliminated
+				break
+			}
+		}
+	case models.TaskTypeFillBlank:
+		if answer := []rune(question.CorrectAnswer); len(answer) > 0 {
+			first := string(answer[0])
+			hint.FirstCharacter = &first
+		}
+	}
+
+	if hint.EliminatedOption == nil && hint.FirstCharacter == nil {
+		return nil, ErrNoHintAvailable
+	}
+
+	if err := ss.shopRepo.UnlockHint(userID, question.ID); err != nil {
+		return nil, err
+	}
+	return hint, nil
+}
+
+func (ss *ShopService) GetAllItems() ([]models.ShopItem, error) {
+	return ss.shopRepo.GetItems(true)
+}
+
+func (ss *ShopService) CreateItem(data dto.ShopItemUpsertDTO) (*models.ShopItem, error) {
+	item, err := buildShopItem(data)
+	if err != nil {
+		return nil, err
+	}
+	if err := ss.ensureItemSlugFree(item.Slug, 0); err != nil {
+		return nil, err
+	}
+
+	if err := ss.shopRepo.CreateItem(item); err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+func (ss *ShopService) UpdateItem(itemID uint, data dto.ShopItemUpsertDTO) (*models.ShopItem, error) {
+	existing, err := ss.shopRepo.GetItemByID(itemID)
+	if err != nil {
+		return nil, err
+	}
+
+	item, err := buildShopItem(data)
+	if err != nil {
+		return nil, err
+	}
+	item.ID = itemID
+	if strings.TrimSpace(data.Slug) == "" {
+		item.Slug = existing.Slug
+	}
+	if data.IsActive == nil {
+		item.IsActive = existing.IsActive
+	}
+	if err := ss.ensureItemSlugFree(item.Slug, itemID); err != nil {
+		return nil, err
+	}
+	if item.Kind != existing.Kind {
+		purchased, err := ss.shopRepo.ItemPurchased(itemID)
+		if err != nil {
+			return nil, err
+		}
+		if purchased {
+			return nil, fmt.Errorf("%w: the kind of an item that has been purchased cannot change", ErrValidation)
+		}
+	}
+
+	if err := ss.shopRepo.UpdateItem(item); err != nil {
+		return nil, err
+	}
+	return ss.shopRepo.GetItemByID(itemID)
+}
+
+func (ss *ShopService) DeleteItem(itemID uint) error {
+	return ss.shopRepo.DeleteItem(itemID)
+}
+
+func (ss *ShopService) ensureItemSlugFree(slug string, itemID uint) error {
+	taken, err := ss.shopRepo.ItemSlugTaken(slug, itemID)
+	if err != nil {
+		return err
+	}
+	if taken {
+		return fmt.Errorf("%w: slug %q is already used by another shop item", ErrValidation, slug)
+	}
+	return nil
+}
+
+// buildShopItem validates shop item fields for the admin API. Without an explicit slug one is
+// derived from the name.
+func buildShopItem(data dto.ShopItemUpsertDTO) (*models.ShopItem, error) {
+	slug := strings.TrimSpace(data.Slug)
+	if slug == "" {
+		slug = content.Slugify(data.Name)
+	}
+	if !content.IsValidSlug(slug) {
+		return nil, fmt.Errorf("%w: slug %q may only contain lowercase letters, digits and dashes", ErrValidation, slug)
+	}
+	if !slices.Contains(models.ShopItemKinds, data.Kind) {
+		return nil, fmt.Errorf("%w: kind must be one of %s", ErrValidation, strings.Join(models.ShopItemKinds, ", "))
+	}
+	assetURL := strings.TrimSpace(data.AssetURL)
+	if models.IsCosmeticItem(data.Kind) && assetURL == "" {
+		return nil, fmt.Errorf("%w: cosmetic items need an asset_url", ErrValidation)
+	}
+
+	item := &models.ShopItem{
+		Slug:        slug,
+		Name:        strings.TrimSpace(data.Name),
+		Description: data.Description,
+		Kind:        data.Kind,
+		Price:       data.Price,
+		AssetURL:    assetURL,
+		IsActive:    true,
+	}
+	if data.IsActive != nil {
+		item.IsActive = *data.IsActive
+	}
+	return item, nil
+}
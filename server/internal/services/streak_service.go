@@ -0,0 +1,168 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/Suplice/CodeQuest/internal/dto"
+	"github.com/Suplice/CodeQuest/internal/events"
+	"github.com/Suplice/CodeQuest/internal/models"
+	"github.com/Suplice/CodeQuest/internal/repositories"
+	"github.com/Suplice/CodeQuest/internal/streak"
+)
+
+const streakHistoryLimit = 30
+
+type StreakService struct {
+	streakRepository *repositories.StreakRepository
+	shopRepository   *repositories.ShopRepository
+	dispatcher       *events.Dispatcher
+	logger           *slog.Logger
+}
+
+func NewStreakService(_streakRepository *repositories.StreakRepository, _shopRepository *repositories.ShopRepository, _dispatcher *events.Dispatcher, _logger *slog.Logger) *StreakService {
+	return &StreakService{streakRepository: _streakRepository, shopRepository: _shopRepository, dispatcher: _dispatcher, logger: _logger}
+}
+
+// ResetLapsedStreaks ends the streaks of users who let a whole day pass in their timezone,
+// unless they hold enough freezes to cover the missed days.
+// Local midnight happens at a different moment for every timezone, so it is meant to run
+// hourly rather than once a night.
+func (ss *StreakService) ResetLapsedStreaks(ctx context.Context) (int, error) {
+	now := time.Now()
+	// A lapsed streak was last active before yesterday began, which is at least a day ago.
+	candidates, err := ss.streakRepository.GetStreakCandidates(now.Add(-24 * time.Hour))
+	if err != nil {
+		return 0, err
+	}
+
+	reset, frozen := 0, 0
+	for _, user := range candidates {
+		if err := ctx.Err(); err != nil {
+			return reset, err
+		}
+
+		loc := streak.Location(user.Timezone)
+		if !streak.IsLapsed(user.LastActiveDate, now, loc) {
+			continue
+		}
+
+		// Freezes only help when they cover every missed day.
+		if user.StreakFreezes >= streak.MissedDays(user.LastActiveDate, now, loc) {
+			ok, err := ss.streakRepository.FreezeStreak(user, now, loc)
+			if err != nil {
+				return reset, err
+			}
+			if ok {
+				frozen++
+			}
+			continue
+		}
+
+		ok, err := ss.streakRepository.ResetStreak(user, loc)
+		if err != nil {
+			return reset, err
+		}
+		if ok {
+			reset++
+		}
+	}
+
+	if reset > 0 || frozen > 0 {
+		ss.logger.Info("Processed lapsed streaks", "reset", reset, "frozen", frozen)
+	}
+	return reset, nil
+}
+
+func (ss *StreakService) GetSummary(userID uint) (*dto.StreakSummaryDTO, error) {
+	user, err := ss.streakRepository.GetUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	history, err := ss.streakRepository.GetHistory(userID, streakHistoryLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	loc := streak.Location(user.Timezone)
+	current := user.StreakCount
+	// The job may not have processed a lapsed streak yet; never report it as still running
+	// unless the user's freezes are about to save it.
+	if streak.IsLapsed(user.LastActiveDate, now, loc) && user.StreakFreezes < streak.MissedDays(user.LastActiveDate, now, loc) {
+		current = 0
+	}
+
+	summary := &dto.StreakSummaryDTO{
+		Current:        current,
+		Longest:        max(user.LongestStreak, current),
+		ActiveToday:    current > 0 && streak.DaysBetween(user.LastActiveDate, now, loc) == 0,
+		LastActiveDate: user.LastActiveDate,
+		Timezone:       user.Timezone,
+		Freezes:        user.StreakFreezes,
+		MaxFreezes:     streak.MaxFreezes,
+		History:        history,
+	}
+
+	// Freezes are sold in the shop, so the price is whatever the shop asks.
+	item, err := ss.shopRepository.GetItemForSale(models.ShopItemStreakFreeze)
+	if err != nil && !errors.Is(err, repositories.ErrShopItemNotFound) {
+		return nil, err
+	}
+	if item != nil {
+		summary.FreezePrice = item.Price
+	}
+
+	broken, err := ss.streakRepository.GetRepairableStreak(userID, now.Add(-streak.RepairWindow))
+	if err != nil {
+		return nil, err
+	}
+	if broken != nil {
+		summary.Repair = &dto.StreakRepairDTO{
+			Length:    broken.Length,
+			Price:     streak.RepairPrice,
+			ExpiresAt: broken.BrokenAt.Add(streak.RepairWindow),
+		}
+	}
+	return summary, nil
+}
+
+// BuyFreezes buys the shop's streak freeze item, so both endpoints charge the same price.
+func (ss *StreakService) BuyFreezes(userID uint, quantity int) (*dto.StreakPurchaseDTO, error) {
+	if quantity < 1 {
+		return nil, fmt.Errorf("%w: quantity must be at least 1", ErrValidation)
+	}
+
+	item, err := ss.shopRepository.GetItemForSale(models.ShopItemStreakFreeze)
+	if err != nil {
+		return nil, err
+	}
+	user, err := ss.shopRepository.Purchase(userID, item.ID, quantity, streak.MaxFreezes)
+	if err != nil {
+		return nil, err
+	}
+	ss.dispatcher.Dispatch(events.Event{Type: events.PointsChanged, UserID: userID})
+	return &dto.StreakPurchaseDTO{Points: user.Points, Freezes: user.StreakFreezes, StreakCount: user.StreakCount}, nil
+}
+
+// RepairStreak restores a streak that broke less than streak.RepairWindow ago.
+func (ss *StreakService) RepairStreak(userID uint) (*dto.StreakPurchaseDTO, error) {
+	now := time.Now()
+	user, err := ss.streakRepository.RepairStreak(userID, streak.RepairPrice, now.Add(-streak.RepairWindow), now)
+	if err != nil {
+		return nil, err
+	}
+	ss.logger.Info("Streak repaired", "userID", userID, "streak", user.StreakCount)
+	ss.dispatcher.Dispatch(events.Event{Type: events.PointsChanged, UserID: userID})
+	return &dto.StreakPurchaseDTO{Points: user.Points, Freezes: user.StreakFreezes, StreakCount: user.StreakCount}, nil
+}
+
+func (ss *StreakService) SetTimezone(userID uint, timezone string) error {
+	if !streak.IsValidTimezone(timezone) {
+		return fmt.Errorf("%w: unknown timezone %q", ErrValidation, timezone)
+	}
+	return ss.streakRepository.SetTimezone(userID, timezone)
+}
@@ -1,20 +1,30 @@
 package services
 
 import (
+	"errors"
 	"log/slog"
-	"strings"
 
+	"github.com/Suplice/CodeQuest/internal/dto"
+	"github.com/Suplice/CodeQuest/internal/events"
+	"github.com/Suplice/CodeQuest/internal/grading"
 	"github.com/Suplice/CodeQuest/internal/models"
 	"github.com/Suplice/CodeQuest/internal/repositories"
+	"github.com/Suplice/CodeQuest/internal/streak"
 )
 
+// ErrCodeSubmissionRequired is returned for a plain answer to a question that is graded by
+// running code.
+var ErrCodeSubmissionRequired = errors.New("question requires a code submission")
+
 type TaskService struct {
 	taskRepository *repositories.TaskRepository
+	graders           *grading.Registry
+	dispatcher        *events.Dispatcher
 	logger            *slog.Logger
 }
 
-func NewTaskService(_taskRepository *repositories.TaskRepository, _logger *slog.Logger) *TaskService {
-	return &TaskService{taskRepository: _taskRepository, logger: _logger}
+func NewTaskService(_taskRepository *repositories.TaskRepository, _graders *grading.Registry, _dispatcher *events.Dispatcher, _logger *slog.Logger) *TaskService {
+	return &TaskService{taskRepository: _taskRepository, graders: _graders, dispatcher: _dispatcher, logger: _logger}
 }
 
 func (ts *TaskService) GetAllTasksForUser(userID uint64) ([]repositories.TaskForUser, error) {
@@ -29,29 +39,83 @@ type SubmitAnswerResponse struct {
 	IsCorrect   bool         `json:"is_correct"`
 	IsCompleted bool         `json:"is_completed"`
 	UpdatedUser *models.User `json:"updated_user,omitempty"` 
+	EarnedBadges []dto.EarnedBadgeDTO `json:"earned_badges,omitempty"`
+	LevelUp      *dto.LevelUpDTO      `json:"level_up,omitempty"`
 }
 
 func (ts *TaskService) SubmitAnswer(userID, taskID, questionID uint, answerGiven string) (*SubmitAnswerResponse, error) {
-	correctAnswer, err := ts.taskRepository.GetCorrectAnswer(questionID)
+	question, err := ts.taskRepository.GetQuestionForTask(taskID, questionID)
 	if err != nil {
-		ts.logger.Error("Could not get correct answer", "err", err, "questionID", questionID)
+		ts.logger.Error("Could not get question", "err", err, "questionID", questionID)
 		return nil, err
 	}
 
-	isCorrect := strings.EqualFold(
-		strings.TrimSpace(answerGiven),
-		strings.TrimSpace(correctAnswer),
-	)
+	if question.Type == models.TaskTypeCode {
+		return nil, ErrCodeSubmissionRequired
+	}
 
-	isCompleted, updatedUser, err := ts.taskRepository.SaveAnswerAttempt(userID, taskID, questionID, answerGiven, isCorrect)
+	isCorrect, err := ts.graders.Grade(question.Type, question.GradingConfig, answerGiven, question.CorrectAnswer)
+	if err != nil {
+		ts.logger.Error("Could not grade answer", "err", err, "questionID", questionID)
+		return nil, err
+	}
+
+	result, err := ts.taskRepository.SaveAnswerAttempt(userID, taskID, questionID, answerGiven, isCorrect)
 	if err != nil {
 		ts.logger.Error("Could not save answer attempt", "err", err, "userID", userID, "taskID", taskID)
 		return nil, err
 	}
 
-	return &SubmitAnswerResponse{
+	response := &SubmitAnswerResponse{
 		IsCorrect:   isCorrect,
-		IsCompleted: isCompleted,
-		UpdatedUser: updatedUser, 
-	}, nil
-}
\ No newline at end of file
+		IsCompleted: result.IsCompleted,
+		UpdatedUser: result.User, 
+	}
+	response.EarnedBadges, response.LevelUp = dispatchAnswerEvents(ts.dispatcher, userID, question, isCorrect, result)
+	return response, nil
+}
+
+// dispatchAnswerEvents publishes the events caused by an answer and collects what the client
+// should celebrate. It is shared by quiz answers and code submissions.
+func dispatchAnswerEvents(dispatcher *events.Dispatcher, userID uint, question *models.TaskQuestion, isCorrect bool, result *repositories.AnswerAttemptResult) ([]dto.EarnedBadgeDTO, *dto.LevelUpDTO) {
+	task := &question.Task
+	emitted := dispatcher.Dispatch(events.Event{
+		Type:    events.AnswerSubmitted,
+		UserID:  userID,
+		TaskID:  task.ID,
+		Payload: map[string]any{"question_id": question.ID, "correct": isCorrect, "first_correct": result.FirstCorrect, "title": task.Title},
+	})
+	if !result.IsCompleted {
+		return nil, nil
+	}
+
+	user := result.User
+	emitted = append(emitted, dispatcher.Dispatch(events.Event{
+		Type:    events.TaskCompleted,
+		UserID:  user.ID,
+		TaskID:  task.ID,
+		XP:      result.XP,
+		Points:  result.Points,
+		Payload: map[string]any{"language": task.Language, "title": task.Title},
+	})...)
+
+	if user.StreakCount != result.PreviousStreak && streak.IsMilestone(user.StreakCount) {
+		emitted = append(emitted, dispatcher.Dispatch(events.Event{
+			Type:    events.StreakMilestone,
+			UserID:  user.ID,
+			Payload: map[string]any{"streak": user.StreakCount},
+		})...)
+	}
+
+	var levelUp *dto.LevelUpDTO
+	if user.Level > result.PreviousLevel {
+		levelUp = &dto.LevelUpDTO{PreviousLevel: result.PreviousLevel, Level: user.Level}
+		emitted = append(emitted, dispatcher.Dispatch(events.Event{
+			Type:    events.LevelUp,
+			UserID:  user.ID,
+			Payload: map[string]any{"previous_level": result.PreviousLevel, "level": user.Level},
+		})...)
+	}
+
+	return earnedBadges(emitted), levelUp
+}
@@ -0,0 +1,80 @@
+// Package streak does the calendar arithmetic for daily streaks. Days are counted in the
+// user's own timezone, so finishing a task at 23:30 and 00:30 local time counts as two days
+// no matter where the server runs.
+package streak
+
+import (
+	"slices"
+	"time"
+)
+
+const DefaultTimezone = "UTC"
+
+// Location loads the IANA timezone, falling back to UTC for empty or unknown names.
+func Location(timezone string) *time.Location {
+	if timezone == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+func IsValidTimezone(timezone string) bool {
+	if timezone == "" {
+		return false
+	}
+	_, err := time.LoadLocation(timezone)
+	return err == nil
+}
+
+// Day returns the start of t's calendar day in loc. That is midnight, except where clocks
+// spring forward at midnight and the day starts at the transition instead.
+func Day(t time.Time, loc *time.Location) time.Time {
+	local := t.In(loc)
+	day := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+	if day.Day() != local.Day() {
+		// time.Date resolved the skipped midnight to the evening before.
+		_, day = day.ZoneBounds()
+	}
+	return day
+}
+
+// DaysBetween returns how many calendar days in loc lie between from and to; 0 means the
+// same day and 1 means to is the day after from.
+func DaysBetween(from, to time.Time, loc *time.Location) int {
+	a, b := Day(from, loc), Day(to, loc)
+	// Compare dates through UTC so DST transitions do not produce 23 or 25 hour days.
+	ua := time.Date(a.Year(), a.Month(), a.Day(), 0, 0, 0, 0, time.UTC)
+	ub := time.Date(b.Year(), b.Month(), b.Day(), 0, 0, 0, 0, time.UTC)
+	return int(ub.Sub(ua).Hours() / 24)
+}
+
+// IsLapsed reports whether a streak whose last active moment was lastActive is broken at now,
+// meaning the user let a whole local day pass without activity.
+func IsLapsed(lastActive, now time.Time, loc *time.Location) bool {
+	return !lastActive.IsZero() && DaysBetween(lastActive, now, loc) > 1
+}
+
+// Limits of the streak shop and the repair price, in points. Freezes are priced by their shop
+// item.
+const (
+	MaxFreezes   = 2
+	RepairPrice  = 200
+	RepairWindow = 24 * time.Hour
+)
+
+// MissedDays returns how many whole local days passed without activity between lastActive
+// and now, which is how many freezes it takes to keep the streak alive.
+func MissedDays(lastActive, now time.Time, loc *time.Location) int {
+	return max(DaysBetween(lastActive, now, loc)-1, 0)
+}
+
+// Milestones are the streak lengths worth celebrating.
+var Milestones = []int{3, 7, 14, 30, 50, 100, 200, 365}
+
+func IsMilestone(days int) bool {
+	return slices.Contains(Milestones, days)
+}
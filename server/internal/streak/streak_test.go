@@ -0,0 +1,107 @@
+package streak
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+func mustLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatalf("load %s: %v", name, err)
+	}
+	return loc
+}
+
+func TestDay(t *testing.T) {
+	newYork := mustLocation(t, "America/New_York")
+	tokyo := mustLocation(t, "Asia/Tokyo")
+	// Chile moves its clocks forward at midnight, so some days start at 01:00.
+	santiago := mustLocation(t, "America/Santiago")
+
+	tests := []struct {
+		name string
+		t    time.Time
+		loc  *time.Location
+		want time.Time
+	}{
+		{"late evening stays on the local day", time.Date(2026, 3, 10, 3, 30, 0, 0, time.UTC), newYork, time.Date(2026, 3, 9, 0, 0, 0, 0, newYork)},
+		{"same instant is the next day further east", time.Date(2026, 3, 10, 3, 30, 0, 0, time.UTC), tokyo, time.Date(2026, 3, 10, 0, 0, 0, 0, tokyo)},
+		{"day clocks spring forward", time.Date(2026, 3, 8, 12, 0, 0, 0, newYork), newYork, time.Date(2026, 3, 8, 0, 0, 0, 0, newYork)},
+		{"hour repeated when clocks fall back", time.Date(2026, 11, 1, 5, 30, 0, 0, time.UTC), newYork, time.Date(2026, 11, 1, 0, 0, 0, 0, newYork)},
+		{"midnight skipped", time.Date(2026, 9, 6, 12, 0, 0, 0, santiago), santiago, time.Date(2026, 9, 6, 1, 0, 0, 0, santiago)},
+	}
+	for _, tt := range tests {
+		if got := Day(tt.t, tt.loc); !got.Equal(tt.want) {
+			t.Errorf("%s: Day = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDaysBetweenAcrossDST(t *testing.T) {
+	newYork := mustLocation(t, "America/New_York")
+	santiago := mustLocation(t, "America/Santiago")
+
+	tests := []struct {
+		name     string
+		from, to time.Time
+		loc      *time.Location
+		want     int
+	}{
+		{"same day", time.Date(2026, 3, 9, 0, 5, 0, 0, newYork), time.Date(2026, 3, 9, 23, 55, 0, 0, newYork), newYork, 0},
+		{"23 hour day", time.Date(2026, 3, 8, 0, 0, 0, 0, newYork), time.Date(2026, 3, 9, 0, 0, 0, 0, newYork), newYork, 1},
+		{"25 hour day", time.Date(2026, 11, 1, 0, 0, 0, 0, newYork), time.Date(2026, 11, 2, 0, 0, 0, 0, newYork), newYork, 1},
+		{"week over spring forward", time.Date(2026, 3, 5, 23, 0, 0, 0, newYork), time.Date(2026, 3, 12, 0, 30, 0, 0, newYork), newYork, 7},
+		{"midnight skipped", time.Date(2026, 9, 5, 23, 30, 0, 0, santiago), time.Date(2026, 9, 6, 1, 30, 0, 0, santiago), santiago, 1},
+		{"day after midnight skipped", time.Date(2026, 9, 6, 12, 0, 0, 0, santiago), time.Date(2026, 9, 7, 0, 30, 0, 0, santiago), santiago, 1},
+	}
+	for _, tt := range tests {
+		if got := DaysBetween(tt.from, tt.to, tt.loc); got != tt.want {
+			t.Errorf("%s: DaysBetween = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestMissedDays(t *testing.T) {
+	newYork := mustLocation(t, "America/New_York")
+
+	tests := []struct {
+		name            string
+		lastActive, now time.Time
+		loc             *time.Location
+		missed          int
+		lapsed          bool
+	}{
+		{"active today", time.Date(2026, 3, 9, 8, 0, 0, 0, newYork), time.Date(2026, 3, 9, 22, 0, 0, 0, newYork), newYork, 0, false},
+		{"active yesterday", time.Date(2026, 3, 8, 23, 59, 0, 0, newYork), time.Date(2026, 3, 9, 23, 59, 0, 0, newYork), newYork, 0, false},
+		{"one day missed over spring forward", time.Date(2026, 3, 7, 22, 0, 0, 0, newYork), time.Date(2026, 3, 9, 0, 30, 0, 0, newYork), newYork, 1, true},
+		{"two days missed over fall back", time.Date(2026, 10, 31, 12, 0, 0, 0, newYork), time.Date(2026, 11, 3, 0, 30, 0, 0, newYork), newYork, 2, true},
+		{"missed in the user's timezone", time.Date(2026, 3, 10, 3, 30, 0, 0, time.UTC), time.Date(2026, 3, 11, 5, 30, 0, 0, time.UTC), newYork, 1, true},
+		{"not missed in UTC", time.Date(2026, 3, 10, 3, 30, 0, 0, time.UTC), time.Date(2026, 3, 11, 5, 30, 0, 0, time.UTC), time.UTC, 0, false},
+	}
+	for _, tt := range tests {
+		if got := MissedDays(tt.lastActive, tt.now, tt.loc); got != tt.missed {
+			t.Errorf("%s: MissedDays = %d, want %d", tt.name, got, tt.missed)
+		}
+		if got := IsLapsed(tt.lastActive, tt.now, tt.loc); got != tt.lapsed {
+			t.Errorf("%s: IsLapsed = %v, want %v", tt.name, got, tt.lapsed)
+		}
+	}
+
+	if IsLapsed(time.Time{}, time.Now(), time.UTC) {
+		t.Error("a user who was never active has no streak to lapse")
+	}
+}
+
+func TestLocationFallsBackToUTC(t *testing.T) {
+	for _, name := range []string{"", "Mars/Olympus_Mons"} {
+		if got := Location(name); got != time.UTC {
+			t.Errorf("Location(%q) = %v, want UTC", name, got)
+		}
+	}
+	if got := Location("Europe/Warsaw"); got.String() != "Europe/Warsaw" {
+		t.Errorf("Location(Europe/Warsaw) = %v", got)
+	}
+}
@@ -64,6 +64,9 @@ const (
 	// Error message, when two settings have same value
 	ErrSettingsSameKeys		= "ERROR_SAME_SETTING_KEYS"
 
+	// Error message, when a setting is given a value it does not accept
+	ErrInvalidSettingValue	= "ERROR_INVALID_SETTING_VALUE"
+
 )
 
 // ParseDBError parses the provided database error based on the given context.
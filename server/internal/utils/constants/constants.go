@@ -6,6 +6,13 @@ const Hour int = 3600
 const Day int = Hour * 24
 const MaxFileSize = 10 << 20 // 10MB
 
+// activityVisibility controls whether friends see the user's activity in their feed.
+const (
+	SettingActivityVisibility = "activityVisibility"
+	ActivityVisibilityFriends = "friends"
+	ActivityVisibilityPrivate = "private"
+)
+
 var BaseSettings = []dto.UserSetting{
 	{
 		SettingKey: "theme",
@@ -15,4 +22,8 @@ var BaseSettings = []dto.UserSetting{
 		SettingKey: "openSearchBox",
 		SettingValue: "j",
 	},
+	{
+		SettingKey: SettingActivityVisibility,
+		SettingValue: ActivityVisibilityFriends,
+	},
 }
\ No newline at end of file
@@ -0,0 +1,15 @@
+#!/bin/sh
+# Delegates the memory and pids cgroup controllers to the code sandbox, then starts the
+# server. cgroup v2 only lets a cgroup without processes hand controllers to its children, so
+# the container's own processes move to a leaf cgroup first. The server checks the result and
+# refuses to start when the sandbox cannot be set up.
+root=/sys/fs/cgroup
+if mkdir -p "$root/server" "$root/codequest-sandbox" 2>/dev/null &&
+	echo $$ > "$root/server/cgroup.procs" &&
+	echo "+memory +pids" > "$root/cgroup.subtree_control" &&
+	echo "+memory +pids" > "$root/codequest-sandbox/cgroup.subtree_control"; then
+	:
+else
+	echo "sandbox-init: could not delegate cgroup controllers under $root" >&2
+fi
+exec "$@"